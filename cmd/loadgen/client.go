@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// outcome исход запроса для подсчета SLI
+type outcome int
+
+const (
+	outcomeOK       outcome = iota // 2xx
+	outcomeRejected                // ожидаемый отказ бизнес-логики, запрос обработан корректно
+	outcomeFailed                  // 5xx, неожиданный 4xx, таймаут или ошибка соединения
+)
+
+// rejectedCodes коды ошибок, которые возникают при конкурентной нагрузке и не считаются сбоем:
+// PR успели замержить, ревьювера уже заменили или замены в команде нет
+var rejectedCodes = map[string]struct{}{
+	"PR_MERGED":    {},
+	"NOT_ASSIGNED": {},
+	"NO_CANDIDATE": {},
+}
+
+// result итог одного запроса
+type result struct {
+	outcome outcome
+	reason  string // код ошибки, HTTP статус или причина сбоя; пусто для успешных запросов
+	latency time.Duration
+}
+
+// client HTTP клиент сервиса с админским токеном
+type client struct {
+	http  *http.Client
+	addr  string
+	token string
+}
+
+func newClient(addr, token string, timeout time.Duration, conns int) *client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = conns
+	transport.MaxIdleConnsPerHost = conns
+
+	return &client{
+		http:  &http.Client{Timeout: timeout, Transport: transport},
+		addr:  addr,
+		token: token,
+	}
+}
+
+// errorResponse тело ошибки {"error": {"code", "message"}}
+type errorResponse struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// do выполняет запрос и разбирает успешный ответ в out (если out не nil).
+// Задержка включает чтение тела ответа
+func (c *client) do(ctx context.Context, method, path string, body, out any) result {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return result{outcome: outcomeFailed, reason: "encode"}
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.addr+path, reader)
+	if err != nil {
+		return result{outcome: outcomeFailed, reason: "request"}
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	start := time.Now()
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return result{outcome: outcomeFailed, reason: transportReason(err), latency: time.Since(start)}
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	res := result{latency: time.Since(start)}
+	if err != nil {
+		res.outcome, res.reason = outcomeFailed, transportReason(err)
+		return res
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		if out != nil {
+			if err := json.Unmarshal(data, out); err != nil {
+				res.outcome, res.reason = outcomeFailed, "decode"
+				return res
+			}
+		}
+		return res
+	}
+	var e errorResponse
+	_ = json.Unmarshal(data, &e)
+	if _, ok := rejectedCodes[e.Error.Code]; ok && resp.StatusCode == http.StatusConflict {
+		res.outcome, res.reason = outcomeRejected, e.Error.Code
+		return res
+	}
+	res.outcome, res.reason = outcomeFailed, strconv.Itoa(resp.StatusCode)
+	if e.Error.Code != "" {
+		res.reason += " " + e.Error.Code
+	}
+	return res
+}
+
+// transportReason причина сбоя без ответа сервера
+func transportReason(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	var netErr interface{ Timeout() bool }
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return "timeout"
+	}
+	return "transport"
+}
+
+// mustOK возвращает ошибку для неуспешного запроса подготовки данных
+func mustOK(what string, res result) error {
+	if res.outcome == outcomeOK {
+		return nil
+	}
+	return fmt.Errorf("%s: %s", what, res.reason)
+}
@@ -0,0 +1,149 @@
+// Loadgen — нагрузочный генератор сервиса назначения ревьюверов.
+// Создает команды с участниками через /team/add, затем с заданной частотой выполняет смесь операций
+// create/reassign/merge/getReview/stats и печатает перцентили задержек, долю ошибок и выполнение SLI
+// (по умолчанию из quest.md: ответ за 300 мс и 99.9% успешных запросов).
+// Завершается с кодом 1, если SLI не выполнен
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// usage справка по флагам
+const usage = `usage: loadgen [flags]
+
+Создает -teams команд по -users участников и -duration выполняет запросы с частотой -rps.
+Пока нет подходящих открытых PR, reassign и merge заменяются на create.
+Отказы PR_MERGED, NOT_ASSIGNED и NO_CANDIDATE ожидаемы при конкурентной нагрузке и сбоем не считаются,
+запросы, пропущенные из-за лимита -concurrency, считаются неуспешными.
+
+flags:
+`
+
+// config настройки прогона
+type config struct {
+	addr        string
+	token       string
+	teams       int
+	users       int
+	rps         float64
+	duration    time.Duration
+	mix         mix
+	concurrency int
+	timeout     time.Duration
+	seed        uint64
+	prefix      string
+
+	sliLatency           time.Duration
+	sliLatencyPercentile float64
+	sliSuccess           float64
+}
+
+func main() {
+	cfg, err := parseFlags(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ok, err := run(ctx, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "loadgen:", err)
+		os.Exit(1)
+	}
+	if !ok {
+		os.Exit(1)
+	}
+}
+
+// parseFlags разбирает и проверяет флаги
+func parseFlags(args []string) (config, error) {
+	var cfg config
+	var mixFlag string
+	flags := flag.NewFlagSet("loadgen", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&cfg.addr, "addr", "http://localhost:8080", "адрес сервиса")
+	flags.StringVar(&cfg.token, "token", envOr("LOADGEN_TOKEN", ""), "админский токен (или LOADGEN_TOKEN), обязателен")
+	flags.IntVar(&cfg.teams, "teams", 20, "количество команд")
+	flags.IntVar(&cfg.users, "users", 10, "участников в команде")
+	flags.Float64Var(&cfg.rps, "rps", 5, "целевая частота запросов в секунду")
+	flags.DurationVar(&cfg.duration, "duration", time.Minute, "длительность нагрузки")
+	flags.StringVar(&mixFlag, "mix", defaultMix, "веса операций: "+strings.Join(opNames, ", "))
+	flags.IntVar(&cfg.concurrency, "concurrency", 64, "максимум одновременных запросов, сверх него запросы пропускаются")
+	flags.DurationVar(&cfg.timeout, "timeout", 5*time.Second, "таймаут запроса")
+	flags.Uint64Var(&cfg.seed, "seed", 1, "seed последовательности операций")
+	flags.StringVar(&cfg.prefix, "prefix", fmt.Sprintf("lg%d", time.Now().Unix()), "префикс команд, пользователей и PR")
+	flags.DurationVar(&cfg.sliLatency, "sli-latency", 300*time.Millisecond, "SLI времени ответа")
+	flags.Float64Var(&cfg.sliLatencyPercentile, "sli-latency-percentile", 99.9, "перцентиль, который должен уложиться в -sli-latency")
+	flags.Float64Var(&cfg.sliSuccess, "sli-success", 99.9, "SLI успешности, %")
+	if err := flags.Parse(args); err != nil {
+		return cfg, err
+	}
+	if flags.NArg() > 0 {
+		return cfg, fmt.Errorf("unexpected arguments: %s", strings.Join(flags.Args(), " "))
+	}
+
+	var errs []error
+	if cfg.token == "" {
+		errs = append(errs, errors.New("-token or LOADGEN_TOKEN is required"))
+	}
+	if cfg.teams <= 0 || cfg.users <= 0 {
+		errs = append(errs, errors.New("-teams and -users must be positive"))
+	}
+	if cfg.rps <= 0 || cfg.duration <= 0 || cfg.concurrency <= 0 || cfg.timeout <= 0 {
+		errs = append(errs, errors.New("-rps, -duration, -concurrency and -timeout must be positive"))
+	}
+	if cfg.sliLatencyPercentile <= 0 || cfg.sliLatencyPercentile > 100 || cfg.sliSuccess <= 0 || cfg.sliSuccess > 100 {
+		errs = append(errs, errors.New("-sli-latency-percentile and -sli-success must be in (0, 100]"))
+	}
+	cfg.addr = strings.TrimRight(cfg.addr, "/")
+	m, err := parseMix(mixFlag)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	cfg.mix = m
+	return cfg, errors.Join(errs...)
+}
+
+// run готовит данные, выполняет нагрузку и печатает отчет; возвращает, выполнены ли SLI
+func run(ctx context.Context, cfg config) (bool, error) {
+	g := newGenerator(cfg)
+	if err := g.ready(ctx); err != nil {
+		return false, fmt.Errorf("service is not ready: %w", err)
+	}
+	fmt.Printf("seeding %d teams x %d users with prefix %s\n", cfg.teams, cfg.users, cfg.prefix)
+	if err := g.seed(ctx); err != nil {
+		return false, fmt.Errorf("seed: %w", err)
+	}
+	fmt.Printf("running %.1f rps for %s against %s\n\n", cfg.rps, cfg.duration, cfg.addr)
+	elapsed := g.drive(ctx)
+
+	rep := g.rec.report(cfg, elapsed)
+	rep.print(os.Stdout)
+	return rep.latencyOK() && rep.successOK(), nil
+}
+
+// envOr значение переменной окружения или def
+func envOr(name, def string) string {
+	if v, ok := os.LookupEnv(name); ok {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// percentiles перцентили задержки в отчете
+var percentiles = []float64{50, 90, 95, 99, 99.9}
+
+// opStat результаты одной операции
+type opStat struct {
+	latencies []time.Duration
+	ok        int
+	rejected  map[string]int // код ошибки -> количество
+	failed    map[string]int // причина -> количество
+	skipped   int            // не отправлены: достигнут лимит одновременных запросов
+}
+
+func newOpStat() *opStat {
+	return &opStat{rejected: map[string]int{}, failed: map[string]int{}}
+}
+
+func (s *opStat) add(res result) {
+	s.latencies = append(s.latencies, res.latency)
+	switch res.outcome {
+	case outcomeOK:
+		s.ok++
+	case outcomeRejected:
+		s.rejected[res.reason]++
+	case outcomeFailed:
+		s.failed[res.reason]++
+	}
+}
+
+func (s *opStat) merge(other *opStat) {
+	s.latencies = append(s.latencies, other.latencies...)
+	s.ok += other.ok
+	s.skipped += other.skipped
+	for k, v := range other.rejected {
+		s.rejected[k] += v
+	}
+	for k, v := range other.failed {
+		s.failed[k] += v
+	}
+}
+
+func (s *opStat) count() int {
+	return len(s.latencies)
+}
+
+func (s *opStat) failures() int {
+	return sum(s.failed)
+}
+
+// successRate доля запросов без сбоя, %. Пропущенные запросы считаются неуспешными:
+// их не отправили, потому что сервис не успевал отвечать
+func (s *opStat) successRate() float64 {
+	total := s.count() + s.skipped
+	if total == 0 {
+		return 100
+	}
+	return 100 * float64(s.count()-s.failures()) / float64(total)
+}
+
+// percentile задержка по методу ближайшего ранга; latencies должны быть отсортированы
+func (s *opStat) percentile(p float64) time.Duration {
+	if len(s.latencies) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(s.latencies))))
+	return s.latencies[max(rank, 1)-1]
+}
+
+// recorder собирает результаты запросов из горутин нагрузки
+type recorder struct {
+	mu  sync.Mutex
+	ops map[string]*opStat
+}
+
+func newRecorder() *recorder {
+	return &recorder{ops: map[string]*opStat{}}
+}
+
+func (r *recorder) stats(op string) *opStat {
+	s, ok := r.ops[op]
+	if !ok {
+		s = newOpStat()
+		r.ops[op] = s
+	}
+	return s
+}
+
+func (r *recorder) add(op string, res result) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats(op).add(res)
+}
+
+func (r *recorder) skip(op string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats(op).skipped++
+}
+
+// report итог прогона
+type report struct {
+	cfg     config
+	elapsed time.Duration
+	ops     map[string]*opStat
+	total   *opStat
+}
+
+// report сортирует задержки и считает итог по всем операциям
+func (r *recorder) report(cfg config, elapsed time.Duration) *report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rep := &report{cfg: cfg, elapsed: elapsed, ops: r.ops, total: newOpStat()}
+	for _, s := range r.ops {
+		slices.Sort(s.latencies)
+		rep.total.merge(s)
+	}
+	slices.Sort(rep.total.latencies)
+	return rep
+}
+
+// latencyOK выполнен ли SLI времени ответа
+func (r *report) latencyOK() bool {
+	return r.total.count() > 0 && r.total.percentile(r.cfg.sliLatencyPercentile) <= r.cfg.sliLatency
+}
+
+// successOK выполнен ли SLI успешности
+func (r *report) successOK() bool {
+	return r.total.count() > 0 && r.total.successRate() >= r.cfg.sliSuccess
+}
+
+// print выводит таблицу по операциям, разбивку ошибок и результат SLI
+func (r *report) print(w io.Writer) {
+	achieved := 0.0
+	if r.elapsed > 0 {
+		achieved = float64(r.total.count()) / r.elapsed.Seconds()
+	}
+	fmt.Fprintf(w, "target %.1f rps for %s, achieved %.1f rps: %d requests, %d skipped\n\n",
+		r.cfg.rps, r.cfg.duration, achieved, r.total.count(), r.total.skipped)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	header := []string{"op", "count", "ok", "rejected", "failed", "success%"}
+	for _, p := range percentiles {
+		header = append(header, "p"+formatFloat(p))
+	}
+	header = append(header, "max")
+	fmt.Fprintln(tw, strings.Join(header, "\t")+"\t")
+	for _, op := range opNames {
+		if s, ok := r.ops[op]; ok {
+			printRow(tw, op, s)
+		}
+	}
+	printRow(tw, "total", r.total)
+	_ = tw.Flush()
+
+	if len(r.total.rejected) > 0 {
+		fmt.Fprintf(w, "\nrejected (expected under concurrent load): %s\n", formatCounts(r.total.rejected))
+	}
+	if len(r.total.failed) > 0 {
+		fmt.Fprintf(w, "\nfailed: %s\n", formatCounts(r.total.failed))
+	}
+
+	fmt.Fprintf(w, "\nSLI latency: p%s = %s, target <= %s: %s\n",
+		formatFloat(r.cfg.sliLatencyPercentile), formatDuration(r.total.percentile(r.cfg.sliLatencyPercentile)),
+		r.cfg.sliLatency, passFail(r.latencyOK()))
+	fmt.Fprintf(w, "SLI success: %.3f%%, target >= %s%%: %s\n",
+		r.total.successRate(), formatFloat(r.cfg.sliSuccess), passFail(r.successOK()))
+}
+
+func printRow(w io.Writer, op string, s *opStat) {
+	row := []string{
+		op, fmt.Sprint(s.count()), fmt.Sprint(s.ok), fmt.Sprint(sum(s.rejected)), fmt.Sprint(s.failures()),
+		fmt.Sprintf("%.3f", s.successRate()),
+	}
+	for _, p := range percentiles {
+		row = append(row, formatDuration(s.percentile(p)))
+	}
+	row = append(row, formatDuration(s.percentile(100)))
+	fmt.Fprintln(w, strings.Join(row, "\t")+"\t")
+}
+
+// formatCounts выводит счетчики по убыванию: "NO_CANDIDATE=3, PR_MERGED=1"
+func formatCounts(counts map[string]int) string {
+	keys := slices.SortedFunc(maps.Keys(counts), func(a, b string) int {
+		if counts[a] != counts[b] {
+			return counts[b] - counts[a]
+		}
+		return strings.Compare(a, b)
+	})
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%d", k, counts[k]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatDuration(d time.Duration) string {
+	return d.Round(10 * time.Microsecond).String()
+}
+
+func formatFloat(f float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.3f", f), "0"), ".")
+}
+
+func passFail(ok bool) string {
+	if ok {
+		return "PASS"
+	}
+	return "FAIL"
+}
+
+func sum(counts map[string]int) int {
+	n := 0
+	for _, v := range counts {
+		n += v
+	}
+	return n
+}
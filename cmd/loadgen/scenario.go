@@ -0,0 +1,291 @@
+package main
+
+import (
+	"avito-test-quest/internal/models"
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// операции нагрузки
+const (
+	opCreate    = "create"
+	opReassign  = "reassign"
+	opMerge     = "merge"
+	opGetReview = "getReview"
+	opStats     = "stats"
+)
+
+// opNames операции в порядке вывода в отчете
+var opNames = []string{opCreate, opReassign, opMerge, opGetReview, opStats}
+
+// defaultMix доли операций по умолчанию: PR создаются чаще, чем мержатся, чтобы пул открытых PR рос
+const defaultMix = "create=30,reassign=20,merge=15,getReview=25,stats=10"
+
+// weighted операция с весом в смеси
+type weighted struct {
+	op     string
+	weight int
+}
+
+// mix смесь операций
+type mix struct {
+	ops   []weighted
+	total int
+}
+
+// parseMix разбирает смесь вида "create=30,reassign=20"; не указанные операции не выполняются
+func parseMix(s string) (mix, error) {
+	var m mix
+	seen := map[string]struct{}{}
+	for _, part := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return mix{}, fmt.Errorf("mix entry %q: expected op=weight", part)
+		}
+		known := false
+		for _, op := range opNames {
+			known = known || op == name
+		}
+		if !known {
+			return mix{}, fmt.Errorf("mix entry %q: unknown op, expected one of %s", part, strings.Join(opNames, ", "))
+		}
+		if _, dup := seen[name]; dup {
+			return mix{}, fmt.Errorf("mix entry %q: duplicated op", part)
+		}
+		seen[name] = struct{}{}
+		weight, err := strconv.Atoi(value)
+		if err != nil || weight < 0 {
+			return mix{}, fmt.Errorf("mix entry %q: weight must be a non-negative integer", part)
+		}
+		m.ops = append(m.ops, weighted{op: name, weight: weight})
+		m.total += weight
+	}
+	if m.total == 0 {
+		return mix{}, fmt.Errorf("mix %q: total weight must be positive", s)
+	}
+	return m, nil
+}
+
+// pick выбирает операцию пропорционально весам
+func (m mix) pick(rnd *rand.Rand) string {
+	n := rnd.IntN(m.total)
+	for _, w := range m.ops {
+		if n < w.weight {
+			return w.op
+		}
+		n -= w.weight
+	}
+	return m.ops[len(m.ops)-1].op
+}
+
+// openPRs открытые PR, созданные генератором, с текущими ревьюверами
+type openPRs struct {
+	mu        sync.Mutex
+	ids       []string
+	index     map[string]int
+	reviewers map[string][]string
+}
+
+func newOpenPRs() *openPRs {
+	return &openPRs{index: map[string]int{}, reviewers: map[string][]string{}}
+}
+
+// add добавляет созданный PR
+func (p *openPRs) add(id string, reviewers []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.index[id] = len(p.ids)
+	p.ids = append(p.ids, id)
+	p.reviewers[id] = reviewers
+}
+
+// update обновляет ревьюверов PR, если его еще не забрали на мерж
+func (p *openPRs) update(id string, reviewers []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.index[id]; ok {
+		p.reviewers[id] = reviewers
+	}
+}
+
+// withReviewer выбирает случайный PR и его ревьювера
+func (p *openPRs) withReviewer(rnd *rand.Rand) (string, string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// PR без ревьюверов пропускаем, ограничивая число попыток
+	for range min(len(p.ids), 8) {
+		id := p.ids[rnd.IntN(len(p.ids))]
+		if rs := p.reviewers[id]; len(rs) > 0 {
+			return id, rs[rnd.IntN(len(rs))], true
+		}
+	}
+	return "", "", false
+}
+
+// take забирает случайный PR из пула
+func (p *openPRs) take(rnd *rand.Rand) (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.ids) == 0 {
+		return "", false
+	}
+	i := rnd.IntN(len(p.ids))
+	id := p.ids[i]
+	last := p.ids[len(p.ids)-1]
+	p.ids[i], p.index[last] = last, i
+	p.ids = p.ids[:len(p.ids)-1]
+	delete(p.index, id)
+	delete(p.reviewers, id)
+	return id, true
+}
+
+// generator состояние нагрузки
+type generator struct {
+	cfg    config
+	client *client
+	rec    *recorder
+	users  []string
+	prs    *openPRs
+	prSeq  atomic.Int64
+}
+
+func newGenerator(cfg config) *generator {
+	return &generator{
+		cfg:    cfg,
+		client: newClient(cfg.addr, cfg.token, cfg.timeout, cfg.concurrency),
+		rec:    newRecorder(),
+		prs:    newOpenPRs(),
+	}
+}
+
+// ready проверяет, что сервис готов принимать запросы
+func (g *generator) ready(ctx context.Context) error {
+	return mustOK("GET /health/ready", g.client.do(ctx, http.MethodGet, "/health/ready", nil, nil))
+}
+
+// seed создает команды с участниками; запросы подготовки не входят в отчет
+func (g *generator) seed(ctx context.Context) error {
+	for i := range g.cfg.teams {
+		team := models.CreateTeamInput{TeamName: fmt.Sprintf("%s-team-%d", g.cfg.prefix, i)}
+		for j := range g.cfg.users {
+			id := fmt.Sprintf("%s-u%d-%d", g.cfg.prefix, i, j)
+			team.Members = append(team.Members, models.TeamMember{UserID: id, Username: id, IsActive: true})
+			g.users = append(g.users, id)
+		}
+		if err := mustOK("POST /team/add "+team.TeamName, g.client.do(ctx, http.MethodPost, "/team/add", team, nil)); err != nil {
+			return fmt.Errorf("%w (use another -prefix if the team already exists)", err)
+		}
+	}
+	return nil
+}
+
+// drive отправляет запросы с постоянной частотой до истечения duration или отмены ctx.
+// Нагрузка открытая: запрос отправляется по расписанию, не дожидаясь ответа на предыдущий
+func (g *generator) drive(ctx context.Context) time.Duration {
+	rnd := rand.New(rand.NewPCG(g.cfg.seed, 0)) //nolint:gosec // воспроизводимая последовательность операций
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / g.cfg.rps))
+	defer ticker.Stop()
+	deadline := time.NewTimer(g.cfg.duration)
+	defer deadline.Stop()
+
+	// отправленные запросы завершаются и после остановки, чтобы не считать отмену сбоем
+	reqCtx := context.WithoutCancel(ctx)
+	inflight := make(chan struct{}, g.cfg.concurrency)
+	var wg sync.WaitGroup
+	start := time.Now()
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break loop
+		case <-deadline.C:
+			break loop
+		case <-ticker.C:
+			op := g.cfg.mix.pick(rnd)
+			select {
+			case inflight <- struct{}{}:
+			default:
+				g.rec.skip(op)
+				continue
+			}
+			// у каждого запроса свой генератор: выбор аргументов не зависит от порядка ответов
+			reqRnd := rand.New(rand.NewPCG(rnd.Uint64(), rnd.Uint64())) //nolint:gosec // см. выше
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-inflight }()
+				g.run(reqCtx, op, reqRnd)
+			}()
+		}
+	}
+	elapsed := time.Since(start)
+	wg.Wait()
+	return elapsed
+}
+
+// run выполняет операцию; reassign и merge без подходящих PR заменяются на create
+func (g *generator) run(ctx context.Context, op string, rnd *rand.Rand) {
+	switch op {
+	case opReassign:
+		if id, reviewer, ok := g.prs.withReviewer(rnd); ok {
+			g.reassign(ctx, id, reviewer)
+			return
+		}
+	case opMerge:
+		if id, ok := g.prs.take(rnd); ok {
+			g.merge(ctx, id)
+			return
+		}
+	case opGetReview:
+		g.getReview(ctx, g.users[rnd.IntN(len(g.users))])
+		return
+	case opStats:
+		g.rec.add(opStats, g.client.do(ctx, http.MethodGet, "/stats", nil, nil))
+		return
+	}
+	g.create(ctx, g.users[rnd.IntN(len(g.users))])
+}
+
+// prResponse ответ ручек PR {"pr": {...}}
+type prResponse struct {
+	PR models.PullRequest `json:"pr"`
+}
+
+func (g *generator) create(ctx context.Context, author string) {
+	id := fmt.Sprintf("%s-pr-%d", g.cfg.prefix, g.prSeq.Add(1))
+	input := models.CreatePullRequestInput{PullRequestID: id, PullRequestName: "load " + id, AuthorID: author}
+	var out prResponse
+	res := g.client.do(ctx, http.MethodPost, "/pullRequest/create", input, &out)
+	g.rec.add(opCreate, res)
+	if res.outcome == outcomeOK {
+		g.prs.add(id, out.PR.AssignedReviewers)
+	}
+}
+
+func (g *generator) reassign(ctx context.Context, id, reviewer string) {
+	input := models.ReassignReviewerInput{PullRequestID: id, OldReviewerID: reviewer}
+	var out prResponse
+	res := g.client.do(ctx, http.MethodPost, "/pullRequest/reassign", input, &out)
+	g.rec.add(opReassign, res)
+	if res.outcome == outcomeOK {
+		g.prs.update(id, out.PR.AssignedReviewers)
+	}
+}
+
+func (g *generator) merge(ctx context.Context, id string) {
+	input := models.MergePullRequestInput{PullRequestID: id}
+	g.rec.add(opMerge, g.client.do(ctx, http.MethodPost, "/pullRequest/merge", input, nil))
+}
+
+func (g *generator) getReview(ctx context.Context, userID string) {
+	path := "/users/getReview?user_id=" + url.QueryEscape(userID)
+	g.rec.add(opGetReview, g.client.do(ctx, http.MethodGet, path, nil, nil))
+}
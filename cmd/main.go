@@ -4,28 +4,182 @@ import (
 	"avito-test-quest/internal/app"
 	"avito-test-quest/internal/config"
 	"avito-test-quest/internal/logger"
+	"avito-test-quest/internal/postgres"
+	"avito-test-quest/internal/sqlite"
 	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"os"
+	"strconv"
 
 	"go.uber.org/zap"
 )
 
+// usage справка по командам
+const usage = `usage:
+  avito-service [--config path] [serve]             запустить HTTP сервер (по умолчанию)
+  avito-service [--config path] migrate up          применить все миграции
+  avito-service [--config path] migrate down [N]    откатить N последних миграций (по умолчанию 1)
+  avito-service [--config path] migrate version     показать версию схемы
+  avito-service [--config path] migrate force V     записать версию V без выполнения миграций и снять dirty
+
+Путь к конфигурации также задается переменной CONFIG_PATH, любая настройка — переменной окружения
+или файлом через NAME_FILE (см. README).
+`
+
+// errUsage неверные аргументы командной строки
+var errUsage = errors.New("invalid arguments")
+
 func main() {
 	ctx := context.Background()
-	cfg, err := config.New()
 
+	flags := flag.NewFlagSet("avito-service", flag.ContinueOnError)
+	flags.Usage = func() { fmt.Fprint(flags.Output(), usage) }
+	configPath := flags.String("config", "", "путь к config.yaml")
+	if err := flags.Parse(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		os.Exit(2)
+	}
+	args := flags.Args()
+	if len(args) > 0 && args[0] == "help" {
+		fmt.Print(usage)
+		return
+	}
+
+	cfg, err := config.New(*configPath)
 	if err != nil {
+		// отчет о невалидной конфигурации многострочный, выводим его как есть
+		var verr *config.ValidationError
+		if errors.As(err, &verr) {
+			fmt.Fprintln(os.Stderr, verr.Error())
+			os.Exit(1)
+		}
 		logger.GetOrCreateLoggerFromCtx(ctx).Fatal(ctx, "failed to load config", zap.Error(err))
 	}
 
+	cmd := "serve"
+	if len(args) > 0 {
+		cmd, args = args[0], args[1:]
+	}
+	switch cmd {
+	case "serve":
+		err = serve(ctx, cfg)
+	case "migrate":
+		err = runMigrate(ctx, cfg, args)
+	default:
+		err = fmt.Errorf("%w: unknown command %q", errUsage, cmd)
+	}
+	if errors.Is(err, errUsage) {
+		fmt.Fprintf(os.Stderr, "%v\n\n%s", err, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		logger.GetOrCreateLoggerFromCtx(ctx).Fatal(ctx, "command failed", zap.String("command", cmd), zap.Error(err))
+	}
+}
+
+// serve запускает HTTP сервер до получения сигнала остановки
+func serve(ctx context.Context, cfg *config.Config) error {
 	application, err := app.New(ctx, cfg)
 	if err != nil {
-		logger.GetOrCreateLoggerFromCtx(ctx).Fatal(ctx, "failed to initialize application", zap.Error(err))
-		os.Exit(1)
+		return fmt.Errorf("failed to initialize application: %w", err)
+	}
+
+	return application.Run(ctx)
+}
+
+// runMigrate выполняет подкоманду migrate отдельно от запуска сервиса
+func runMigrate(ctx context.Context, cfg *config.Config, args []string) error {
+	action, err := parseMigrate(args)
+	if err != nil {
+		return err
+	}
+	if cfg.Storage.Driver == config.StorageDriverMemory {
+		return fmt.Errorf("migrations apply to postgres and sqlite only, storage.driver is %q", cfg.Storage.Driver)
+	}
+	ctx, log, err := logger.New(ctx, cfg.Log)
+	if err != nil {
+		return fmt.Errorf("failed to init logger: %w", err)
 	}
 
-	err = application.Run(ctx)
+	mg, err := newMigrator(ctx, cfg)
 	if err != nil {
-		logger.GetOrCreateLoggerFromCtx(ctx).Fatal(ctx, "failed with app", zap.Error(err))
+		return err
+	}
+	defer func() { _ = mg.Close() }()
+
+	if err := action(mg); err != nil {
+		return fmt.Errorf("migrate %s: %w", args[0], err)
+	}
+	version, dirty, err := mg.Version()
+	if err != nil {
+		return fmt.Errorf("failed to read migration version: %w", err)
+	}
+	log.Info(ctx, "migrate "+args[0]+" completed", zap.Uint("version", version), zap.Bool("dirty", dirty))
+	fmt.Printf("version %d, dirty %t\n", version, dirty)
+	return nil
+}
+
+// migrator команды migrate, общие для миграций Postgres и SQLite
+type migrator interface {
+	Up() error
+	Down(steps int) error
+	Version() (version uint, dirty bool, err error)
+	Force(version int) error
+	Close() error
+}
+
+// newMigrator открывает миграции хранилища из storage.driver
+func newMigrator(ctx context.Context, cfg *config.Config) (migrator, error) {
+	if cfg.Storage.Driver == config.StorageDriverSQLite {
+		return sqlite.NewMigrator(cfg.SQLite)
+	}
+	return postgres.NewMigrator(ctx, cfg.Postgres)
+}
+
+// parseMigrate разбирает аргументы migrate до подключения к БД
+func parseMigrate(args []string) (func(mg migrator) error, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("%w: migrate requires up, down, version or force", errUsage)
+	}
+	switch sub, rest := args[0], args[1:]; sub {
+	case "up":
+		if len(rest) != 0 {
+			return nil, fmt.Errorf("%w: migrate up takes no arguments", errUsage)
+		}
+		return migrator.Up, nil
+	case "down":
+		if len(rest) > 1 {
+			return nil, fmt.Errorf("%w: migrate down takes at most one argument", errUsage)
+		}
+		steps := 1
+		if len(rest) == 1 {
+			n, err := strconv.Atoi(rest[0])
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("%w: migrate down expects a positive number of steps, got %q", errUsage, rest[0])
+			}
+			steps = n
+		}
+		return func(mg migrator) error { return mg.Down(steps) }, nil
+	case "version":
+		if len(rest) != 0 {
+			return nil, fmt.Errorf("%w: migrate version takes no arguments", errUsage)
+		}
+		// версия выводится после любой команды
+		return func(migrator) error { return nil }, nil
+	case "force":
+		if len(rest) != 1 {
+			return nil, fmt.Errorf("%w: migrate force requires a version", errUsage)
+		}
+		version, err := strconv.Atoi(rest[0])
+		if err != nil || version < -1 {
+			return nil, fmt.Errorf("%w: migrate force expects a version number, got %q", errUsage, rest[0])
+		}
+		return func(mg migrator) error { return mg.Force(version) }, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown migrate command %q", errUsage, sub)
 	}
 }
@@ -1,13 +1,21 @@
 package app
 
 import (
+	"avito-test-quest/internal/auth"
 	"avito-test-quest/internal/config"
 	"avito-test-quest/internal/handler"
+	"avito-test-quest/internal/health"
 	"avito-test-quest/internal/logger"
+	"avito-test-quest/internal/metrics"
 	"avito-test-quest/internal/postgres"
 	"avito-test-quest/internal/repository"
+	"avito-test-quest/internal/repository/memory"
+	sqliterepo "avito-test-quest/internal/repository/sqlite"
 	"avito-test-quest/internal/service"
+	"avito-test-quest/internal/sqlite"
+	"avito-test-quest/internal/tracing"
 	"context"
+	"database/sql"
 	"fmt"
 	"os"
 	"os/signal"
@@ -15,6 +23,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/multitracer"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 
@@ -23,37 +32,61 @@ import (
 
 // App представляет основное приложение
 type App struct {
-	config *config.Config
-	log    *logger.Logger
-	pool   *pgxpool.Pool
-	server *http.Server
+	config          *config.Config
+	log             *logger.Logger
+	storage         storage
+	server          *http.Server
+	health          *health.Checker
+	shutdownTracing func(context.Context) error
 }
 
 // New инициализирует приложение
 func New(ctx context.Context, cfg *config.Config) (*App, error) {
-	ctx, _, err := logger.New(ctx)
+	ctx, _, err := logger.New(ctx, cfg.Log)
 	if err != nil {
 		return nil, fmt.Errorf("failed to init logger: %w", err)
 	}
 
 	log := logger.GetLoggerFromCtx(ctx)
 
-	pool, err := postgres.New(ctx, cfg.Postgres)
+	shutdownTracing, err := tracing.New(ctx, cfg.Tracing)
 	if err != nil {
-		return nil, fmt.Errorf("failed to init postgres: %w", err)
+		return nil, fmt.Errorf("failed to init tracing: %w", err)
 	}
 
-	err = postgres.Migrate(ctx, cfg.Postgres)
+	m := metrics.New()
+	store, err := newStorage(ctx, cfg, m)
 	if err != nil {
-		return nil, fmt.Errorf("failed to migrate postgres: %w", err)
+		return nil, err
 	}
 
-	prRepo := repository.NewPrRepository(pool)
-	prService := service.NewPrService(prRepo)
+	prRepo := repository.NewTracedRepository(store.repo)
+	selector, err := service.NewReviewerSelector(cfg.Assignment.Strategy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init reviewer selector: %w", err)
+	}
+	prService := service.NewTracedService(service.NewPrService(prRepo, selector, m))
 
-	router := gin.Default()
+	// вместо gin.Default: журнал запросов и восстановление после паники пишут в zap.
+	// RequestID первым кладет в контекст идентификатор и логгер запроса, Tracing — спан запроса
+	router := gin.New()
+	router.Use(handler.RequestID(log), handler.Tracing(), handler.AccessLog(), handler.Metrics(m), handler.Recovery())
+	router.GET("/metrics", gin.WrapH(m.Handler()))
 
-	httpHandler := handler.NewPrHandler(prService, router)
+	authn, err := newAuthenticator(ctx, cfg.Auth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init authentication: %w", err)
+	}
+	if len(cfg.Auth.AdminTokens) == 0 && !cfg.Auth.JWT.Enabled() {
+		log.Warn(ctx, "no admin tokens or jwt configured, admin endpoints will reject all requests")
+	}
+
+	checker, err := newChecker(store)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init readiness check: %w", err)
+	}
+
+	httpHandler := handler.NewPrHandler(prService, router, authn, checker)
 
 	httpHandler.InitRoutes()
 
@@ -67,17 +100,110 @@ func New(ctx context.Context, cfg *config.Config) (*App, error) {
 	}
 
 	return &App{
-		config: cfg,
-		log:    log,
-		pool:   pool,
-		server: srv,
+		config:          cfg,
+		log:             log,
+		storage:         store,
+		server:          srv,
+		health:          checker,
+		shutdownTracing: shutdownTracing,
 	}, nil
 }
 
+// newChecker создает проверку готовности выбранного хранилища:
+// схема сравнивается с последней встроенной миграцией своего драйвера
+func newChecker(store storage) (*health.Checker, error) {
+	switch {
+	case store.pool != nil:
+		expected, err := postgres.LatestMigrationVersion()
+		if err != nil {
+			return nil, err
+		}
+		return health.NewChecker(postgres.DB{Pool: store.pool}, expected), nil
+	case store.db != nil:
+		expected, err := sqlite.LatestMigrationVersion()
+		if err != nil {
+			return nil, err
+		}
+		return health.NewChecker(sqlite.DB{DB: store.db}, expected), nil
+	}
+	return health.NewChecker(nil, 0), nil
+}
+
+// storage выбранное хранилище и соединения, которые закрываются при остановке
+type storage struct {
+	repo repository.Repository
+	pool *pgxpool.Pool // только для Postgres
+	db   *sql.DB       // только для SQLite
+}
+
+// newStorage создает хранилище, выбранное в storage.driver.
+// Для Postgres и SQLite открывает БД и применяет миграции; хранилищу в памяти соединения не нужны
+func newStorage(ctx context.Context, cfg *config.Config, m *metrics.Metrics) (storage, error) {
+	log := logger.GetLoggerFromCtx(ctx)
+
+	switch cfg.Storage.Driver {
+	case config.StorageDriverMemory:
+		log.Warn(ctx, "using in-memory storage, data will be lost on restart")
+		return storage{repo: memory.New()}, nil
+	case config.StorageDriverSQLite:
+		if cfg.SQLite.SkipMigrations {
+			log.Info(ctx, "skipping migrations on startup, schema is managed by the migrate command")
+		} else if err := sqlite.Migrate(ctx, cfg.SQLite); err != nil {
+			return storage{}, fmt.Errorf("failed to migrate sqlite: %w", err)
+		}
+		db, err := sqlite.New(ctx, cfg.SQLite)
+		if err != nil {
+			return storage{}, fmt.Errorf("failed to init sqlite: %w", err)
+		}
+		log.Info(ctx, "using sqlite storage", zap.String("path", cfg.SQLite.Path))
+		return storage{repo: sqliterepo.NewRepository(db), db: db}, nil
+	}
+
+	pool, err := postgres.New(ctx, cfg.Postgres, multitracer.New(metrics.NewQueryTracer(m), tracing.NewQueryTracer()))
+	if err != nil {
+		return storage{}, fmt.Errorf("failed to init postgres: %w", err)
+	}
+	if err := m.Register(metrics.NewPoolCollector(pool)); err != nil {
+		return storage{}, fmt.Errorf("failed to register pool metrics: %w", err)
+	}
+
+	if cfg.Postgres.SkipMigrations {
+		log.Info(ctx, "skipping migrations on startup, schema is managed by the migrate command")
+	} else if err := postgres.Migrate(ctx, cfg.Postgres); err != nil {
+		return storage{}, fmt.Errorf("failed to migrate postgres: %w", err)
+	}
+
+	return storage{repo: repository.NewPrRepository(pool), pool: pool}, nil
+}
+
+// newAuthenticator собирает проверку статических токенов и, если настроен, JWT от SSO
+func newAuthenticator(ctx context.Context, cfg config.AuthConfig) (auth.Authenticator, error) {
+	chain := auth.Chain{auth.NewStaticTokens(cfg.AdminTokens, cfg.UserTokens)}
+	if cfg.JWT.Enabled() {
+		verifier, err := auth.NewJWTVerifier(ctx, cfg.JWT)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, verifier)
+	}
+	return chain, nil
+}
+
 // Shutdown выполняет graceful shutdown приложения
 func (a *App) Shutdown(ctx context.Context) error {
 	a.log.Info(ctx, "starting graceful shutdown...")
 
+	// сначала readiness начинает отказывать, и балансировщик перестает слать новые запросы,
+	// затем сервер дожидается завершения текущих
+	a.health.SetShuttingDown()
+	if delay := a.config.PR.DrainDelay; delay > 0 {
+		a.log.Info(ctx, "readiness switched to shutting_down, draining", zap.Duration("delay", delay))
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+		}
+	}
+
 	shutdownCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
@@ -87,8 +213,22 @@ func (a *App) Shutdown(ctx context.Context) error {
 	}
 	a.log.Info(ctx, "HTTP server shutdown successfully")
 
-	a.pool.Close()
-	a.log.Info(ctx, "database pool closed successfully")
+	if a.storage.pool != nil {
+		a.storage.pool.Close()
+		a.log.Info(ctx, "database pool closed successfully")
+	}
+	if a.storage.db != nil {
+		if err := a.storage.db.Close(); err != nil {
+			a.log.Error(ctx, "failed to close sqlite", zap.Error(err))
+		} else {
+			a.log.Info(ctx, "sqlite closed successfully")
+		}
+	}
+
+	// выгружаем оставшиеся спаны после завершения запросов
+	if err := a.shutdownTracing(shutdownCtx); err != nil {
+		a.log.Error(ctx, "failed to flush traces", zap.Error(err))
+	}
 
 	a.log.Info(ctx, "graceful shutdown completed")
 	return nil
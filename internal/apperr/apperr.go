@@ -0,0 +1,90 @@
+package apperr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Code код доменной ошибки, передается клиенту в поле error.code (см. openapi.yml)
+type Code string
+
+// коды доменных ошибок
+const (
+	CodeTeamExists          Code = "TEAM_EXISTS"
+	CodePRExists            Code = "PR_EXISTS"
+	CodePRMerged            Code = "PR_MERGED"
+	CodeNotAssigned         Code = "NOT_ASSIGNED"
+	CodeNoCandidate         Code = "NO_CANDIDATE"
+	CodeNotFound            Code = "NOT_FOUND"
+	CodeInvalidStrategy     Code = "INVALID_STRATEGY"
+	CodeInvalidFallbackTeam Code = "INVALID_FALLBACK_TEAM"
+	CodeInvalidRequest      Code = "INVALID_REQUEST"
+	CodeUnauthorized        Code = "UNAUTHORIZED"
+	CodeForbidden           Code = "FORBIDDEN"
+	CodeInternal            Code = "INTERNAL"
+)
+
+// эталонные ошибки для сравнения через errors.Is: совпадение определяется только кодом
+var (
+	ErrTeamExists          = &Error{Code: CodeTeamExists}
+	ErrPRExists            = &Error{Code: CodePRExists}
+	ErrPRMerged            = &Error{Code: CodePRMerged}
+	ErrNotAssigned         = &Error{Code: CodeNotAssigned}
+	ErrNoCandidate         = &Error{Code: CodeNoCandidate}
+	ErrNotFound            = &Error{Code: CodeNotFound}
+	ErrInvalidStrategy     = &Error{Code: CodeInvalidStrategy}
+	ErrInvalidFallbackTeam = &Error{Code: CodeInvalidFallbackTeam}
+	ErrInvalidRequest      = &Error{Code: CodeInvalidRequest}
+	ErrUnauthorized        = &Error{Code: CodeUnauthorized}
+	ErrForbidden           = &Error{Code: CodeForbidden}
+	ErrInternal            = &Error{Code: CodeInternal}
+)
+
+// Error доменная ошибка: код, сообщение для клиента и исходная причина
+type Error struct {
+	Code    Code
+	Message string
+	Err     error // причина, клиенту не передается
+}
+
+// New создает доменную ошибку без причины
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Wrap создает доменную ошибку с исходной причиной
+func Wrap(err error, code Code, message string) *Error {
+	return &Error{Code: code, Message: message, Err: err}
+}
+
+// Error возвращает код, сообщение и причину
+func (e *Error) Error() string {
+	msg := string(e.Code)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.Err != nil {
+		msg = fmt.Sprintf("%s: %v", msg, e.Err)
+	}
+	return msg
+}
+
+// Unwrap возвращает исходную причину
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is сравнивает ошибки по коду, поэтому errors.Is(err, apperr.ErrNotFound) не зависит от сообщения
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// CodeOf возвращает код доменной ошибки из цепочки err или CodeInternal
+func CodeOf(err error) Code {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return CodeInternal
+}
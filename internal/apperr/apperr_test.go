@@ -0,0 +1,68 @@
+package apperr_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"avito-test-quest/internal/apperr"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorMessage(t *testing.T) {
+	cause := errors.New("no rows")
+	tests := []struct {
+		name string
+		err  *apperr.Error
+		want string
+	}{
+		{name: "code only", err: &apperr.Error{Code: apperr.CodeNotFound}, want: "NOT_FOUND"},
+		{name: "with message", err: apperr.New(apperr.CodeNotFound, "team not found"), want: "NOT_FOUND: team not found"},
+		{name: "with cause", err: apperr.Wrap(cause, apperr.CodeNotFound, "team not found"), want: "NOT_FOUND: team not found: no rows"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.err.Error())
+		})
+	}
+}
+
+func TestErrorIs(t *testing.T) {
+	cause := errors.New("no rows")
+	wrapped := apperr.Wrap(cause, apperr.CodeNotFound, "team not found")
+	tests := []struct {
+		name   string
+		err    error
+		target error
+		want   bool
+	}{
+		{name: "same code, different message", err: wrapped, target: apperr.ErrNotFound, want: true},
+		{name: "different code", err: wrapped, target: apperr.ErrPRExists, want: false},
+		{name: "through fmt wrapping", err: fmt.Errorf("reassign: %w", wrapped), target: apperr.ErrNotFound, want: true},
+		{name: "unwraps to cause", err: wrapped, target: cause, want: true},
+		{name: "plain error", err: cause, target: apperr.ErrNotFound, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, errors.Is(tt.err, tt.target))
+		})
+	}
+}
+
+func TestCodeOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want apperr.Code
+	}{
+		{name: "domain error", err: apperr.New(apperr.CodePRMerged, "merged"), want: apperr.CodePRMerged},
+		{name: "wrapped domain error", err: fmt.Errorf("tx: %w", apperr.New(apperr.CodeForbidden, "denied")), want: apperr.CodeForbidden},
+		{name: "plain error", err: errors.New("connection reset"), want: apperr.CodeInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, apperr.CodeOf(tt.err))
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+)
+
+// Role уровень доступа токена; роль пользователя в команде (users.role) определяет сервисный слой
+type Role string
+
+const (
+	RoleAdmin Role = "admin"
+	RoleUser  Role = "user"
+)
+
+// ErrInvalidToken токен не распознан или недействителен
+var ErrInvalidToken = errors.New("invalid token")
+
+// Principal аутентифицированная вызывающая сторона
+type Principal struct {
+	UserID string // пустой для админских токенов
+	Role   Role
+}
+
+// Authenticator проверяет bearer токен и возвращает вызывающую сторону
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (*Principal, error)
+}
+
+type key string
+
+const keyForPrincipal key = "principal"
+
+// WithPrincipal добавляет вызывающую сторону в контекст
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, keyForPrincipal, p)
+}
+
+// PrincipalFromCtx достаёт вызывающую сторону из контекста
+func PrincipalFromCtx(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(keyForPrincipal).(*Principal)
+	return p, ok
+}
+
+// StaticTokens аутентификация по статическим токенам из конфигурации.
+// Токены хранятся в виде sha256, чтобы поиск не зависел от содержимого токена посимвольно
+type StaticTokens struct {
+	tokens map[[sha256.Size]byte]Principal
+}
+
+// NewStaticTokens создает аутентификатор по админским токенам и токенам пользователей (user_id -> токен)
+func NewStaticTokens(adminTokens []string, userTokens map[string]string) *StaticTokens {
+	tokens := make(map[[sha256.Size]byte]Principal, len(adminTokens)+len(userTokens))
+	for userID, token := range userTokens {
+		if token == "" {
+			continue
+		}
+		tokens[sha256.Sum256([]byte(token))] = Principal{UserID: userID, Role: RoleUser}
+	}
+	// админский токен имеет приоритет, если совпал с пользовательским
+	for _, token := range adminTokens {
+		if token == "" {
+			continue
+		}
+		tokens[sha256.Sum256([]byte(token))] = Principal{Role: RoleAdmin}
+	}
+	return &StaticTokens{tokens: tokens}
+}
+
+// Authenticate ищет токен среди настроенных
+func (s *StaticTokens) Authenticate(_ context.Context, token string) (*Principal, error) {
+	p, ok := s.tokens[sha256.Sum256([]byte(token))]
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	return &p, nil
+}
+
+// проверка реализации интерфейса Authenticator
+var _ Authenticator = (*StaticTokens)(nil)
@@ -0,0 +1,55 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+
+	"avito-test-quest/internal/auth"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticTokens(t *testing.T) {
+	authn := auth.NewStaticTokens([]string{"admin-1", "", "shared"}, map[string]string{"u1": "user-1", "u2": "shared", "u3": ""})
+
+	tests := []struct {
+		name  string
+		token string
+		want  *auth.Principal
+	}{
+		{name: "admin token", token: "admin-1", want: &auth.Principal{Role: auth.RoleAdmin}},
+		{name: "user token", token: "user-1", want: &auth.Principal{UserID: "u1", Role: auth.RoleUser}},
+		{name: "admin wins over user with the same token", token: "shared", want: &auth.Principal{Role: auth.RoleAdmin}},
+		{name: "empty token", token: ""},
+		{name: "unknown token", token: "guess"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := authn.Authenticate(context.Background(), tt.token)
+			if tt.want == nil {
+				require.ErrorIs(t, err, auth.ErrInvalidToken)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestChain(t *testing.T) {
+	chain := auth.Chain{
+		auth.NewStaticTokens([]string{"admin-1"}, nil),
+		auth.NewStaticTokens(nil, map[string]string{"u1": "user-1"}),
+	}
+
+	got, err := chain.Authenticate(context.Background(), "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, &auth.Principal{UserID: "u1", Role: auth.RoleUser}, got)
+
+	_, err = chain.Authenticate(context.Background(), "guess")
+	require.ErrorIs(t, err, auth.ErrInvalidToken)
+
+	_, err = auth.Chain{}.Authenticate(context.Background(), "admin-1")
+	require.ErrorIs(t, err, auth.ErrInvalidToken)
+}
@@ -0,0 +1,243 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// jwksRefreshInterval минимальный интервал между повторными загрузками JWKS по URL
+const jwksRefreshInterval = time.Minute
+
+// jwk ключ из JWKS (RFC 7517), поддерживаются RSA и EC P-256
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// jwks набор публичных ключей для проверки подписи токенов.
+// Ключи из URL перезагружаются, если токен подписан неизвестным kid (ротация ключей у SSO)
+type jwks struct {
+	load func(ctx context.Context) ([]byte, error)
+	// refreshable ключи можно перезагружать (источник — URL)
+	refreshable bool
+
+	refreshMu   sync.Mutex
+	mu          sync.RWMutex
+	keys        map[string]crypto.PublicKey
+	single      crypto.PublicKey // единственный ключ набора, им проверяются токены без kid
+	refreshedAt time.Time
+}
+
+// newJWKSFromFile загружает JWKS из файла
+func newJWKSFromFile(ctx context.Context, path string) (*jwks, error) {
+	k := &jwks{load: func(context.Context) ([]byte, error) { return os.ReadFile(path) }}
+	if err := k.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// newJWKSFromURL загружает JWKS по URL
+func newJWKSFromURL(ctx context.Context, url string) (*jwks, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	k := &jwks{
+		load: func(ctx context.Context) ([]byte, error) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return nil, err
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				return nil, err
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+			}
+			return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		},
+		refreshable: true,
+	}
+	if err := k.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// key возвращает ключ по kid; при промахе ключи из URL перезагружаются не чаще jwksRefreshInterval
+func (k *jwks) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	key, refreshedAt, ok := k.lookup(kid)
+	if ok {
+		return key, nil
+	}
+	if !k.refreshable {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	// одновременные промахи перезагружают ключи один раз
+	k.refreshMu.Lock()
+	defer k.refreshMu.Unlock()
+	key, lastRefresh, ok := k.lookup(kid)
+	if ok {
+		return key, nil
+	}
+	if lastRefresh.Equal(refreshedAt) && time.Since(lastRefresh) > jwksRefreshInterval {
+		if err := k.refresh(ctx); err != nil {
+			return nil, err
+		}
+		if key, _, ok = k.lookup(kid); ok {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown key id %q", kid)
+}
+
+// lookup ищет ключ в текущем наборе и возвращает время его загрузки
+func (k *jwks) lookup(kid string) (crypto.PublicKey, time.Time, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	key, ok := k.keys[kid]
+	if !ok && kid == "" && k.single != nil {
+		key, ok = k.single, true
+	}
+	return key, k.refreshedAt, ok
+}
+
+// refresh загружает и разбирает JWKS
+func (k *jwks) refresh(ctx context.Context) error {
+	data, err := k.load(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load jwks: %w", err)
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return err
+	}
+
+	var single crypto.PublicKey
+	if len(keys) == 1 {
+		for _, key := range keys {
+			single = key
+		}
+	}
+
+	k.mu.Lock()
+	k.keys = keys
+	k.single = single
+	k.refreshedAt = time.Now()
+	k.mu.Unlock()
+	return nil
+}
+
+// parseJWKS разбирает JWKS; ключи не для подписи и неподдерживаемых типов пропускаются
+func parseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to parse jwks: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		var (
+			key crypto.PublicKey
+			err error
+		)
+		switch k.Kty {
+		case "RSA":
+			key, err = k.rsaKey()
+		case "EC":
+			key, err = k.ecKey()
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwk %q: %w", k.Kid, err)
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("jwks contains no signing keys")
+	}
+	return keys, nil
+}
+
+// rsaKey собирает RSA ключ из модуля и экспоненты
+func (k jwk) rsaKey() (*rsa.PublicKey, error) {
+	n, err := decodeBigInt(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("modulus: %w", err)
+	}
+	e, err := decodeBigInt(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("exponent: %w", err)
+	}
+	if !e.IsInt64() || e.Int64() < 2 || e.Int64() > 1<<31-1 {
+		return nil, errors.New("exponent out of range")
+	}
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+// ecKey собирает EC ключ на кривой P-256 (ES256)
+func (k jwk) ecKey() (*ecdsa.PublicKey, error) {
+	if k.Crv != "P-256" {
+		return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+	}
+	x, err := decodeBigInt(k.X)
+	if err != nil {
+		return nil, fmt.Errorf("x: %w", err)
+	}
+	y, err := decodeBigInt(k.Y)
+	if err != nil {
+		return nil, fmt.Errorf("y: %w", err)
+	}
+	if x.BitLen() > 256 || y.BitLen() > 256 {
+		return nil, errors.New("coordinate out of range")
+	}
+	// ecdh проверяет, что точка лежит на кривой
+	point := make([]byte, 65)
+	point[0] = 4
+	x.FillBytes(point[1:33])
+	y.FillBytes(point[33:])
+	if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+		return nil, err
+	}
+	return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+}
+
+// decodeBigInt декодирует число в base64url без паддинга
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty value")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
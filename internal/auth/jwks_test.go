@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rsaJWKS JWKS с новым RSA ключом для каждого kid
+func rsaJWKS(t *testing.T, kids ...string) []byte {
+	t.Helper()
+	b64 := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	var keys []map[string]string
+	for _, kid := range kids {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		keys = append(keys, map[string]string{
+			"kty": "RSA", "kid": kid, "use": "sig",
+			"n": b64(key.N.Bytes()), "e": b64(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+	data, err := json.Marshal(map[string]interface{}{"keys": keys})
+	require.NoError(t, err)
+	return data
+}
+
+// TestJWKSRefreshOnUnknownKid ротация ключей у SSO: неизвестный kid перезагружает JWKS по URL,
+// но не чаще jwksRefreshInterval
+func TestJWKSRefreshOnUnknownKid(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		body     = rsaJWKS(t, "k1")
+		requests int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	served := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return requests
+	}
+
+	ctx := context.Background()
+	k, err := newJWKSFromURL(ctx, srv.URL)
+	require.NoError(t, err)
+	_, err = k.key(ctx, "k1")
+	require.NoError(t, err)
+
+	// SSO выпустил новый ключ, но интервал перезагрузки еще не прошел
+	mu.Lock()
+	body = rsaJWKS(t, "k1", "k2")
+	mu.Unlock()
+	_, err = k.key(ctx, "k2")
+	require.Error(t, err)
+	assert.Equal(t, 1, served())
+
+	// после интервала неизвестный kid перезагружает ключи
+	k.mu.Lock()
+	k.refreshedAt = time.Now().Add(-2 * jwksRefreshInterval)
+	k.mu.Unlock()
+	_, err = k.key(ctx, "k2")
+	require.NoError(t, err)
+	assert.Equal(t, 2, served())
+
+	// kid, которого нет и после перезагрузки, не вызывает повторных запросов
+	_, err = k.key(ctx, "k3")
+	require.Error(t, err)
+	assert.Equal(t, 2, served())
+}
+
+// TestJWKSFromFileNotRefreshed ключи из файла не перезагружаются
+func TestJWKSFromFileNotRefreshed(t *testing.T) {
+	k := &jwks{load: func(context.Context) ([]byte, error) { return rsaJWKS(t, "k1"), nil }}
+	require.NoError(t, k.refresh(context.Background()))
+	k.refreshedAt = time.Now().Add(-2 * jwksRefreshInterval)
+
+	_, err := k.key(context.Background(), "k2")
+	assert.Error(t, err)
+	// единственным ключом набора проверяются токены без kid
+	_, err = k.key(context.Background(), "")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWTConfig содержит настройки проверки JWT, выпущенных SSO
+type JWTConfig struct {
+	// Issuer ожидаемый iss токена
+	Issuer string `yaml:"issuer" env:"AUTH_JWT_ISSUER"`
+	// Audience ожидаемый aud токена
+	Audience string `yaml:"audience" env:"AUTH_JWT_AUDIENCE"`
+	// JWKSFile путь к файлу с публичными ключами; имеет приоритет над JWKSURL
+	JWKSFile string `yaml:"jwks_file" env:"AUTH_JWT_JWKS_FILE"`
+	// JWKSURL адрес JWKS у SSO, ключи перезагружаются при появлении нового kid
+	JWKSURL string `yaml:"jwks_url" env:"AUTH_JWT_JWKS_URL"`
+	// UserIDClaim claim с user_id, по умолчанию sub
+	UserIDClaim string `yaml:"user_id_claim" env:"AUTH_JWT_USER_ID_CLAIM" env-default:"sub"`
+	// RoleClaim claim с ролью: строка или массив строк
+	RoleClaim string `yaml:"role_claim" env:"AUTH_JWT_ROLE_CLAIM" env-default:"role"`
+	// AdminRole значение RoleClaim, дающее роль admin; остальные получают роль user
+	AdminRole string `yaml:"admin_role" env:"AUTH_JWT_ADMIN_ROLE" env-default:"admin"`
+	// Leeway допустимое расхождение часов при проверке exp и nbf
+	Leeway time.Duration `yaml:"leeway" env:"AUTH_JWT_LEEWAY" env-default:"30s"`
+}
+
+// Enabled проверка JWT включена, если задан источник ключей
+func (c JWTConfig) Enabled() bool {
+	return c.JWKSFile != "" || c.JWKSURL != ""
+}
+
+// JWTVerifier аутентификация по JWT с подписью RS256 или ES256
+type JWTVerifier struct {
+	cfg    JWTConfig
+	keys   *jwks
+	parser *jwt.Parser
+}
+
+// NewJWTVerifier загружает JWKS и создает проверку токенов
+func NewJWTVerifier(ctx context.Context, cfg JWTConfig) (*JWTVerifier, error) {
+	if cfg.Issuer == "" || cfg.Audience == "" {
+		return nil, errors.New("jwt issuer and audience are required")
+	}
+	if cfg.UserIDClaim == "" {
+		cfg.UserIDClaim = "sub"
+	}
+	if cfg.RoleClaim == "" {
+		cfg.RoleClaim = "role"
+	}
+	if cfg.AdminRole == "" {
+		cfg.AdminRole = string(RoleAdmin)
+	}
+
+	var (
+		keys *jwks
+		err  error
+	)
+	switch {
+	case cfg.JWKSFile != "":
+		keys, err = newJWKSFromFile(ctx, cfg.JWKSFile)
+	case cfg.JWKSURL != "":
+		keys, err = newJWKSFromURL(ctx, cfg.JWKSURL)
+	default:
+		return nil, errors.New("jwks_file or jwks_url is required")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &JWTVerifier{
+		cfg:  cfg,
+		keys: keys,
+		parser: jwt.NewParser(
+			jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg()}),
+			jwt.WithIssuer(cfg.Issuer),
+			jwt.WithAudience(cfg.Audience),
+			jwt.WithExpirationRequired(),
+			jwt.WithLeeway(cfg.Leeway),
+		),
+	}, nil
+}
+
+// Authenticate проверяет подпись, iss, aud, exp и достаёт user_id и роль из claims
+func (v *JWTVerifier) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	claims := jwt.MapClaims{}
+	_, err := v.parser.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.keys.key(ctx, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	userID, _ := claims[v.cfg.UserIDClaim].(string)
+	if userID == "" {
+		return nil, fmt.Errorf("%w: claim %q is missing", ErrInvalidToken, v.cfg.UserIDClaim)
+	}
+	role := RoleUser
+	if hasRole(claims[v.cfg.RoleClaim], v.cfg.AdminRole) {
+		role = RoleAdmin
+	}
+
+	return &Principal{UserID: userID, Role: role}, nil
+}
+
+// hasRole проверяет значение claim роли: строку или массив строк
+func hasRole(claim interface{}, role string) bool {
+	switch v := claim.(type) {
+	case string:
+		return v == role
+	case []interface{}:
+		return slices.ContainsFunc(v, func(r interface{}) bool { return r == role })
+	}
+	return false
+}
+
+// Chain пробует аутентификаторы по очереди и возвращает первую успешную проверку
+type Chain []Authenticator
+
+// Authenticate возвращает ошибку последнего аутентификатора, если токен не подошел ни одному
+func (c Chain) Authenticate(ctx context.Context, token string) (*Principal, error) {
+	err := ErrInvalidToken
+	for _, a := range c {
+		var p *Principal
+		if p, err = a.Authenticate(ctx, token); err == nil {
+			return p, nil
+		}
+	}
+	return nil, err
+}
+
+// проверка реализации интерфейса Authenticator
+var (
+	_ Authenticator = (*JWTVerifier)(nil)
+	_ Authenticator = Chain(nil)
+)
@@ -0,0 +1,306 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"avito-test-quest/internal/auth"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testIssuer   = "https://sso.test"
+	testAudience = "pr-reviewer"
+)
+
+// testKeys локально сгенерированные ключи RSA и EC P-256
+type testKeys struct {
+	rsa *rsa.PrivateKey
+	ec  *ecdsa.PrivateKey
+}
+
+func newTestKeys(t *testing.T) *testKeys {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	return &testKeys{rsa: rsaKey, ec: ecKey}
+}
+
+// jwks публичные части ключей с kid rsa-1 и ec-1
+func (k *testKeys) jwks(t *testing.T) []byte {
+	t.Helper()
+	b64 := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	data, err := json.Marshal(map[string]interface{}{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa-1", "use": "sig", "alg": "RS256",
+			"n": b64(k.rsa.N.Bytes()), "e": b64(big.NewInt(int64(k.rsa.E)).Bytes())},
+		{"kty": "EC", "kid": "ec-1", "use": "sig", "alg": "ES256", "crv": "P-256",
+			"x": b64(k.ec.X.FillBytes(make([]byte, 32))), "y": b64(k.ec.Y.FillBytes(make([]byte, 32)))},
+	}})
+	require.NoError(t, err)
+	return data
+}
+
+// jwksFile пишет JWKS во временный файл
+func (k *testKeys) jwksFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, k.jwks(t), 0o600))
+	return path
+}
+
+// sign подписывает токен методом method ключом key с заголовком kid
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+// claims claims, проходящие все проверки, с заменой полей из override (nil удаляет поле)
+func claims(override jwt.MapClaims) jwt.MapClaims {
+	c := jwt.MapClaims{
+		"iss": testIssuer,
+		"aud": testAudience,
+		"sub": "u1",
+		"iat": time.Now().Unix(),
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for k, v := range override {
+		if v == nil {
+			delete(c, k)
+			continue
+		}
+		c[k] = v
+	}
+	return c
+}
+
+func TestJWTVerifier(t *testing.T) {
+	keys := newTestKeys(t)
+	verifier, err := auth.NewJWTVerifier(context.Background(), auth.JWTConfig{
+		Issuer: testIssuer, Audience: testAudience, JWKSFile: keys.jwksFile(t), Leeway: 30 * time.Second,
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		token   string
+		want    *auth.Principal
+		wantErr bool
+	}{
+		{
+			name:  "RS256",
+			token: sign(t, jwt.SigningMethodRS256, keys.rsa, "rsa-1", claims(nil)),
+			want:  &auth.Principal{UserID: "u1", Role: auth.RoleUser},
+		},
+		{
+			name:  "ES256",
+			token: sign(t, jwt.SigningMethodES256, keys.ec, "ec-1", claims(nil)),
+			want:  &auth.Principal{UserID: "u1", Role: auth.RoleUser},
+		},
+		{
+			name:  "audience in array",
+			token: sign(t, jwt.SigningMethodRS256, keys.rsa, "rsa-1", claims(jwt.MapClaims{"aud": []string{"other", testAudience}})),
+			want:  &auth.Principal{UserID: "u1", Role: auth.RoleUser},
+		},
+		{
+			name:  "expired within leeway",
+			token: sign(t, jwt.SigningMethodRS256, keys.rsa, "rsa-1", claims(jwt.MapClaims{"exp": time.Now().Add(-10 * time.Second).Unix()})),
+			want:  &auth.Principal{UserID: "u1", Role: auth.RoleUser},
+		},
+		{
+			name:    "wrong issuer",
+			token:   sign(t, jwt.SigningMethodRS256, keys.rsa, "rsa-1", claims(jwt.MapClaims{"iss": "https://evil.test"})),
+			wantErr: true,
+		},
+		{
+			name:    "wrong audience",
+			token:   sign(t, jwt.SigningMethodRS256, keys.rsa, "rsa-1", claims(jwt.MapClaims{"aud": "other"})),
+			wantErr: true,
+		},
+		{
+			name:    "expired",
+			token:   sign(t, jwt.SigningMethodRS256, keys.rsa, "rsa-1", claims(jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()})),
+			wantErr: true,
+		},
+		{
+			name:    "without exp",
+			token:   sign(t, jwt.SigningMethodRS256, keys.rsa, "rsa-1", claims(jwt.MapClaims{"exp": nil})),
+			wantErr: true,
+		},
+		{
+			name:    "without user id",
+			token:   sign(t, jwt.SigningMethodRS256, keys.rsa, "rsa-1", claims(jwt.MapClaims{"sub": nil})),
+			wantErr: true,
+		},
+		{
+			name:    "alg does not match key type",
+			token:   sign(t, jwt.SigningMethodES256, keys.ec, "rsa-1", claims(nil)),
+			wantErr: true,
+		},
+		{
+			name:    "HS256 with public key as secret",
+			token:   sign(t, jwt.SigningMethodHS256, keys.rsa.N.Bytes(), "rsa-1", claims(nil)),
+			wantErr: true,
+		},
+		{
+			name:    "alg none",
+			token:   sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "rsa-1", claims(nil)),
+			wantErr: true,
+		},
+		{
+			name:    "signed by another key",
+			token:   sign(t, jwt.SigningMethodRS256, newTestKeys(t).rsa, "rsa-1", claims(nil)),
+			wantErr: true,
+		},
+		{
+			name:    "unknown kid",
+			token:   sign(t, jwt.SigningMethodRS256, keys.rsa, "rsa-2", claims(nil)),
+			wantErr: true,
+		},
+		{
+			name:    "not a jwt",
+			token:   "static-token",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := verifier.Authenticate(context.Background(), tt.token)
+			if tt.wantErr {
+				require.ErrorIs(t, err, auth.ErrInvalidToken)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestJWTVerifierRoleClaim(t *testing.T) {
+	keys := newTestKeys(t)
+	file := keys.jwksFile(t)
+
+	tests := []struct {
+		name   string
+		cfg    auth.JWTConfig
+		claims jwt.MapClaims
+		want   *auth.Principal
+	}{
+		{
+			name:   "admin role string",
+			claims: claims(jwt.MapClaims{"role": "admin"}),
+			want:   &auth.Principal{UserID: "u1", Role: auth.RoleAdmin},
+		},
+		{
+			name:   "admin role in array",
+			claims: claims(jwt.MapClaims{"role": []string{"developer", "admin"}}),
+			want:   &auth.Principal{UserID: "u1", Role: auth.RoleAdmin},
+		},
+		{
+			name:   "other role",
+			claims: claims(jwt.MapClaims{"role": "developer"}),
+			want:   &auth.Principal{UserID: "u1", Role: auth.RoleUser},
+		},
+		{
+			name:   "role of wrong type",
+			claims: claims(jwt.MapClaims{"role": map[string]string{"name": "admin"}}),
+			want:   &auth.Principal{UserID: "u1", Role: auth.RoleUser},
+		},
+		{
+			name:   "custom claims",
+			cfg:    auth.JWTConfig{UserIDClaim: "preferred_username", RoleClaim: "groups", AdminRole: "pr-admins"},
+			claims: claims(jwt.MapClaims{"preferred_username": "alice", "groups": []string{"pr-admins"}, "role": "user"}),
+			want:   &auth.Principal{UserID: "alice", Role: auth.RoleAdmin},
+		},
+		{
+			name:   "custom role claim ignores default",
+			cfg:    auth.JWTConfig{RoleClaim: "groups"},
+			claims: claims(jwt.MapClaims{"role": "admin"}),
+			want:   &auth.Principal{UserID: "u1", Role: auth.RoleUser},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			cfg.Issuer, cfg.Audience, cfg.JWKSFile = testIssuer, testAudience, file
+			verifier, err := auth.NewJWTVerifier(context.Background(), cfg)
+			require.NoError(t, err)
+
+			got, err := verifier.Authenticate(context.Background(), sign(t, jwt.SigningMethodRS256, keys.rsa, "rsa-1", tt.claims))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestJWTVerifierFromURL(t *testing.T) {
+	keys := newTestKeys(t)
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		_, _ = w.Write(keys.jwks(t))
+	}))
+	t.Cleanup(srv.Close)
+
+	verifier, err := auth.NewJWTVerifier(context.Background(), auth.JWTConfig{
+		Issuer: testIssuer, Audience: testAudience, JWKSURL: srv.URL,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, requests)
+
+	got, err := verifier.Authenticate(context.Background(), sign(t, jwt.SigningMethodES256, keys.ec, "ec-1", claims(nil)))
+	require.NoError(t, err)
+	assert.Equal(t, &auth.Principal{UserID: "u1", Role: auth.RoleUser}, got)
+
+	// неизвестный kid сразу после загрузки не перезагружает ключи: перезагрузка не чаще раза в минуту
+	_, err = verifier.Authenticate(context.Background(), sign(t, jwt.SigningMethodRS256, keys.rsa, "rsa-2", claims(nil)))
+	require.ErrorIs(t, err, auth.ErrInvalidToken)
+	assert.Equal(t, 1, requests)
+}
+
+func TestNewJWTVerifierErrors(t *testing.T) {
+	keys := newTestKeys(t)
+	notFound := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(notFound.Close)
+	badJSON := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(badJSON, []byte(`{"keys": [{"kty": "oct", "k": "c2VjcmV0"}]}`), 0o600))
+
+	tests := []struct {
+		name string
+		cfg  auth.JWTConfig
+	}{
+		{name: "without issuer", cfg: auth.JWTConfig{Audience: testAudience, JWKSFile: keys.jwksFile(t)}},
+		{name: "without audience", cfg: auth.JWTConfig{Issuer: testIssuer, JWKSFile: keys.jwksFile(t)}},
+		{name: "without key source", cfg: auth.JWTConfig{Issuer: testIssuer, Audience: testAudience}},
+		{name: "missing file", cfg: auth.JWTConfig{Issuer: testIssuer, Audience: testAudience, JWKSFile: filepath.Join(t.TempDir(), "missing.json")}},
+		{name: "no signing keys", cfg: auth.JWTConfig{Issuer: testIssuer, Audience: testAudience, JWKSFile: badJSON}},
+		{name: "url responds 404", cfg: auth.JWTConfig{Issuer: testIssuer, Audience: testAudience, JWKSURL: notFound.URL}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := auth.NewJWTVerifier(context.Background(), tt.cfg)
+			assert.Error(t, err)
+		})
+	}
+}
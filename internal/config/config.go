@@ -1,51 +1,156 @@
 package config
 
 import (
+	"avito-test-quest/internal/auth"
+	"avito-test-quest/internal/logger"
 	"avito-test-quest/internal/postgres"
+	"avito-test-quest/internal/sqlite"
+	"avito-test-quest/internal/tracing"
 	"fmt"
 	"os"
+	"reflect"
+	"strings"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
 // PRConfig содержит настройки для сервера PR
 type PRConfig struct {
-	Host string `yaml:"host"`
-	Port string `yaml:"port"`
+	Host string `yaml:"host" env:"PR_HOST"`
+	Port string `yaml:"port" env:"PR_PORT" env-default:"8080"`
+	// DrainDelay пауза между переводом /health/ready в shutting_down и остановкой HTTP сервера,
+	// за которую балансировщик выводит экземпляр из ротации
+	DrainDelay time.Duration `yaml:"drain_delay" env:"PR_DRAIN_DELAY" env-default:"5s"`
+}
+
+// AssignmentConfig содержит настройки назначения ревьюверов
+type AssignmentConfig struct {
+	// Strategy стратегия выбора ревьюверов: random, round_robin, least_loaded
+	Strategy string `yaml:"strategy" env:"ASSIGNMENT_STRATEGY" env-default:"random"`
+}
+
+// драйверы хранилища
+const (
+	StorageDriverPostgres = "postgres"
+	StorageDriverSQLite   = "sqlite"
+	StorageDriverMemory   = "memory"
+)
+
+// StorageConfig содержит выбор хранилища данных
+type StorageConfig struct {
+	// Driver хранилище: postgres, sqlite (файл рядом с сервисом, без отдельной БД)
+	// или memory (данные живут до перезапуска, для локального запуска и тестов)
+	Driver string `yaml:"driver" env:"STORAGE_DRIVER" env-default:"postgres"`
+}
+
+// AuthConfig содержит токены доступа к API
+type AuthConfig struct {
+	// AdminTokens токены с полным доступом. Читаются только из окружения (или AUTH_ADMIN_TOKENS_FILE),
+	// чтобы админский доступ не попадал в файл конфигурации из репозитория
+	AdminTokens []string `yaml:"-" env:"AUTH_ADMIN_TOKENS" env-separator:","`
+	// UserTokens токены пользователей: user_id -> токен
+	UserTokens map[string]string `yaml:"user_tokens" env:"AUTH_USER_TOKENS"`
+	// JWT проверка токенов SSO; выключена, если не задан jwks_file или jwks_url
+	JWT auth.JWTConfig `yaml:"jwt"`
 }
 
 // Config содержит общие настройки приложения
 type Config struct {
-	Postgres postgres.Config `yaml:"postgres"`
-	PR       PRConfig        `yaml:"pr"`
+	Storage    StorageConfig    `yaml:"storage"`
+	Postgres   postgres.Config  `yaml:"postgres"`
+	SQLite     sqlite.Config    `yaml:"sqlite"`
+	PR         PRConfig         `yaml:"pr"`
+	Assignment AssignmentConfig `yaml:"assignment"`
+	Auth       AuthConfig       `yaml:"auth"`
+	Log        logger.Config    `yaml:"log"`
+	Tracing    tracing.Config   `yaml:"tracing"`
+}
+
+// ConfigPathEnv переменная окружения с путем к файлу конфигурации
+const ConfigPathEnv = "CONFIG_PATH"
+
+// defaultConfigPaths где искать config.yaml, если путь не задан: для совместимости с тестами и Docker
+var defaultConfigPaths = []string{
+	"./configs/config.yaml",
+	"../configs/config.yaml",
+	"../../configs/config.yaml",
 }
 
-// New загружает конфигурацию из файла и возвращает Config
-func New() (*Config, error) {
+// New загружает конфигурацию и проверяет её.
+// Файл берется из path, затем из CONFIG_PATH, затем ищется в стандартных местах;
+// если файла нет, а путь не задан явно, настройки читаются только из окружения.
+// Любую переменную окружения можно передать файлом через NAME_FILE (секреты Kubernetes и Docker)
+func New(path string) (*Config, error) {
 	var cfg Config
 
-	// Ищем config.yaml в нескольких местах для совместимости с тестами и Docker
-	configPaths := []string{
-		"./configs/config.yaml",
-		"configs/config.yaml",
-		"../configs/config.yaml",
-		"../../configs/config.yaml",
+	if err := loadSecretFiles(); err != nil {
+		return nil, err
 	}
 
-	var configPath string
-	for _, path := range configPaths {
-		if _, err := os.Stat(path); err == nil {
-			configPath = path
-			break
+	explicit := path != ""
+	if !explicit {
+		path = os.Getenv(ConfigPathEnv)
+		explicit = path != ""
+	}
+	if !explicit {
+		for _, p := range defaultConfigPaths {
+			if _, err := os.Stat(p); err == nil {
+				path = p
+				break
+			}
 		}
 	}
 
-	if configPath == "" {
-		return &Config{}, fmt.Errorf("config file not found")
+	if path != "" {
+		if err := cleanenv.ReadConfig(path, &cfg); err != nil {
+			return nil, fmt.Errorf("error reading config %s: %w", path, err)
+		}
+	} else if err := cleanenv.ReadEnv(&cfg); err != nil {
+		return nil, fmt.Errorf("error reading config from environment: %w", err)
 	}
 
-	if err := cleanenv.ReadConfig(configPath, &cfg); err != nil {
-		return &Config{}, fmt.Errorf("error reading config: %w", err)
+	if err := cfg.Validate(); err != nil {
+		return nil, err
 	}
 	return &cfg, nil
 }
+
+// loadSecretFiles для каждой переменной конфигурации NAME, не заданной напрямую,
+// читает значение из файла, указанного в NAME_FILE
+func loadSecretFiles() error {
+	for _, name := range envNames(reflect.TypeOf(Config{})) {
+		if _, ok := os.LookupEnv(name); ok {
+			continue
+		}
+		file := os.Getenv(name + "_FILE")
+		if file == "" {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read %s_FILE: %w", name, err)
+		}
+		// файлы секретов обычно заканчиваются переводом строки
+		if err := os.Setenv(name, strings.TrimRight(string(data), "\r\n")); err != nil {
+			return fmt.Errorf("failed to set %s from file: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// envNames собирает имена переменных окружения из тегов env всех вложенных структур
+func envNames(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if name := f.Tag.Get("env"); name != "" {
+			names = append(names, name)
+			continue
+		}
+		if f.Type.Kind() == reflect.Struct {
+			names = append(names, envNames(f.Type)...)
+		}
+	}
+	return names
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"avito-test-quest/internal/models"
+	"avito-test-quest/internal/tracing"
+)
+
+// placeholderAdminToken админский токен из старого configs/config.yaml: он опубликован в репозитории
+const placeholderAdminToken = "admin-secret-token"
+
+// ValidationError список всех ошибок конфигурации: сервис сообщает их сразу, а не по одной за запуск
+type ValidationError struct {
+	Problems []string
+}
+
+// Error перечисляет ошибки по строке на каждую
+func (e *ValidationError) Error() string {
+	return "invalid config:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// Validate проверяет значения конфигурации и возвращает *ValidationError со всеми найденными ошибками
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	// настройки подключения проверяются только для выбранного хранилища
+	switch c.Storage.Driver {
+	case "", StorageDriverPostgres:
+		v.required("postgres.host (POSTGRES_HOST)", c.Postgres.Host)
+		v.port("postgres.port (POSTGRES_PORT)", c.Postgres.Port)
+		v.required("postgres.username (POSTGRES_USER)", c.Postgres.Username)
+		v.required("postgres.password (POSTGRES_PASSWORD)", c.Postgres.Password)
+		v.required("postgres.database (POSTGRES_DB)", c.Postgres.Database)
+		v.check(c.Postgres.MaxConns >= 1, "postgres.max_conns (MAX_CONNS) must be at least 1, got %d", c.Postgres.MaxConns)
+		v.check(c.Postgres.MinConns >= 0 && c.Postgres.MinConns <= c.Postgres.MaxConns,
+			"postgres.min_conns (MIN_CONNS) must be between 0 and max_conns %d, got %d", c.Postgres.MaxConns, c.Postgres.MinConns)
+	case StorageDriverSQLite:
+		v.required("sqlite.path (SQLITE_PATH)", c.SQLite.Path)
+	case StorageDriverMemory:
+	default:
+		v.add("storage.driver (STORAGE_DRIVER) must be postgres, sqlite or memory, got %q", c.Storage.Driver)
+	}
+
+	v.port("pr.port (PR_PORT)", c.PR.Port)
+	v.check(c.PR.DrainDelay >= 0, "pr.drain_delay (PR_DRAIN_DELAY) must not be negative, got %s", c.PR.DrainDelay)
+
+	switch c.Assignment.Strategy {
+	case "", models.StrategyRandom, models.StrategyRoundRobin, models.StrategyLeastLoaded:
+	default:
+		v.add("assignment.strategy (ASSIGNMENT_STRATEGY) must be random, round_robin or least_loaded, got %q", c.Assignment.Strategy)
+	}
+
+	for i, token := range c.Auth.AdminTokens {
+		v.check(token != "", "auth.admin_tokens[%d] (AUTH_ADMIN_TOKENS) must not be empty", i)
+		v.check(token != placeholderAdminToken, "auth.admin_tokens[%d] (AUTH_ADMIN_TOKENS) must not be the published placeholder %q", i, placeholderAdminToken)
+	}
+	for userID, token := range c.Auth.UserTokens {
+		v.check(userID != "" && token != "", "auth.user_tokens (AUTH_USER_TOKENS) must not contain empty user_id or token, got %q", userID)
+	}
+	if c.Auth.JWT.JWKSURL != "" {
+		u, err := url.Parse(c.Auth.JWT.JWKSURL)
+		v.check(err == nil && (u.Scheme == "https" || u.Scheme == "http") && u.Host != "",
+			"auth.jwt.jwks_url (AUTH_JWT_JWKS_URL) must be an http(s) URL, got %q", c.Auth.JWT.JWKSURL)
+	}
+	v.check(c.Auth.JWT.Leeway >= 0, "auth.jwt.leeway (AUTH_JWT_LEEWAY) must not be negative, got %s", c.Auth.JWT.Leeway)
+
+	switch c.Log.Level {
+	case "", "debug", "info", "warn", "error":
+	default:
+		v.add("log.level (LOG_LEVEL) must be debug, info, warn or error, got %q", c.Log.Level)
+	}
+	switch c.Log.Encoding {
+	case "", "json", "console":
+	default:
+		v.add("log.encoding (LOG_ENCODING) must be json or console, got %q", c.Log.Encoding)
+	}
+
+	switch c.Tracing.Exporter {
+	case "", tracing.ExporterNone, tracing.ExporterOTLP, tracing.ExporterStdout:
+	default:
+		v.add("tracing.exporter (TRACING_EXPORTER) must be none, otlp or stdout, got %q", c.Tracing.Exporter)
+	}
+	v.check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1,
+		"tracing.sample_ratio (TRACING_SAMPLE_RATIO) must be between 0 and 1, got %v", c.Tracing.SampleRatio)
+
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+	return nil
+}
+
+// validator накапливает ошибки проверки
+type validator struct {
+	problems []string
+}
+
+// add добавляет ошибку
+func (v *validator) add(format string, args ...any) {
+	v.problems = append(v.problems, fmt.Sprintf(format, args...))
+}
+
+// check добавляет ошибку, если условие не выполнено
+func (v *validator) check(ok bool, format string, args ...any) {
+	if !ok {
+		v.add(format, args...)
+	}
+}
+
+// required проверяет, что строка не пустая
+func (v *validator) required(field, value string) {
+	v.check(strings.TrimSpace(value) != "", "%s must not be empty", field)
+}
+
+// port проверяет номер TCP порта
+func (v *validator) port(field, value string) {
+	n, err := strconv.Atoi(value)
+	v.check(err == nil && n >= 1 && n <= 65535, "%s must be a port number between 1 and 65535, got %q", field, value)
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"avito-test-quest/internal/apperr"
+	"avito-test-quest/internal/auth"
+	"avito-test-quest/internal/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// errorCodeKey ключ gin.Context с кодом ошибки ответа для журнала запросов
+const errorCodeKey = "error_code"
+
+// AccessLog middleware, пишущее журнал запросов через zap: маршрут, статус, длительность, пользователь.
+// Использует логгер запроса из контекста, поэтому подключается после RequestID
+func AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		// c.Request мог быть заменен следующими middleware (Authenticate кладет вызывающую сторону)
+		ctx := c.Request.Context()
+		log := logger.GetOrCreateLoggerFromCtx(ctx)
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := c.Writer.Status()
+		fields := []zap.Field{
+			zap.String("route", route),
+			zap.Int("status", status),
+			zap.Duration("latency", time.Since(start)),
+			zap.String("client_ip", c.ClientIP()),
+			zap.Int("response_size", c.Writer.Size()),
+		}
+		if p, ok := auth.PrincipalFromCtx(ctx); ok {
+			fields = append(fields, zap.String("user", p.UserID), zap.String("auth_role", string(p.Role)))
+		}
+		if code, ok := c.Get(errorCodeKey); ok {
+			fields = append(fields, zap.Any("error_code", code))
+		}
+
+		switch {
+		case status >= http.StatusInternalServerError:
+			log.Error(ctx, "http request", fields...)
+		case status >= http.StatusBadRequest:
+			log.Warn(ctx, "http request", fields...)
+		default:
+			log.Info(ctx, "http request", fields...)
+		}
+	}
+}
+
+// Recovery middleware, перехватывающее панику в обработчике: пишет стек в zap
+// и отвечает 500 INTERNAL в едином формате ошибок
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+			// http.ErrAbortHandler — штатный способ прервать ответ, его обрабатывает net/http
+			if err, ok := r.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(r)
+			}
+			ctx := c.Request.Context()
+			logger.GetOrCreateLoggerFromCtx(ctx).Error(ctx, "panic recovered",
+				zap.Any("panic", r), zap.String("route", c.FullPath()), zap.Stack("stack"))
+
+			c.Set(errorCodeKey, apperr.CodeInternal)
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			abortWithError(c, http.StatusInternalServerError, apperr.CodeInternal, internalErrorMessage)
+		}()
+
+		c.Next()
+	}
+}
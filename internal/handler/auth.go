@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"strings"
+
+	"avito-test-quest/internal/apperr"
+	"avito-test-quest/internal/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Authenticate middleware, пропускающее запрос только с действительным bearer токеном.
+// Вызывающая сторона кладется в контекст запроса, права по ролям проверяет сервисный слой.
+// Без токена или с неизвестным токеном — 401 UNAUTHORIZED
+func Authenticate(authn auth.Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := bearerToken(c.GetHeader("Authorization"))
+		if !ok {
+			unauthorized(c, apperr.New(apperr.CodeUnauthorized, "missing bearer token"))
+			return
+		}
+		principal, err := authn.Authenticate(c.Request.Context(), token)
+		if err != nil {
+			unauthorized(c, apperr.Wrap(err, apperr.CodeUnauthorized, "invalid token"))
+			return
+		}
+
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), principal))
+		c.Next()
+	}
+}
+
+// unauthorized прерывает запрос с 401 и заголовком WWW-Authenticate
+func unauthorized(c *gin.Context, err error) {
+	c.Header("WWW-Authenticate", `Bearer realm="pr-reviewer"`)
+	_ = c.Error(err)
+	c.Abort()
+}
+
+// bearerToken достаёт токен из заголовка "Authorization: Bearer <token>"
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
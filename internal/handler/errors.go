@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"avito-test-quest/internal/apperr"
+	"avito-test-quest/internal/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// internalErrorMessage сообщение клиенту для внутренних ошибок: детали причины только в логах
+const internalErrorMessage = "internal server error"
+
+// statusByCode HTTP статус для кода доменной ошибки
+var statusByCode = map[apperr.Code]int{
+	apperr.CodeTeamExists:          http.StatusBadRequest,
+	apperr.CodePRExists:            http.StatusConflict,
+	apperr.CodePRMerged:            http.StatusConflict,
+	apperr.CodeNotAssigned:         http.StatusConflict,
+	apperr.CodeNoCandidate:         http.StatusConflict,
+	apperr.CodeNotFound:            http.StatusNotFound,
+	apperr.CodeInvalidStrategy:     http.StatusBadRequest,
+	apperr.CodeInvalidFallbackTeam: http.StatusBadRequest,
+	apperr.CodeInvalidRequest:      http.StatusBadRequest,
+	apperr.CodeUnauthorized:        http.StatusUnauthorized,
+	apperr.CodeForbidden:           http.StatusForbidden,
+	apperr.CodeInternal:            http.StatusInternalServerError,
+}
+
+// ErrorHandler middleware, преобразующее ошибку, добавленную ручкой через c.Error,
+// в ответ {"error": {"code", "message", "request_id"}}. Ошибки без доменного кода отдаются как INTERNAL
+// без текста причины
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		ctx := c.Request.Context()
+		log := logger.GetOrCreateLoggerFromCtx(ctx)
+		err := c.Errors.Last().Err
+
+		var appErr *apperr.Error
+		if !errors.As(err, &appErr) {
+			appErr = apperr.Wrap(err, apperr.CodeInternal, internalErrorMessage)
+		}
+		status, ok := statusByCode[appErr.Code]
+		if !ok {
+			status = http.StatusInternalServerError
+		}
+		message := appErr.Message
+		if status >= http.StatusInternalServerError {
+			log.Error(ctx, "request failed", zap.String("path", c.FullPath()), zap.Error(err))
+			message = internalErrorMessage
+		} else {
+			log.Info(ctx, "request rejected", zap.String("path", c.FullPath()), zap.String("code", string(appErr.Code)), zap.Error(err))
+		}
+
+		c.Set(errorCodeKey, appErr.Code)
+		abortWithError(c, status, appErr.Code, message)
+	}
+}
+
+// abortWithError прерывает запрос ответом {"error": {"code", "message", "request_id"}}
+func abortWithError(c *gin.Context, status int, code apperr.Code, message string) {
+	body := gin.H{"code": code, "message": message}
+	if requestID := logger.GetRequestIDFromCtx(c.Request.Context()); requestID != "" {
+		body["request_id"] = requestID
+	}
+	c.AbortWithStatusJSON(status, gin.H{"error": body})
+}
+
+// invalidRequest оборачивает ошибку разбора или валидации тела запроса
+func invalidRequest(err error) error {
+	return apperr.Wrap(err, apperr.CodeInvalidRequest, "invalid request body: "+err.Error())
+}
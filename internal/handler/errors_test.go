@@ -0,0 +1,88 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"avito-test-quest/internal/apperr"
+	"avito-test-quest/internal/handler"
+	"avito-test-quest/internal/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// errorBody конверт ошибки {"error": {"code", "message", "request_id"}}
+type errorBody struct {
+	Error struct {
+		Code      string `json:"code"`
+		Message   string `json:"message"`
+		RequestID string `json:"request_id"`
+	} `json:"error"`
+}
+
+func TestErrorHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	_, log, err := logger.New(context.Background(), logger.Config{Level: "fatal"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantCode    string
+		wantMessage string
+	}{
+		{name: "NOT_FOUND", err: apperr.New(apperr.CodeNotFound, "team not found"), wantStatus: http.StatusNotFound, wantCode: "NOT_FOUND", wantMessage: "team not found"},
+		{name: "TEAM_EXISTS", err: apperr.New(apperr.CodeTeamExists, "team_name already exists"), wantStatus: http.StatusBadRequest, wantCode: "TEAM_EXISTS", wantMessage: "team_name already exists"},
+		{name: "PR_MERGED", err: apperr.New(apperr.CodePRMerged, "cannot reassign on merged PR"), wantStatus: http.StatusConflict, wantCode: "PR_MERGED", wantMessage: "cannot reassign on merged PR"},
+		{name: "UNAUTHORIZED", err: apperr.New(apperr.CodeUnauthorized, "authentication required"), wantStatus: http.StatusUnauthorized, wantCode: "UNAUTHORIZED", wantMessage: "authentication required"},
+		{name: "FORBIDDEN", err: apperr.New(apperr.CodeForbidden, "admin role required"), wantStatus: http.StatusForbidden, wantCode: "FORBIDDEN", wantMessage: "admin role required"},
+		{name: "wrapped domain error", err: fmt.Errorf("tx: %w", apperr.New(apperr.CodeNoCandidate, "no candidate")), wantStatus: http.StatusConflict, wantCode: "NO_CANDIDATE", wantMessage: "no candidate"},
+		{name: "storage error hides cause", err: errors.New("connection reset"), wantStatus: http.StatusInternalServerError, wantCode: "INTERNAL", wantMessage: "internal server error"},
+		{name: "unknown code", err: apperr.New(apperr.Code("TEAPOT"), "secret detail"), wantStatus: http.StatusInternalServerError, wantCode: "TEAPOT", wantMessage: "internal server error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(handler.RequestID(log), handler.ErrorHandler())
+			router.GET("/fail", func(c *gin.Context) {
+				_ = c.Error(tt.err)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/fail", nil)
+			req.Header.Set(handler.RequestIDHeader, "req-1")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			var body errorBody
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, tt.wantCode, body.Error.Code)
+			assert.Equal(t, tt.wantMessage, body.Error.Message)
+			assert.Equal(t, "req-1", body.Error.RequestID)
+		})
+	}
+}
+
+func TestErrorHandlerKeepsWrittenResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(handler.ErrorHandler())
+	router.GET("/ok", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+		_ = c.Error(errors.New("late error"))
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ok", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+}
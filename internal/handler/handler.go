@@ -3,52 +3,70 @@ package handler
 import (
 	"net/http"
 
-	"avito-test-quest/internal/logger"
+	"avito-test-quest/internal/apperr"
+	"avito-test-quest/internal/auth"
+	"avito-test-quest/internal/health"
 	"avito-test-quest/internal/models"
 	"avito-test-quest/internal/service"
 
 	"github.com/gin-gonic/gin"
-	"go.uber.org/zap"
 )
 
 type PrHandler struct {
 	service service.Service
 	router  *gin.Engine
+	authn   auth.Authenticator
+	health  *health.Checker
 }
 
 // NewPrHandler создает новый экземпляр PrHandler
-func NewPrHandler(service service.Service, router *gin.Engine) AllHandlers {
+func NewPrHandler(service service.Service, router *gin.Engine, authn auth.Authenticator, checker *health.Checker) AllHandlers {
 	return &PrHandler{
 		service: service,
 		router:  router,
+		authn:   authn,
+		health:  checker,
 	}
 }
 
 // InitRoutes инициализирует все роуты приложения
 func (h *PrHandler) InitRoutes() {
-	// Health check
+	// ошибки всех ручек преобразуются в единый конверт {"error": {"code", "message", "request_id"}}
+	h.router.Use(ErrorHandler())
+
+	// защищенные ручки (security в openapi.yml) требуют токен, права по ролям проверяет сервис
+	authed := Authenticate(h.authn)
+
+	// Health check: /health оставлен как синоним liveness
 	h.router.GET("/health", h.HealthCheck)
+	h.router.GET("/health/live", h.HealthCheck)
+	h.router.GET("/health/ready", h.ReadinessCheck)
 
 	// ручки Teams
 	teamGroup := h.router.Group("/team")
 	{
-		teamGroup.POST("/add", h.CreateTeam)
-		teamGroup.GET("/get", h.GetTeam)
+		teamGroup.POST("/add", authed, h.CreateTeam)
+		teamGroup.GET("/get", authed, h.GetTeam)
+		teamGroup.GET("/settings", authed, h.GetTeamSettings)
+		teamGroup.PUT("/settings", authed, h.UpdateTeamSettings)
+		teamGroup.POST("/deactivateMembers", authed, h.DeactivateTeamMembers)
 	}
 
 	// ручки Users
 	usersGroup := h.router.Group("/users")
 	{
-		usersGroup.POST("/setIsActive", h.SetIsActive) // только для админов (если будет аутентификация)
-		usersGroup.GET("/getReview", h.GetUserReviews)
+		usersGroup.POST("/setIsActive", authed, h.SetIsActive)
+		usersGroup.POST("/setRole", authed, h.SetUserRole)
+		usersGroup.GET("/getReview", authed, h.GetUserReviews)
 	}
 
 	// ручки Pull Requests
 	prGroup := h.router.Group("/pullRequest")
 	{
-		prGroup.POST("/create", h.CreatePullRequest)  // только для админов (если будет аутентификация)
-		prGroup.POST("/merge", h.MergePullRequest)    // только для админов (если будет аутентификация)
-		prGroup.POST("/reassign", h.ReassignReviewer) // только для админов (если будет аутентификация)
+		prGroup.POST("/create", authed, h.CreatePullRequest)
+		prGroup.POST("/merge", authed, h.MergePullRequest)
+		prGroup.POST("/reassign", authed, h.ReassignReviewer)
+		prGroup.GET("/history", authed, h.GetPullRequestHistory)
 	}
 
 	// Stats endpoint
@@ -60,26 +78,15 @@ func (h *PrHandler) InitRoutes() {
 // CreateTeam создает команду с участниками
 func (h *PrHandler) CreateTeam(c *gin.Context) {
 	var input models.CreateTeamInput
-	ctx := c.Request.Context()
-
-	log := logger.GetOrCreateLoggerFromCtx(ctx)
 	if err := c.ShouldBindJSON(&input); err != nil {
-		log.Warn(ctx, "invalid create team request", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(invalidRequest(err))
 		return
 	}
 	// создаем команду
-	tm, err := h.service.CreateTeam(ctx, input)
+	tm, err := h.service.CreateTeam(c.Request.Context(), input)
 	if err != nil {
-		switch err.Error() {
-		case "TEAM_EXISTS":
-			c.JSON(http.StatusBadRequest, gin.H{"error": gin.H{"code": "TEAM_EXISTS", "message": "team_name already exists"}})
-			return
-		default:
-			log.Error(ctx, "create team failed", zap.Error(err))
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
+		_ = c.Error(err)
+		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{"team": tm})
@@ -87,49 +94,102 @@ func (h *PrHandler) CreateTeam(c *gin.Context) {
 
 // GetTeam получает команду по имени
 func (h *PrHandler) GetTeam(c *gin.Context) {
-	ctx := c.Request.Context()
-	log := logger.GetOrCreateLoggerFromCtx(ctx)
 	teamName := c.Query("team_name")
 	if teamName == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": gin.H{"code": "NOT_FOUND", "message": "team_name required"}})
+		_ = c.Error(apperr.New(apperr.CodeInvalidRequest, "team_name required"))
 		return
 	}
 	// получаем команду
-	tm, err := h.service.GetTeam(ctx, teamName)
+	tm, err := h.service.GetTeam(c.Request.Context(), teamName)
 	if err != nil {
-		if err.Error() == "NOT_FOUND" {
-			c.JSON(http.StatusNotFound, gin.H{"error": gin.H{"code": "NOT_FOUND", "message": "team not found"}})
-			return
-		}
-		log.Error(ctx, "get team failed", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, tm)
 }
 
+// GetTeamSettings получает настройки назначения ревьюверов команды
+func (h *PrHandler) GetTeamSettings(c *gin.Context) {
+	teamName := c.Query("team_name")
+	if teamName == "" {
+		_ = c.Error(apperr.New(apperr.CodeInvalidRequest, "team_name required"))
+		return
+	}
+	// получаем настройки команды
+	settings, err := h.service.GetTeamSettings(c.Request.Context(), teamName)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
+
+// UpdateTeamSettings изменяет настройки назначения ревьюверов команды
+func (h *PrHandler) UpdateTeamSettings(c *gin.Context) {
+	var input models.UpdateTeamSettingsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		_ = c.Error(invalidRequest(err))
+		return
+	}
+	// обновляем настройки команды
+	settings, err := h.service.UpdateTeamSettings(c.Request.Context(), input)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
+
+// DeactivateTeamMembers деактивирует участников команды и переназначает их открытые ревью
+func (h *PrHandler) DeactivateTeamMembers(c *gin.Context) {
+	var input models.DeactivateTeamMembersInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		_ = c.Error(invalidRequest(err))
+		return
+	}
+	// деактивируем участников и переназначаем ревью
+	out, err := h.service.DeactivateTeamMembers(c.Request.Context(), input)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
 // ==================== User Handlers ====================
 
 // SetIsActive устанавливает флаг активности пользователя
 func (h *PrHandler) SetIsActive(c *gin.Context) {
 	var input models.SetIsActiveInput
-	ctx := c.Request.Context()
-	log := logger.GetOrCreateLoggerFromCtx(ctx)
 	if err := c.ShouldBindJSON(&input); err != nil {
-		log.Warn(ctx, "invalid setIsActive request", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(invalidRequest(err))
 		return
 	}
 	// устанавливаем is_active
-	u, err := h.service.SetIsActive(ctx, input)
+	u, err := h.service.SetIsActive(c.Request.Context(), input)
 	if err != nil {
-		if err.Error() == "NOT_FOUND" {
-			c.JSON(http.StatusNotFound, gin.H{"error": gin.H{"code": "NOT_FOUND", "message": "user not found"}})
-			return
-		}
-		log.Error(ctx, "set is_active failed", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user": u})
+}
+
+// SetUserRole устанавливает роль пользователя
+func (h *PrHandler) SetUserRole(c *gin.Context) {
+	var input models.SetRoleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		_ = c.Error(invalidRequest(err))
+		return
+	}
+	// устанавливаем роль
+	u, err := h.service.SetUserRole(c.Request.Context(), input)
+	if err != nil {
+		_ = c.Error(err)
 		return
 	}
 
@@ -138,22 +198,15 @@ func (h *PrHandler) SetIsActive(c *gin.Context) {
 
 // GetUserReviews получает список PR, где пользователь назначен ревьювером
 func (h *PrHandler) GetUserReviews(c *gin.Context) {
-	ctx := c.Request.Context()
-	log := logger.GetOrCreateLoggerFromCtx(ctx)
 	userID := c.Query("user_id")
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": gin.H{"code": "NOT_FOUND", "message": "user_id required"}})
+		_ = c.Error(apperr.New(apperr.CodeInvalidRequest, "user_id required"))
 		return
 	}
 	// получаем список PR ревьювера
-	out, err := h.service.GetUserReviews(ctx, userID)
+	out, err := h.service.GetUserReviews(c.Request.Context(), userID)
 	if err != nil {
-		if err.Error() == "NOT_FOUND" {
-			c.JSON(http.StatusNotFound, gin.H{"error": gin.H{"code": "NOT_FOUND", "message": "user not found"}})
-			return
-		}
-		log.Error(ctx, "get user reviews failed", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
@@ -162,31 +215,18 @@ func (h *PrHandler) GetUserReviews(c *gin.Context) {
 
 // ==================== Pull Request Handlers ====================
 
-// CreatePullRequest создает PR и назначает до 2 ревьюверов
+// CreatePullRequest создает PR и назначает ревьюверов по настройкам команды автора
 func (h *PrHandler) CreatePullRequest(c *gin.Context) {
 	var input models.CreatePullRequestInput
-	ctx := c.Request.Context()
-	log := logger.GetOrCreateLoggerFromCtx(ctx)
 	if err := c.ShouldBindJSON(&input); err != nil {
-		log.Warn(ctx, "invalid create pr request", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(invalidRequest(err))
 		return
 	}
 	// создаем PR
-	pr, err := h.service.CreatePullRequest(ctx, input)
+	pr, err := h.service.CreatePullRequest(c.Request.Context(), input)
 	if err != nil {
-		switch err.Error() {
-		case "NOT_FOUND":
-			c.JSON(http.StatusNotFound, gin.H{"error": gin.H{"code": "NOT_FOUND", "message": "author or team not found"}})
-			return
-		case "PR_EXISTS":
-			c.JSON(http.StatusConflict, gin.H{"error": gin.H{"code": "PR_EXISTS", "message": "PR id already exists"}})
-			return
-		default:
-			log.Error(ctx, "create pr failed", zap.Error(err))
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
+		_ = c.Error(err)
+		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{"pr": pr})
@@ -195,22 +235,14 @@ func (h *PrHandler) CreatePullRequest(c *gin.Context) {
 // MergePullRequest помечает PR как MERGED (идемпотентно)
 func (h *PrHandler) MergePullRequest(c *gin.Context) {
 	var input models.MergePullRequestInput
-	ctx := c.Request.Context()
-	log := logger.GetOrCreateLoggerFromCtx(ctx)
 	if err := c.ShouldBindJSON(&input); err != nil {
-		log.Warn(ctx, "invalid merge pr request", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(invalidRequest(err))
 		return
 	}
 	// мержим PR
-	pr, err := h.service.MergePullRequest(ctx, input)
+	pr, err := h.service.MergePullRequest(c.Request.Context(), input)
 	if err != nil {
-		if err.Error() == "NOT_FOUND" {
-			c.JSON(http.StatusNotFound, gin.H{"error": gin.H{"code": "NOT_FOUND", "message": "pr not found"}})
-			return
-		}
-		log.Error(ctx, "merge pr failed", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
@@ -220,57 +252,62 @@ func (h *PrHandler) MergePullRequest(c *gin.Context) {
 // ReassignReviewer переназначает ревьювера на другого из команды
 func (h *PrHandler) ReassignReviewer(c *gin.Context) {
 	var input models.ReassignReviewerInput
-	ctx := c.Request.Context()
-	log := logger.GetOrCreateLoggerFromCtx(ctx)
 	if err := c.ShouldBindJSON(&input); err != nil {
-		log.Warn(ctx, "invalid reassign request", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		_ = c.Error(invalidRequest(err))
 		return
 	}
 	// переназначаем ревьювера
-	out, err := h.service.ReassignReviewer(ctx, input)
+	out, err := h.service.ReassignReviewer(c.Request.Context(), input)
 	if err != nil {
-		switch err.Error() {
-		case "NOT_FOUND":
-			c.JSON(http.StatusNotFound, gin.H{"error": gin.H{"code": "NOT_FOUND", "message": "pr or user not found"}})
-			return
-		case "PR_MERGED":
-			c.JSON(http.StatusConflict, gin.H{"error": gin.H{"code": "PR_MERGED", "message": "cannot reassign on merged PR"}})
-			return
-		case "NOT_ASSIGNED":
-			c.JSON(http.StatusConflict, gin.H{"error": gin.H{"code": "NOT_ASSIGNED", "message": "reviewer is not assigned to this PR"}})
-			return
-		case "NO_CANDIDATE":
-			c.JSON(http.StatusConflict, gin.H{"error": gin.H{"code": "NO_CANDIDATE", "message": "no active replacement candidate in team"}})
-			return
-		default:
-			log.Error(ctx, "reassign failed", zap.Error(err))
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
-		}
+		_ = c.Error(err)
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"pr": out.PR, "replaced_by": out.ReplacedBy})
 }
 
+// GetPullRequestHistory получает историю назначений ревьюверов PR
+func (h *PrHandler) GetPullRequestHistory(c *gin.Context) {
+	prID := c.Query("pull_request_id")
+	if prID == "" {
+		_ = c.Error(apperr.New(apperr.CodeInvalidRequest, "pull_request_id required"))
+		return
+	}
+	// получаем историю назначений
+	out, err := h.service.GetPullRequestHistory(c.Request.Context(), prID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
 // ==================== Health Handler ====================
 
-// HealthCheck проверка здоровья сервиса
+// HealthCheck liveness: процесс жив и обслуживает HTTP, зависимости не проверяются
 func (h *PrHandler) HealthCheck(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"status": "ok"})
 }
 
+// ReadinessCheck readiness: БД доступна, схема актуальна, сервис не останавливается
+func (h *PrHandler) ReadinessCheck(c *gin.Context) {
+	report := h.health.Ready(c.Request.Context())
+	status := http.StatusOK
+	if report.Status != health.StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+
+	c.JSON(status, report)
+}
+
 // ==================== Stats Handler ====================
 
 // GetStats получить статистику по ревьюверам и PR
 func (h *PrHandler) GetStats(c *gin.Context) {
-	ctx := c.Request.Context()
-	log := logger.GetOrCreateLoggerFromCtx(ctx)
-
-	stats, err := h.service.GetStats(ctx)
+	stats, err := h.service.GetStats(c.Request.Context())
 	if err != nil {
-		log.Error(ctx, "failed to get stats", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		_ = c.Error(err)
 		return
 	}
 
@@ -19,14 +19,30 @@ type TeamHandler interface {
 	// GetTeam GET /team/get
 	// Получить команду с участниками по team_name (query param)
 	GetTeam(c *gin.Context)
+
+	// GetTeamSettings GET /team/settings
+	// Получить настройки назначения ревьюверов команды по team_name (query param)
+	GetTeamSettings(c *gin.Context)
+
+	// UpdateTeamSettings PUT /team/settings
+	// Изменить настройки назначения ревьюверов команды
+	UpdateTeamSettings(c *gin.Context)
+
+	// DeactivateTeamMembers POST /team/deactivateMembers
+	// Деактивировать участников команды и переназначить их открытые ревью (требует Admin токен)
+	DeactivateTeamMembers(c *gin.Context)
 }
 
 // UserHandler интерфейс для работы с пользователями
 type UserHandler interface {
 	// SetIsActive POST /users/setIsActive
-	// Установить флаг активности пользователя (требует Admin токен)
+	// Установить флаг активности пользователя (админ или тимлид команды пользователя)
 	SetIsActive(c *gin.Context)
 
+	// SetUserRole POST /users/setRole
+	// Установить роль пользователя: admin, team_lead, member (только админ)
+	SetUserRole(c *gin.Context)
+
 	// GetUserReviews GET /users/getReview
 	// Получить PR'ы, где пользователь назначен ревьювером (query param: user_id)
 	GetUserReviews(c *gin.Context)
@@ -35,7 +51,7 @@ type UserHandler interface {
 // PullRequestHandler интерфейс для работы с Pull Request'ами
 type PullRequestHandler interface {
 	// CreatePullRequest POST /pullRequest/create
-	// Создать PR и автоматически назначить до 2 ревьюверов из команды автора
+	// Создать PR и автоматически назначить ревьюверов из команды автора по настройкам команды
 	CreatePullRequest(c *gin.Context)
 
 	// MergePullRequest POST /pullRequest/merge
@@ -45,13 +61,21 @@ type PullRequestHandler interface {
 	// ReassignReviewer POST /pullRequest/reassign
 	// Переназначить конкретного ревьювера на другого из его команды
 	ReassignReviewer(c *gin.Context)
+
+	// GetPullRequestHistory GET /pullRequest/history
+	// Получить историю назначений ревьюверов PR (query param: pull_request_id)
+	GetPullRequestHistory(c *gin.Context)
 }
 
 // HealthHandler интерфейс для health check
 type HealthHandler interface {
-	// HealthCheck GET /health
-	// Проверка состояния сервиса
+	// HealthCheck GET /health, GET /health/live
+	// Проверка, что процесс жив
 	HealthCheck(c *gin.Context)
+
+	// ReadinessCheck GET /health/ready
+	// Проверка готовности принимать трафик: БД, версия миграций, заполненность пула Postgres
+	ReadinessCheck(c *gin.Context)
 }
 
 // StatsHandler интерфейс для получения статистики
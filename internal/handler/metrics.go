@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"time"
+
+	"avito-test-quest/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics middleware, записывающее длительность запроса в гистограмму по методу, маршруту и статусу.
+// Маршрут берется шаблоном gin, чтобы неизвестные пути не раздували кардинальность
+func Metrics(m *metrics.Metrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		m.ObserveHTTPRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"avito-test-quest/internal/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RequestIDHeader заголовок с идентификатором запроса
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength ограничение на длину принимаемого идентификатора
+const maxRequestIDLength = 128
+
+// RequestID middleware, принимающее X-Request-ID от клиента или генерирующее новый.
+// Идентификатор и логгер запроса кладутся в c.Request.Context(), идентификатор возвращается в ответе
+func RequestID(log *logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+		c.Header(RequestIDHeader, requestID)
+
+		// requestID в поля добавляют сами методы Logger, здесь — атрибуты запроса
+		reqLog := log.With(zap.String("method", c.Request.Method), zap.String("path", c.Request.URL.Path))
+		ctx := logger.WithRequestID(logger.WithLogger(c.Request.Context(), reqLog), requestID)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// validRequestID допускаются только короткие идентификаторы из безопасных символов,
+// чтобы клиент не мог подделать строки логов
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID генерирует случайный идентификатор из 16 байт
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
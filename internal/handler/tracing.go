@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"net/http"
+
+	"avito-test-quest/internal/apperr"
+	"avito-test-quest/internal/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Tracing middleware, открывающее серверный спан запроса и продолжающее трассу из заголовка traceparent.
+// Спан кладется в c.Request.Context(), поэтому сервис и репозиторий создают дочерние спаны.
+// Подключается после RequestID: идентификатор трассы добавляется в логгер запроса
+func Tracing() gin.HandlerFunc {
+	tracer := otel.Tracer("avito-test-quest/internal/handler")
+	return func(c *gin.Context) {
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		// шаблон маршрута известен до выполнения цепочки обработчиков
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		ctx, span := tracer.Start(ctx, c.Request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Request.Method),
+				semconv.HTTPRoute(route),
+				semconv.URLPath(c.Request.URL.Path),
+				attribute.String("app.request_id", logger.GetRequestIDFromCtx(ctx)),
+			),
+		)
+		defer span.End()
+
+		if sc := span.SpanContext(); sc.IsValid() {
+			log := logger.GetOrCreateLoggerFromCtx(ctx).With(zap.String("traceID", sc.TraceID().String()))
+			ctx = logger.WithLogger(ctx, log)
+		}
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if code, ok := c.Value(errorCodeKey).(apperr.Code); ok {
+			span.SetAttributes(attribute.String("app.error_code", string(code)))
+		}
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// статусы проверок готовности
+const (
+	StatusOK           = "ok"
+	StatusUnavailable  = "unavailable"
+	StatusShuttingDown = "shutting_down"
+)
+
+// pingTimeout ограничение на проверку БД, чтобы зависшая БД не держала пробу балансировщика
+const pingTimeout = 2 * time.Second
+
+// Database хранилище, готовность которого проверяется: доступность и примененная версия схемы
+type Database interface {
+	Ping(ctx context.Context) error
+	MigrationVersion(ctx context.Context) (version uint, dirty bool, err error)
+}
+
+// poolStater хранилище с пулом соединений; статистика пула есть только у Postgres
+type poolStater interface {
+	Stat() *pgxpool.Stat
+}
+
+// Report результат проверки готовности
+// Проверки БД отсутствуют, если сервис работает с хранилищем в памяти, pool — только для Postgres
+type Report struct {
+	Status     string           `json:"status"`
+	Database   *DatabaseCheck   `json:"database,omitempty"`
+	Migrations *MigrationsCheck `json:"migrations,omitempty"`
+	Pool       *PoolCheck       `json:"pool,omitempty"`
+}
+
+// DatabaseCheck доступность БД
+type DatabaseCheck struct {
+	Status  string `json:"status"`
+	Latency string `json:"latency"`
+	Error   string `json:"error,omitempty"`
+}
+
+// MigrationsCheck версия схемы относительно встроенных миграций
+type MigrationsCheck struct {
+	Status   string `json:"status"`
+	Version  uint   `json:"version"`
+	Expected uint   `json:"expected"`
+	Dirty    bool   `json:"dirty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// PoolCheck заполненность пула соединений; на готовность не влияет
+type PoolCheck struct {
+	Acquired   int32   `json:"acquired"`
+	Idle       int32   `json:"idle"`
+	Total      int32   `json:"total"`
+	Max        int32   `json:"max"`
+	Saturation float64 `json:"saturation"` // acquired / max
+}
+
+// Checker проверяет готовность сервиса принимать трафик
+type Checker struct {
+	db           Database // nil — хранилище в памяти, внешних зависимостей нет
+	expected     uint
+	shuttingDown atomic.Bool
+}
+
+// NewChecker создает проверку готовности; expected — последняя встроенная миграция выбранного хранилища.
+// С db == nil проверяется только остановка сервиса
+func NewChecker(db Database, expected uint) *Checker {
+	return &Checker{db: db, expected: expected}
+}
+
+// SetShuttingDown переводит проверку готовности в состояние shutting_down:
+// балансировщик выводит экземпляр из ротации до остановки HTTP сервера
+func (c *Checker) SetShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+// Ready проверяет БД и версию схемы. Сервис готов, если БД отвечает, миграции не в состоянии dirty
+// и схема не старее встроенных миграций (более новая допустима при поэтапном обновлении)
+func (c *Checker) Ready(ctx context.Context) Report {
+	report := Report{Status: StatusOK}
+	if c.db != nil {
+		c.checkDatabase(ctx, &report)
+	}
+	if c.shuttingDown.Load() {
+		report.Status = StatusShuttingDown
+	}
+	return report
+}
+
+// checkDatabase дополняет отчет проверками БД, схемы и, если есть, пула
+func (c *Checker) checkDatabase(ctx context.Context, report *Report) {
+	if p, ok := c.db.(poolStater); ok {
+		pool := poolCheck(p.Stat())
+		report.Pool = &pool
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+
+	started := time.Now()
+	err := c.db.Ping(ctx)
+	report.Database = &DatabaseCheck{Status: StatusOK, Latency: time.Since(started).String()}
+	if err != nil {
+		report.Database.Status = StatusUnavailable
+		report.Database.Error = err.Error()
+		report.Status = StatusUnavailable
+	}
+
+	// без соединения версию схемы не узнать, ошибку подключения не дублируем
+	migrations := MigrationsCheck{Status: StatusUnavailable, Expected: c.expected, Error: "database unavailable"}
+	if err == nil {
+		migrations = c.migrationsCheck(ctx)
+	}
+	report.Migrations = &migrations
+	if migrations.Status != StatusOK {
+		report.Status = StatusUnavailable
+	}
+}
+
+// migrationsCheck сравнивает примененную версию схемы с ожидаемой
+func (c *Checker) migrationsCheck(ctx context.Context) MigrationsCheck {
+	check := MigrationsCheck{Status: StatusOK, Expected: c.expected}
+	version, dirty, err := c.db.MigrationVersion(ctx)
+	if err != nil {
+		check.Status = StatusUnavailable
+		check.Error = err.Error()
+		return check
+	}
+	check.Version, check.Dirty = version, dirty
+	switch {
+	case dirty:
+		check.Status = StatusUnavailable
+		check.Error = fmt.Sprintf("migration %d is dirty", version)
+	case version < c.expected:
+		check.Status = StatusUnavailable
+		check.Error = fmt.Sprintf("schema version %d is behind %d", version, c.expected)
+	}
+	return check
+}
+
+// poolCheck переводит статистику пула в отчет
+func poolCheck(s *pgxpool.Stat) PoolCheck {
+	check := PoolCheck{Acquired: s.AcquiredConns(), Idle: s.IdleConns(), Total: s.TotalConns(), Max: s.MaxConns()}
+	if check.Max > 0 {
+		check.Saturation = float64(check.Acquired) / float64(check.Max)
+	}
+	return check
+}
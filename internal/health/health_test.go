@@ -0,0 +1,112 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"avito-test-quest/internal/health"
+	"avito-test-quest/internal/sqlite"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openSQLite открывает временную БД SQLite, при migrate — с примененными миграциями
+func openSQLite(t *testing.T, migrate bool) sqlite.DB {
+	t.Helper()
+	cfg := sqlite.Config{Path: filepath.Join(t.TempDir(), "health.db")}
+	if migrate {
+		require.NoError(t, sqlite.Migrate(context.Background(), cfg))
+	}
+	db, err := sqlite.New(context.Background(), cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	return sqlite.DB{DB: db}
+}
+
+// failingDB БД, которая не отвечает на ping
+type failingDB struct{}
+
+func (failingDB) Ping(context.Context) error { return errors.New("connection refused") }
+
+func (failingDB) MigrationVersion(context.Context) (uint, bool, error) {
+	return 0, false, errors.New("unexpected call")
+}
+
+func TestReadyMemory(t *testing.T) {
+	c := health.NewChecker(nil, 0)
+
+	report := c.Ready(context.Background())
+	assert.Equal(t, health.Report{Status: health.StatusOK}, report)
+
+	c.SetShuttingDown()
+	assert.Equal(t, health.StatusShuttingDown, c.Ready(context.Background()).Status)
+}
+
+func TestReadySQLite(t *testing.T) {
+	expected, err := sqlite.LatestMigrationVersion()
+	require.NoError(t, err)
+	require.Positive(t, expected)
+
+	t.Run("migrated", func(t *testing.T) {
+		report := health.NewChecker(openSQLite(t, true), expected).Ready(context.Background())
+
+		assert.Equal(t, health.StatusOK, report.Status)
+		require.NotNil(t, report.Database)
+		assert.Equal(t, health.StatusOK, report.Database.Status)
+		require.NotNil(t, report.Migrations)
+		assert.Equal(t, health.MigrationsCheck{Status: health.StatusOK, Version: expected, Expected: expected}, *report.Migrations)
+		assert.Nil(t, report.Pool, "у SQLite нет пула соединений")
+	})
+
+	t.Run("not migrated", func(t *testing.T) {
+		report := health.NewChecker(openSQLite(t, false), expected).Ready(context.Background())
+
+		assert.Equal(t, health.StatusUnavailable, report.Status)
+		assert.Equal(t, health.StatusOK, report.Database.Status)
+		assert.Equal(t, health.StatusUnavailable, report.Migrations.Status)
+		assert.NotEmpty(t, report.Migrations.Error)
+	})
+
+	t.Run("schema behind", func(t *testing.T) {
+		db := openSQLite(t, true)
+		_, err := db.Exec("UPDATE schema_migrations SET version = version - 1")
+		require.NoError(t, err)
+
+		report := health.NewChecker(db, expected).Ready(context.Background())
+
+		assert.Equal(t, health.StatusUnavailable, report.Status)
+		assert.Equal(t, health.StatusUnavailable, report.Migrations.Status)
+		assert.Equal(t, expected-1, report.Migrations.Version)
+	})
+
+	t.Run("dirty", func(t *testing.T) {
+		db := openSQLite(t, true)
+		_, err := db.Exec("UPDATE schema_migrations SET dirty = 1")
+		require.NoError(t, err)
+
+		report := health.NewChecker(db, expected).Ready(context.Background())
+
+		assert.Equal(t, health.StatusUnavailable, report.Status)
+		assert.True(t, report.Migrations.Dirty)
+		assert.Equal(t, health.StatusUnavailable, report.Migrations.Status)
+	})
+
+	t.Run("newer schema", func(t *testing.T) {
+		report := health.NewChecker(openSQLite(t, true), expected-1).Ready(context.Background())
+
+		assert.Equal(t, health.StatusOK, report.Status)
+	})
+}
+
+func TestReadyDatabaseUnavailable(t *testing.T) {
+	report := health.NewChecker(failingDB{}, 1).Ready(context.Background())
+
+	assert.Equal(t, health.StatusUnavailable, report.Status)
+	assert.Equal(t, health.StatusUnavailable, report.Database.Status)
+	assert.Equal(t, "connection refused", report.Database.Error)
+	assert.Equal(t, health.StatusUnavailable, report.Migrations.Status)
+	assert.Nil(t, report.Pool)
+}
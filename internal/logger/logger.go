@@ -2,8 +2,10 @@ package logger
 
 import (
 	"context"
+	"fmt"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 type key string
@@ -13,20 +15,65 @@ const (
 	KeyForRequestID key = "requestID"
 )
 
+// Config содержит настройки логирования
+type Config struct {
+	// Level минимальный уровень: debug, info, warn, error
+	Level string `yaml:"level" env:"LOG_LEVEL" env-default:"info"`
+	// Encoding формат вывода: json или console
+	Encoding string `yaml:"encoding" env:"LOG_ENCODING" env-default:"json"`
+}
+
 // Logger обёртка над zap.Logger
 type Logger struct {
 	l *zap.Logger
 }
 
-// New создаёт новый логгер и добавляет его в контекст
-func New(ctx context.Context) (context.Context, *Logger, error) {
-	l, err := zap.NewProduction()
+// New создаёт новый логгер по настройкам и добавляет его в контекст
+func New(ctx context.Context, cfg Config) (context.Context, *Logger, error) {
+	zcfg := zap.NewProductionConfig()
+	if cfg.Level != "" {
+		level, err := zap.ParseAtomicLevel(cfg.Level)
+		if err != nil {
+			return ctx, nil, fmt.Errorf("invalid log level %q: %w", cfg.Level, err)
+		}
+		zcfg.Level = level
+	}
+	switch cfg.Encoding {
+	case "", "json":
+	case "console":
+		zcfg.Encoding = "console"
+		zcfg.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	default:
+		return ctx, nil, fmt.Errorf("invalid log encoding %q: expected json or console", cfg.Encoding)
+	}
+	// пропускаем кадр обёртки, чтобы caller указывал на место вызова Logger
+	l, err := zcfg.Build(zap.AddCallerSkip(1))
 	if err != nil {
 		return ctx, nil, err
 	}
 	wrapped := &Logger{l: l}
-	ctx = context.WithValue(ctx, KeyForLogger, wrapped)
-	return ctx, wrapped, nil
+	return WithLogger(ctx, wrapped), wrapped, nil
+}
+
+// WithLogger добавляет логгер в контекст
+func WithLogger(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, KeyForLogger, l)
+}
+
+// WithRequestID добавляет идентификатор запроса в контекст, все методы Logger добавляют его в поля
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, KeyForRequestID, requestID)
+}
+
+// GetRequestIDFromCtx достаёт идентификатор запроса из контекста
+func GetRequestIDFromCtx(ctx context.Context) string {
+	id, _ := ctx.Value(KeyForRequestID).(string)
+	return id
+}
+
+// With возвращает дочерний логгер с дополнительными полями
+func (l *Logger) With(fields ...zap.Field) *Logger {
+	return &Logger{l: l.l.With(fields...)}
 }
 
 // GetLoggerFromCtx безопасно достаёт логгер из контекста
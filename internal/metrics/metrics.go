@@ -0,0 +1,145 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace префикс всех метрик сервиса
+const namespace = "pr_reviewer"
+
+// операции, в которых назначаются и переназначаются ревьюверы (значение label operation)
+const (
+	OperationCreate     = "create"
+	OperationTopUp      = "top_up"
+	OperationReassign   = "reassign"
+	OperationDeactivate = "deactivate"
+)
+
+// Metrics метрики сервиса в собственном реестре: несколько экземпляров приложения
+// в одном процессе (интеграционные тесты) не конфликтуют при регистрации.
+// Методы безопасны для nil-получателя, тогда метрики не собираются
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpDuration  *prometheus.HistogramVec
+	dbDuration    *prometheus.HistogramVec
+	assignments   *prometheus.CounterVec
+	reassignments *prometheus.CounterVec
+	noCandidate   *prometheus.CounterVec
+	merges        prometheus.Counter
+}
+
+// New создает метрики и регистрирует их вместе со стандартными метриками процесса и Go runtime
+func New() *Metrics {
+	// бакеты сгущаются около SLI в 300 мс
+	latencyBuckets := []float64{.005, .01, .025, .05, .1, .2, .3, .5, 1, 2.5, 5}
+
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Длительность HTTP запросов по маршруту, методу и статусу",
+			Buckets:   latencyBuckets,
+		}, []string{"method", "route", "status"}),
+		dbDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_query_duration_seconds",
+			Help:      "Длительность запросов к Postgres по типу запроса и таблице",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+		}, []string{"query", "success"}),
+		assignments: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reviewer_assignments_total",
+			Help:      "Назначенные ревьюверы",
+		}, []string{"operation", "fallback"}),
+		reassignments: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reviewer_reassignments_total",
+			Help:      "Переназначения ревьюверов",
+		}, []string{"operation", "fallback"}),
+		noCandidate: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "no_candidate_total",
+			Help:      "Ревью, для которых не нашлось активного кандидата",
+		}, []string{"operation"}),
+		merges: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "pull_request_merges_total",
+			Help:      "Замерженные PR (повторный мерж не учитывается)",
+		}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpDuration, m.dbDuration, m.assignments, m.reassignments, m.noCandidate, m.merges,
+	)
+	return m
+}
+
+// Register регистрирует дополнительный коллектор, например статистику пула соединений
+func (m *Metrics) Register(c prometheus.Collector) error {
+	if m == nil {
+		return nil
+	}
+	return m.registry.Register(c)
+}
+
+// Handler отдает метрики в формате Prometheus
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// ObserveHTTPRequest учитывает HTTP запрос
+func (m *Metrics) ObserveHTTPRequest(method, route string, status int, d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.httpDuration.WithLabelValues(method, route, strconv.Itoa(status)).Observe(d.Seconds())
+}
+
+// ObserveDBQuery учитывает запрос к БД
+func (m *Metrics) ObserveDBQuery(query string, success bool, d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.dbDuration.WithLabelValues(query, strconv.FormatBool(success)).Observe(d.Seconds())
+}
+
+// AddAssignments учитывает назначенных ревьюверов
+func (m *Metrics) AddAssignments(operation string, fallback bool, n int) {
+	if m == nil || n == 0 {
+		return
+	}
+	m.assignments.WithLabelValues(operation, strconv.FormatBool(fallback)).Add(float64(n))
+}
+
+// AddReassignments учитывает переназначения ревьюверов
+func (m *Metrics) AddReassignments(operation string, fallback bool, n int) {
+	if m == nil || n == 0 {
+		return
+	}
+	m.reassignments.WithLabelValues(operation, strconv.FormatBool(fallback)).Add(float64(n))
+}
+
+// AddNoCandidate учитывает ревью без кандидата на замену
+func (m *Metrics) AddNoCandidate(operation string, n int) {
+	if m == nil || n == 0 {
+		return
+	}
+	m.noCandidate.WithLabelValues(operation).Add(float64(n))
+}
+
+// IncMerges учитывает мерж PR
+func (m *Metrics) IncMerges() {
+	if m == nil {
+		return
+	}
+	m.merges.Inc()
+}
@@ -0,0 +1,163 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// queryTraceKey ключ контекста с началом и меткой выполняемого запроса
+type queryTraceKey struct{}
+
+// queryTrace начало и метка запроса между TraceQueryStart и TraceQueryEnd
+type queryTrace struct {
+	start time.Time
+	label string
+}
+
+// QueryTracer pgx.QueryTracer, измеряющий длительность каждого запроса.
+// Метка запроса — команда SQL и таблица ("select users"), а не полный текст:
+// squirrel строит запросы с разным числом плейсхолдеров, и кардинальность была бы неограниченной
+type QueryTracer struct {
+	metrics *Metrics
+}
+
+// NewQueryTracer создает трейсер для pgx.ConnConfig.Tracer
+func NewQueryTracer(m *Metrics) *QueryTracer {
+	return &QueryTracer{metrics: m}
+}
+
+// TraceQueryStart запоминает начало запроса в контексте
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryTraceKey{}, queryTrace{start: time.Now(), label: QueryLabel(data.SQL)})
+}
+
+// TraceQueryEnd записывает длительность запроса
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	qt, ok := ctx.Value(queryTraceKey{}).(queryTrace)
+	if !ok {
+		return
+	}
+	t.metrics.ObserveDBQuery(qt.label, data.Err == nil, time.Since(qt.start))
+}
+
+// QueryLabel сводит текст SQL к команде и первой таблице: "select users", "insert pr_reviewers".
+// Для запросов с CTE берется основная команда после WITH
+func QueryLabel(sql string) string {
+	fields := strings.Fields(strings.ToLower(sql))
+	if len(fields) == 0 {
+		return "unknown"
+	}
+	verb := fields[0]
+	if verb == "with" {
+		// пропускаем определения CTE: основная команда идет после закрытия последней скобки верхнего уровня
+		depth := 0
+		for i, f := range fields[1:] {
+			depth += strings.Count(f, "(") - strings.Count(f, ")")
+			if depth == 0 && i+2 < len(fields) && isVerb(fields[i+2]) {
+				verb = fields[i+2]
+				fields = fields[i+2:]
+				break
+			}
+		}
+		if verb == "with" {
+			return "with"
+		}
+	}
+
+	var tableKeyword string
+	switch verb {
+	case "select", "delete":
+		tableKeyword = "from"
+	case "insert":
+		tableKeyword = "into"
+	case "update":
+		if len(fields) > 1 {
+			return verb + " " + tableName(fields[1])
+		}
+		return verb
+	default:
+		return verb
+	}
+	for i, f := range fields[:len(fields)-1] {
+		if f == tableKeyword && !strings.HasPrefix(fields[i+1], "(") {
+			return verb + " " + tableName(fields[i+1])
+		}
+	}
+	return verb
+}
+
+// isVerb проверяет, начинает ли слово команду SQL
+func isVerb(word string) bool {
+	switch word {
+	case "select", "insert", "update", "delete":
+		return true
+	}
+	return false
+}
+
+// tableName отрезает от имени таблицы кавычки и пунктуацию
+func tableName(word string) string {
+	return strings.Trim(word, `"(),;`)
+}
+
+// poolCollector отдает статистику пула соединений на каждый scrape
+type poolCollector struct {
+	pool *pgxpool.Pool
+
+	acquired      *prometheus.Desc
+	idle          *prometheus.Desc
+	total         *prometheus.Desc
+	max           *prometheus.Desc
+	acquireCount  *prometheus.Desc
+	acquireWait   *prometheus.Desc
+	emptyAcquire  *prometheus.Desc
+	canceledWaits *prometheus.Desc
+}
+
+// NewPoolCollector создает коллектор статистики пула pgxpool
+func NewPoolCollector(pool *pgxpool.Pool) prometheus.Collector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", name), help, nil, nil)
+	}
+	return &poolCollector{
+		pool:          pool,
+		acquired:      desc("acquired_connections", "Соединения, выданные из пула"),
+		idle:          desc("idle_connections", "Свободные соединения в пуле"),
+		total:         desc("total_connections", "Все соединения пула"),
+		max:           desc("max_connections", "Максимальный размер пула"),
+		acquireCount:  desc("acquires_total", "Успешные получения соединения из пула"),
+		acquireWait:   desc("acquire_wait_seconds_total", "Суммарное ожидание свободного соединения"),
+		emptyAcquire:  desc("empty_acquires_total", "Получения соединения, которым пришлось ждать"),
+		canceledWaits: desc("canceled_acquires_total", "Получения соединения, отмененные контекстом"),
+	}
+}
+
+// Describe реализует prometheus.Collector
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquired
+	ch <- c.idle
+	ch <- c.total
+	ch <- c.max
+	ch <- c.acquireCount
+	ch <- c.acquireWait
+	ch <- c.emptyAcquire
+	ch <- c.canceledWaits
+}
+
+// Collect реализует prometheus.Collector
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(c.acquired, prometheus.GaugeValue, float64(s.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(s.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.total, prometheus.GaugeValue, float64(s.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.max, prometheus.GaugeValue, float64(s.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquireCount, prometheus.CounterValue, float64(s.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireWait, prometheus.CounterValue, s.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquire, prometheus.CounterValue, float64(s.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.canceledWaits, prometheus.CounterValue, float64(s.CanceledAcquireCount()))
+}
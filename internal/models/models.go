@@ -1,5 +1,12 @@
 package models
 
+// стратегии выбора ревьюверов: assignment.strategy в конфигурации и TeamSettings.Strategy
+const (
+	StrategyRandom      = "random"
+	StrategyRoundRobin  = "round_robin"
+	StrategyLeastLoaded = "least_loaded"
+)
+
 // Team представляет команду с участниками
 type Team struct {
 	TeamName string       `json:"team_name"`
@@ -13,12 +20,22 @@ type TeamMember struct {
 	IsActive bool   `json:"is_active"`
 }
 
+// TeamSettings настройки назначения ревьюверов команды
+type TeamSettings struct {
+	TeamName       string   `json:"team_name"`
+	ReviewersCount int      `json:"reviewers_count"`
+	Strategy       string   `json:"strategy"`         // пусто — стратегия по умолчанию из конфигурации
+	MaxOpenReviews int      `json:"max_open_reviews"` // 0 — без ограничения
+	FallbackTeams  []string `json:"fallback_teams"`   // резервные команды в порядке приоритета
+}
+
 // User представляет пользователя
 type User struct {
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
 	TeamName string `json:"team_name"`
 	IsActive bool   `json:"is_active"`
+	Role     string `json:"role"`
 }
 
 // PullRequest представляет pull request с полной информацией
@@ -28,16 +45,19 @@ type PullRequest struct {
 	AuthorID          string   `json:"author_id"`
 	Status            string   `json:"status"` // OPEN, MERGED
 	AssignedReviewers []string `json:"assigned_reviewers"`
+	FallbackReviewers []string `json:"fallback_reviewers,omitempty"` // ревьюверы из резервных команд
+	NeedMoreReviewers bool     `json:"needMoreReviewers"`            // назначено меньше ревьюверов, чем требуется
 	CreatedAt         *string  `json:"createdAt,omitempty"`
 	MergedAt          *string  `json:"mergedAt,omitempty"`
 }
 
 // PullRequestShort представляет краткую информацию о PR
 type PullRequestShort struct {
-	PullRequestID   string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
-	AuthorID        string `json:"author_id"`
-	Status          string `json:"status"`
+	PullRequestID     string `json:"pull_request_id"`
+	PullRequestName   string `json:"pull_request_name"`
+	AuthorID          string `json:"author_id"`
+	Status            string `json:"status"`
+	NeedMoreReviewers bool   `json:"needMoreReviewers"`
 }
 
 // CreateTeamInput входные данные для создания команды
@@ -46,12 +66,58 @@ type CreateTeamInput struct {
 	Members  []TeamMember `json:"members" binding:"required,dive"`
 }
 
+// UpdateTeamSettingsInput входные данные для изменения настроек команды
+// Не переданные поля сохраняют текущее значение
+type UpdateTeamSettingsInput struct {
+	TeamName       string  `json:"team_name" binding:"required"`
+	ReviewersCount *int    `json:"reviewers_count" binding:"omitempty,min=0,max=10"`
+	Strategy       *string `json:"strategy"`
+	MaxOpenReviews *int    `json:"max_open_reviews" binding:"omitempty,min=0"`
+	// FallbackTeams nil — не менять, пустой список — убрать резервные команды
+	FallbackTeams []string `json:"fallback_teams"`
+}
+
+// DeactivateTeamMembersInput входные данные для массовой деактивации участников команды
+type DeactivateTeamMembersInput struct {
+	TeamName  string   `json:"team_name" binding:"required"`
+	UserIDs   []string `json:"user_ids"`
+	AllExcept bool     `json:"all_except"`                                   // деактивировать всех, кроме user_ids
+	Reason    *string  `json:"reason,omitempty" binding:"omitempty,max=500"` // сохраняется в истории назначений
+}
+
+// ReviewReassignment переназначенное ревью
+type ReviewReassignment struct {
+	PullRequestID string `json:"pull_request_id"`
+	OldUserID     string `json:"old_user_id"`
+	NewUserID     string `json:"new_user_id"`
+}
+
+// UnreassignedReview ревью, для которого не нашлось замены
+type UnreassignedReview struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+}
+
+// DeactivateTeamMembersOutput результат массовой деактивации участников команды
+type DeactivateTeamMembersOutput struct {
+	TeamName    string               `json:"team_name"`
+	Deactivated []string             `json:"deactivated"`
+	Reassigned  []ReviewReassignment `json:"reassigned"`
+	NoCandidate []UnreassignedReview `json:"no_candidate"` // ревьювер остался назначенным
+}
+
 // SetIsActiveInput входные данные для изменения статуса активности
 type SetIsActiveInput struct {
 	UserID   string `json:"user_id" binding:"required"`
 	IsActive bool   `json:"is_active"`
 }
 
+// SetRoleInput входные данные для изменения роли пользователя
+type SetRoleInput struct {
+	UserID string `json:"user_id" binding:"required"`
+	Role   string `json:"role" binding:"required,oneof=admin team_lead member"`
+}
+
 // CreatePullRequestInput входные данные для создания PR
 type CreatePullRequestInput struct {
 	PullRequestID   string `json:"pull_request_id" binding:"required"`
@@ -66,8 +132,9 @@ type MergePullRequestInput struct {
 
 // ReassignReviewerInput входные данные для переназначения ревьювера
 type ReassignReviewerInput struct {
-	PullRequestID string `json:"pull_request_id" binding:"required"`
-	OldReviewerID string `json:"old_user_id" binding:"required"`
+	PullRequestID string  `json:"pull_request_id" binding:"required"`
+	OldReviewerID string  `json:"old_user_id" binding:"required"`
+	Reason        *string `json:"reason,omitempty" binding:"omitempty,max=500"` // сохраняется в истории назначений
 }
 
 // ReassignReviewerOutput результат переназначения ревьювера
@@ -76,6 +143,20 @@ type ReassignReviewerOutput struct {
 	ReplacedBy string       `json:"replaced_by"`
 }
 
+// ReviewerAssignmentEvent запись истории назначений ревьюверов PR
+type ReviewerAssignmentEvent struct {
+	OldUserID  *string `json:"old_user_id"` // null — первичное назначение
+	NewUserID  string  `json:"new_user_id"`
+	Reason     *string `json:"reason,omitempty"`
+	AssignedAt string  `json:"assigned_at"`
+}
+
+// PullRequestHistory история назначений ревьюверов PR
+type PullRequestHistory struct {
+	PullRequestID string                    `json:"pull_request_id"`
+	History       []ReviewerAssignmentEvent `json:"history"`
+}
+
 // UserReviewsOutput список PR для пользователя
 type UserReviewsOutput struct {
 	UserID       string             `json:"user_id"`
@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"avito-test-quest/internal/logger"
+	"avito-test-quest/migrations"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"go.uber.org/zap"
+)
+
+// connectAttempts сколько раз пытаться подключиться к Postgres, пока он поднимается вместе с сервисом
+const connectAttempts = 5
+
+// Migrator применяет встроенные миграции к Postgres
+type Migrator struct {
+	m *migrate.Migrate
+}
+
+// NewMigrator подключается к Postgres с повторными попытками и открывает встроенные миграции.
+// Повторяется только подключение: ошибка самой миграции оставляет схему dirty, и повтор её не исправит
+func NewMigrator(ctx context.Context, cfg Config) (*Migrator, error) {
+	log := logger.GetOrCreateLoggerFromCtx(ctx)
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		var m *migrate.Migrate
+		m, err = newMigrate(cfg)
+		if err == nil {
+			return &Migrator{m: m}, nil
+		}
+		if attempt == connectAttempts {
+			break
+		}
+		log.Info(ctx, "postgres is not reachable for migrations, retrying...", zap.Int("attempt", attempt), zap.Error(err))
+		select {
+		case <-time.After(time.Duration(attempt) * time.Second):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return nil, fmt.Errorf("failed to create migration instance: %w", err)
+}
+
+// newMigrate создает экземпляр golang-migrate поверх встроенных миграций
+func newMigrate(cfg Config) (*migrate.Migrate, error) {
+	src, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open embedded migrations: %w", err)
+	}
+	m, err := migrate.NewWithSourceInstance("iofs", src, cfg.GetConnString())
+	if err != nil {
+		_ = src.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+// Up применяет все непримененные миграции
+func (mg *Migrator) Up() error {
+	if err := mg.m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+// Down откатывает steps последних миграций
+func (mg *Migrator) Down(steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("steps must be positive, got %d", steps)
+	}
+	if err := mg.m.Steps(-steps); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+// Version возвращает примененную версию схемы; 0 — миграции не применялись
+func (mg *Migrator) Version() (version uint, dirty bool, err error) {
+	version, dirty, err = mg.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
+
+// Force записывает версию схемы без выполнения миграций и снимает флаг dirty.
+// Используется после ручного исправления схемы, упавшей посреди миграции
+func (mg *Migrator) Force(version int) error {
+	return mg.m.Force(version)
+}
+
+// Close закрывает соединение с БД
+func (mg *Migrator) Close() error {
+	srcErr, dbErr := mg.m.Close()
+	return errors.Join(srcErr, dbErr)
+}
+
+// Migrate применяет встроенные миграции при запуске сервиса
+func Migrate(ctx context.Context, cfg Config) error {
+	mg, err := NewMigrator(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = mg.Close() }()
+
+	if err := mg.Up(); err != nil {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+	version, _, err := mg.Version()
+	if err != nil {
+		return fmt.Errorf("failed to read migration version: %w", err)
+	}
+
+	logger.GetOrCreateLoggerFromCtx(ctx).Info(ctx, "migrated successfully", zap.Uint("version", version))
+	return nil
+}
@@ -1,34 +1,29 @@
 package postgres
 
 import (
-	"avito-test-quest/internal/logger"
 	"context"
-	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
-	"time"
 
-	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"go.uber.org/zap"
 )
 
 // Config содержит настройки для подключения к Postgres
 type Config struct {
-	Host     string `yaml:"host"`
-	Port     string `yaml:"port"`
-	Username string `yaml:"username"`
-	Password string `yaml:"password"`
-	Database string `yaml:"database"`
+	Host     string `yaml:"host" env:"POSTGRES_HOST"`
+	Port     string `yaml:"port" env:"POSTGRES_PORT" env-default:"5432"`
+	Username string `yaml:"username" env:"POSTGRES_USER"`
+	Password string `yaml:"password" env:"POSTGRES_PASSWORD"`
+	Database string `yaml:"database" env:"POSTGRES_DB"`
 	MaxConns int32  `yaml:"max_conns" env:"MAX_CONNS" env-default:"10"`
 	MinConns int32  `yaml:"min_conns" env:"MIN_CONNS" env-default:"5"`
+	// SkipMigrations не применять миграции при запуске serve: схему обновляет отдельный "migrate up"
+	SkipMigrations bool `yaml:"skip_migrations" env:"SKIP_MIGRATIONS"`
 }
 
 // New создает новое подключение к Postgres
-func New(ctx context.Context, cfg Config) (*pgxpool.Pool, error) {
+// tracer получает события каждого запроса (метрики, спаны), nil — без трассировки
+func New(ctx context.Context, cfg Config, tracer pgx.QueryTracer) (*pgxpool.Pool, error) {
 	// создаем строку подключения с параметрами пула
 	connString := cfg.GetConnString()
 	connString += fmt.Sprintf("&pool_max_conns=%d&pool_min_conns=%d",
@@ -36,64 +31,21 @@ func New(ctx context.Context, cfg Config) (*pgxpool.Pool, error) {
 		cfg.MinConns,
 	)
 
-	// создаем пул подключений
-	conn, err := pgxpool.New(ctx, connString)
+	poolCfg, err := pgxpool.ParseConfig(connString)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to postgres: %w", err)
-	}
-
-	return conn, nil
-}
-
-// Migrate выполняет миграции базы данных Postgres
-func Migrate(ctx context.Context, cfg Config) error {
-	// создаем строку подключения
-	connString := cfg.GetConnString()
-	log := logger.GetOrCreateLoggerFromCtx(ctx)
-
-	// Ищем директорию с миграциями в нескольких местах
-	migrationPaths := []string{
-		"migrations",
-		"./migrations",
-		"../migrations",
-		"../../migrations",
+		return nil, fmt.Errorf("failed to parse postgres config: %w", err)
 	}
-
-	var migrationPath string
-	for _, path := range migrationPaths {
-		if info, err := os.Stat(path); err == nil && info.IsDir() {
-			migrationPath, _ = filepath.Abs(path)
-			break
-		}
+	if tracer != nil {
+		poolCfg.ConnConfig.Tracer = tracer
 	}
 
-	if migrationPath == "" {
-		return fmt.Errorf("migrations directory not found")
-	}
-
-	// создаем миграции с найденным путем
-	m, err := migrate.New("file://"+migrationPath, connString)
-
+	// создаем пул подключений
+	conn, err := pgxpool.NewWithConfig(ctx, poolCfg)
 	if err != nil {
-		return fmt.Errorf("failed to create migration instance: %w", err)
-	}
-
-	// пытаемся выполнить миграции с ретраями
-	retries := 5
-	for i := 0; i < retries; i++ {
-		err = m.Up()
-		if err == nil {
-			break
-		}
-		log.Info(ctx, "migration failed, retrying...", zap.Error(err))
-		time.Sleep(time.Duration(i+1) * time.Second)
-	}
-	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
-		return fmt.Errorf("failed to run migrations: %w", err)
+		return nil, fmt.Errorf("failed to connect to postgres: %w", err)
 	}
 
-	logger.GetOrCreateLoggerFromCtx(ctx).Info(ctx, "migrated successfully")
-	return nil
+	return conn, nil
 }
 
 // GetConnString формирует строку подключения к Postgres
@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"avito-test-quest/migrations"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// LatestMigrationVersion возвращает версию последней встроенной миграции
+func LatestMigrationVersion() (uint, error) {
+	return migrations.Latest(migrations.FS, ".")
+}
+
+// MigrationVersion возвращает примененную версию схемы из таблицы golang-migrate.
+// Версия 0 без ошибки — миграции не запускались
+func MigrationVersion(ctx context.Context, pool *pgxpool.Pool) (version uint, dirty bool, err error) {
+	var v int64
+	err = pool.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&v, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return uint(v), dirty, nil
+}
+
+// DB пул Postgres для проверки готовности: ping, версия схемы и статистика пула
+type DB struct {
+	*pgxpool.Pool
+}
+
+// MigrationVersion возвращает примененную версию схемы
+func (db DB) MigrationVersion(ctx context.Context) (version uint, dirty bool, err error) {
+	return MigrationVersion(ctx, db.Pool)
+}
@@ -2,12 +2,20 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
+// ErrAlreadyExists возвращается при вставке записи с уже занятым уникальным ключом
+var ErrAlreadyExists = errors.New("already exists")
+
+// ErrNotFound возвращается, если запрошенной записи нет.
+// Совпадает с pgx.ErrNoRows, который PrRepository возвращает без преобразования
+var ErrNotFound = pgx.ErrNoRows
+
 // TeamModel представляет команду в БД
 type TeamModel struct {
 	ID        int64     `db:"id"`
@@ -23,20 +31,22 @@ type UserModel struct {
 	Username  string    `db:"username"`
 	TeamID    int64     `db:"team_id"`
 	IsActive  bool      `db:"is_active"`
+	Role      string    `db:"role"` // admin, team_lead или member
 	CreatedAt time.Time `db:"created_at"`
 	UpdatedAt time.Time `db:"updated_at"`
 }
 
 // PullRequestModel представляет PR в БД
 type PullRequestModel struct {
-	ID              int64      `db:"id"`
-	PullRequestID   string     `db:"pull_request_id"`
-	PullRequestName string     `db:"pull_request_name"`
-	AuthorID        string     `db:"author_id"`
-	Status          string     `db:"status"` // OPEN, MERGED
-	CreatedAt       time.Time  `db:"created_at"`
-	MergedAt        *time.Time `db:"merged_at"`
-	UpdatedAt       time.Time  `db:"updated_at"`
+	ID                int64      `db:"id"`
+	PullRequestID     string     `db:"pull_request_id"`
+	PullRequestName   string     `db:"pull_request_name"`
+	AuthorID          string     `db:"author_id"`
+	Status            string     `db:"status"`              // OPEN, MERGED
+	NeedMoreReviewers bool       `db:"need_more_reviewers"` // назначено меньше ревьюверов, чем требуется
+	CreatedAt         time.Time  `db:"created_at"`
+	MergedAt          *time.Time `db:"merged_at"`
+	UpdatedAt         time.Time  `db:"updated_at"`
 }
 
 // PRReviewerModel представляет связь PR и ревьювера в БД
@@ -44,19 +54,52 @@ type PRReviewerModel struct {
 	ID             int64     `db:"id"`
 	PullRequestID  string    `db:"pull_request_id"`
 	ReviewerUserID string    `db:"reviewer_user_id"`
+	IsFallback     bool      `db:"is_fallback"` // назначен из резервной команды
 	AssignedAt     time.Time `db:"assigned_at"`
 }
 
 // ReviewerAssignmentHistoryModel представляет историю переназначений
+// Первичное назначение записывается с пустым OldReviewerUserID
 type ReviewerAssignmentHistoryModel struct {
 	ID                int64     `db:"id"`
 	PullRequestID     string    `db:"pull_request_id"`
-	OldReviewerUserID string    `db:"old_reviewer_user_id"`
+	OldReviewerUserID *string   `db:"old_reviewer_user_id"`
 	NewReviewerUserID string    `db:"new_reviewer_user_id"`
 	ReassignedAt      time.Time `db:"reassigned_at"`
 	Reason            *string   `db:"reason"`
 }
 
+// TeamSettingsModel представляет настройки назначения ревьюверов команды в БД
+type TeamSettingsModel struct {
+	TeamID         int64     `db:"team_id"`
+	ReviewersCount int       `db:"reviewers_count"`
+	Strategy       *string   `db:"strategy"`         // nil — стратегия по умолчанию
+	MaxOpenReviews int       `db:"max_open_reviews"` // 0 — без ограничения
+	CreatedAt      time.Time `db:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at"`
+}
+
+// ReviewerCandidate активный пользователь с количеством открытых ревью
+type ReviewerCandidate struct {
+	UserModel
+	OpenReviews int `db:"open_reviews"`
+}
+
+// OpenReview OPEN PR со всеми назначенными ревьюверами
+type OpenReview struct {
+	PullRequestID string
+	AuthorID      string
+	Reviewers     []string // в порядке user_id
+}
+
+// ReviewerReplacement замена ревьювера при массовой деактивации участников
+type ReviewerReplacement struct {
+	PullRequestID     string
+	OldReviewerUserID string
+	NewReviewerUserID *string // nil — подходящего кандидата нет
+	IsFallback        bool    // кандидат из резервной команды
+}
+
 // UserWithTeam расширенная модель пользователя с названием команды
 type UserWithTeam struct {
 	UserID   string `db:"user_id"`
@@ -67,8 +110,9 @@ type UserWithTeam struct {
 
 // PRWithReviewers PR с назначенными ревьюверами
 type PRWithReviewers struct {
-	PullRequest *PullRequestModel
-	Reviewers   []string // список user_id ревьюверов
+	PullRequest       *PullRequestModel
+	Reviewers         []string // список user_id ревьюверов
+	FallbackReviewers []string // user_id ревьюверов, назначенных из резервных команд
 }
 
 // TeamRepository интерфейс для работы с командами
@@ -86,6 +130,22 @@ type TeamRepository interface {
 	TeamExists(ctx context.Context, teamName string) (bool, error)
 }
 
+// TeamSettingsRepository интерфейс для работы с настройками команд
+type TeamSettingsRepository interface {
+	// GetTeamSettings получает настройки команды
+	// Возвращает nil без ошибки, если настройки не задавались
+	GetTeamSettings(ctx context.Context, teamID int64) (*TeamSettingsModel, error)
+
+	// UpsertTeamSettings создает или обновляет настройки команды
+	UpsertTeamSettings(ctx context.Context, settings TeamSettingsModel) (*TeamSettingsModel, error)
+
+	// GetFallbackTeams получает резервные команды в порядке приоритета
+	GetFallbackTeams(ctx context.Context, teamID int64) ([]TeamModel, error)
+
+	// SetFallbackTeams заменяет список резервных команд, порядок задает приоритет
+	SetFallbackTeams(ctx context.Context, teamID int64, fallbackTeamIDs []int64) error
+}
+
 // UserRepository интерфейс для работы с пользователями
 type UserRepository interface {
 	// CreateUser создает нового пользователя
@@ -106,21 +166,37 @@ type UserRepository interface {
 	// SetIsActive обновляет флаг активности пользователя
 	SetIsActive(ctx context.Context, userID string, isActive bool) (*UserModel, error)
 
+	// SetUserRole обновляет роль пользователя
+	SetUserRole(ctx context.Context, userID, role string) (*UserModel, error)
+
 	// UserExists проверяет существование пользователя
 	UserExists(ctx context.Context, userID string) (bool, error)
 
 	// GetActiveUsersInTeam получает активных пользователей команды
 	GetActiveUsersInTeam(ctx context.Context, teamID int64) ([]UserModel, error)
+
+	// DeactivateTeamMembers деактивирует активных участников команды из userIDs
+	// (или всех, кроме userIDs, при allExcept) одним запросом и возвращает их user_id
+	DeactivateTeamMembers(ctx context.Context, teamID int64, userIDs []string, allExcept bool) ([]string, error)
+
+	// GetActiveUsersWithLoad получает активных пользователей команды
+	// вместе с количеством OPEN PR, на которые они назначены, одним запросом
+	GetActiveUsersWithLoad(ctx context.Context, teamID int64) ([]ReviewerCandidate, error)
 }
 
 // PullRequestRepository интерфейс для работы с Pull Request
 type PullRequestRepository interface {
 	// CreatePullRequest создает новый PR
+	// Возвращает ErrAlreadyExists, если PR с таким pull_request_id уже есть
 	CreatePullRequest(ctx context.Context, prID, prName, authorID string) (*PullRequestModel, error)
 
 	// GetPullRequestByID получает PR по pull_request_id
 	GetPullRequestByID(ctx context.Context, prID string) (*PullRequestModel, error)
 
+	// LockPullRequest получает PR и блокирует его строку до конца транзакции (SELECT ... FOR UPDATE)
+	// Имеет смысл только внутри WithTx
+	LockPullRequest(ctx context.Context, prID string) (*PullRequestModel, error)
+
 	// GetPullRequestWithReviewers получает PR со списком ревьюверов
 	GetPullRequestWithReviewers(ctx context.Context, prID string) (*PRWithReviewers, error)
 
@@ -132,12 +208,20 @@ type PullRequestRepository interface {
 
 	// GetPullRequestsByAuthor получает все PR автора
 	GetPullRequestsByAuthor(ctx context.Context, authorID string) ([]PullRequestModel, error)
+
+	// SetNeedMoreReviewers обновляет флаг нехватки ревьюверов на PR
+	SetNeedMoreReviewers(ctx context.Context, prID string, needMore bool) error
+
+	// GetOpenPRsNeedingReviewers получает OPEN PR авторов команды, которым не хватает ревьюверов,
+	// и блокирует их строки до конца транзакции
+	GetOpenPRsNeedingReviewers(ctx context.Context, teamID int64) ([]PullRequestModel, error)
 }
 
 // PRReviewerRepository интерфейс для работы с ревьюверами PR
 type PRReviewerRepository interface {
-	// AssignReviewer назначает ревьювера на PR
-	AssignReviewer(ctx context.Context, prID, reviewerUserID string) error
+	// AssignReviewer назначает ревьювера на PR и записывает назначение в историю
+	// isFallback отмечает ревьювера, выбранного из резервной команды
+	AssignReviewer(ctx context.Context, prID, reviewerUserID string, isFallback bool) error
 
 	// RemoveReviewer удаляет ревьювера с PR
 	RemoveReviewer(ctx context.Context, prID, reviewerUserID string) error
@@ -145,14 +229,29 @@ type PRReviewerRepository interface {
 	// GetReviewersByPRID получает всех ревьюверов PR
 	GetReviewersByPRID(ctx context.Context, prID string) ([]string, error)
 
+	// GetReviewerAssignmentsByPRID получает назначения ревьюверов PR
+	GetReviewerAssignmentsByPRID(ctx context.Context, prID string) ([]PRReviewerModel, error)
+
 	// GetPRsByReviewerID получает все PR, где пользователь назначен ревьювером
 	GetPRsByReviewerID(ctx context.Context, reviewerUserID string) ([]PullRequestModel, error)
 
 	// IsReviewerAssigned проверяет, назначен ли пользователь ревьювером на PR
 	IsReviewerAssigned(ctx context.Context, prID, reviewerUserID string) (bool, error)
 
-	// ReplaceReviewer заменяет одного ревьювера на другого
-	ReplaceReviewer(ctx context.Context, prID, oldReviewerID, newReviewerID string) error
+	// ReplaceReviewer заменяет одного ревьювера на другого и записывает замену в историю
+	// reason — необязательная причина переназначения.
+	// Возвращает ErrAlreadyExists и ничего не меняет, если новый ревьювер уже назначен на PR
+	ReplaceReviewer(ctx context.Context, prID, oldReviewerID, newReviewerID string, isFallback bool, reason *string) error
+
+	// GetAssignmentHistory получает историю назначений ревьюверов PR в хронологическом порядке
+	GetAssignmentHistory(ctx context.Context, prID string) ([]ReviewerAssignmentHistoryModel, error)
+
+	// LockOpenReviews блокирует OPEN PR, где назначен кто-то из reviewerIDs, и возвращает их
+	// со всеми ревьюверами в порядке pull_request_id. Вызывается внутри WithTx
+	LockOpenReviews(ctx context.Context, reviewerIDs []string) ([]OpenReview, error)
+
+	// ApplyReviewerReplacements применяет замены с найденным кандидатом пакетно и записывает их в историю
+	ApplyReviewerReplacements(ctx context.Context, replacements []ReviewerReplacement, reason *string) error
 
 	// CountReviewersByPRID подсчитывает количество ревьюверов на PR
 	CountReviewersByPRID(ctx context.Context, prID string) (int, error)
@@ -168,6 +267,9 @@ type DB interface {
 
 	// QueryRow выполняет SQL запрос и возвращает одну строку
 	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+
+	// Begin начинает транзакцию (внутри транзакции — savepoint)
+	Begin(ctx context.Context) (pgx.Tx, error)
 }
 
 type ReviewerStatRow struct {
@@ -194,10 +296,17 @@ type StatsRepository interface {
 }
 
 // Repository объединяет все репозиторные интерфейсы
+//
+//go:generate mockgen -destination=mocks/repository_mock.go -package=mocks avito-test-quest/internal/repository Repository
 type Repository interface {
 	TeamRepository
+	TeamSettingsRepository
 	UserRepository
 	PullRequestRepository
 	PRReviewerRepository
 	StatsRepository
+
+	// WithTx выполняет fn в транзакции: коммит, если fn вернула nil, иначе откат
+	// Все вызовы репозитория внутри fn должны идти через переданный repo
+	WithTx(ctx context.Context, fn func(repo Repository) error) error
 }
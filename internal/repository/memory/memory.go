@@ -0,0 +1,151 @@
+// Package memory реализует repository.Repository в памяти процесса:
+// для локального запуска без Postgres и быстрых тестов.
+// Поведение совпадает с PrRepository, это проверяет общий набор тестов repotest
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"avito-test-quest/internal/repository"
+)
+
+// errTxClosed обращение к репозиторию транзакции после её завершения
+var errTxClosed = errors.New("memory: transaction is closed")
+
+// Repository хранилище в памяти. Чтения выполняются под разделяемой блокировкой,
+// изменения и WithTx — под эксклюзивной, поэтому транзакции сериализуемы
+type Repository struct {
+	st  *store
+	log *txLog // журнал текущей транзакции, nil вне транзакции
+}
+
+// store данные всех таблиц; ключи совпадают с уникальными ключами схемы Postgres
+type store struct {
+	mu sync.RWMutex
+
+	teams     map[int64]repository.TeamModel
+	teamNames map[string]int64
+	users     map[string]repository.UserModel
+	settings  map[int64]repository.TeamSettingsModel
+	fallbacks map[int64][]int64 // team_id -> резервные команды в порядке position
+	prs       map[string]repository.PullRequestModel
+	reviewers map[string][]repository.PRReviewerModel // pull_request_id -> назначения в порядке id
+	history   map[string][]repository.ReviewerAssignmentHistoryModel
+
+	// последовательности id, как SERIAL, не откатываются вместе с транзакцией
+	teamSeq, userSeq, prSeq, reviewerSeq, historySeq int64
+}
+
+// txLog журнал отмены транзакции. Изменения применяются к store сразу,
+// при откате журнал проигрывается в обратном порядке
+type txLog struct {
+	now    time.Time // время начала транзакции, как CURRENT_TIMESTAMP
+	undo   []func()
+	closed bool
+}
+
+// New создает пустое хранилище
+func New() repository.Repository {
+	return &Repository{st: &store{
+		teams:     map[int64]repository.TeamModel{},
+		teamNames: map[string]int64{},
+		users:     map[string]repository.UserModel{},
+		settings:  map[int64]repository.TeamSettingsModel{},
+		fallbacks: map[int64][]int64{},
+		prs:       map[string]repository.PullRequestModel{},
+		reviewers: map[string][]repository.PRReviewerModel{},
+		history:   map[string][]repository.ReviewerAssignmentHistoryModel{},
+	}}
+}
+
+// WithTx выполняет fn в транзакции
+// fn получает репозиторий транзакции; при ошибке или панике её изменения откатываются.
+// Вызов внутри другой транзакции откатывает только свои изменения, как savepoint
+func (r *Repository) WithTx(ctx context.Context, fn func(repo repository.Repository) error) error {
+	return r.inTx(ctx, func(tx *Repository) error {
+		return fn(tx)
+	})
+}
+
+// inTx выполняет fn в транзакции, каждое изменение данных идет через неё
+func (r *Repository) inTx(ctx context.Context, fn func(tx *Repository) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	log := r.log
+	if log == nil {
+		r.st.mu.Lock()
+		defer r.st.mu.Unlock()
+		log = &txLog{now: now()}
+		defer func() { log.closed = true }()
+	} else if log.closed {
+		return errTxClosed
+	}
+
+	savepoint := len(log.undo)
+	committed := false
+	defer func() {
+		if !committed {
+			log.rollbackTo(savepoint)
+		}
+	}()
+	if err := fn(&Repository{st: r.st, log: log}); err != nil {
+		return err
+	}
+	committed = true
+
+	return nil
+}
+
+// read выполняет fn под разделяемой блокировкой; внутри транзакции блокировка уже захвачена
+func (r *Repository) read(ctx context.Context, fn func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if r.log == nil {
+		r.st.mu.RLock()
+		defer r.st.mu.RUnlock()
+	} else if r.log.closed {
+		return errTxClosed
+	}
+
+	return fn()
+}
+
+// rollbackTo отменяет изменения, сделанные после savepoint
+func (t *txLog) rollbackTo(savepoint int) {
+	for i := len(t.undo) - 1; i >= savepoint; i-- {
+		t.undo[i]()
+	}
+	t.undo = t.undo[:savepoint]
+}
+
+// put записывает значение в m и запоминает, как вернуть прежнее
+func put[K comparable, V any](log *txLog, m map[K]V, key K, value V) {
+	prev, existed := m[key]
+	m[key] = value
+	log.undo = append(log.undo, func() {
+		if existed {
+			m[key] = prev
+		} else {
+			delete(m, key)
+		}
+	})
+}
+
+// nextID выдает следующий id последовательности
+func nextID(seq *int64) int64 {
+	*seq++
+	return *seq
+}
+
+// now текущее время с точностью TIMESTAMP Postgres
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+// проверка реализации интерфейса Repository
+var _ repository.Repository = (*Repository)(nil)
@@ -0,0 +1,15 @@
+package memory_test
+
+import (
+	"testing"
+
+	"avito-test-quest/internal/repository"
+	"avito-test-quest/internal/repository/memory"
+	"avito-test-quest/internal/repository/repotest"
+)
+
+func TestRepositoryConformance(t *testing.T) {
+	repotest.Run(t, func(*testing.T) repository.Repository {
+		return memory.New()
+	})
+}
@@ -0,0 +1,790 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"avito-test-quest/internal/repository"
+)
+
+// статусы PR, как в типе pr_status
+const (
+	statusOpen   = "OPEN"
+	statusMerged = "MERGED"
+)
+
+// roles допустимые роли пользователя, как в ограничении CHECK колонки users.role
+var roles = map[string]struct{}{"admin": {}, "team_lead": {}, "member": {}}
+
+// ==================== Team Repository Methods ====================
+
+// CreateTeam создает новую команду
+func (r *Repository) CreateTeam(ctx context.Context, teamName string) (*repository.TeamModel, error) {
+	var tm repository.TeamModel
+	err := r.inTx(ctx, func(tx *Repository) error {
+		if _, ok := tx.st.teamNames[teamName]; ok {
+			return fmt.Errorf("team %q: %w", teamName, repository.ErrAlreadyExists)
+		}
+		tm = repository.TeamModel{ID: nextID(&tx.st.teamSeq), TeamName: teamName, CreatedAt: tx.log.now, UpdatedAt: tx.log.now}
+		put(tx.log, tx.st.teams, tm.ID, tm)
+		put(tx.log, tx.st.teamNames, teamName, tm.ID)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tm, nil
+}
+
+// GetTeamByName получает команду по имени
+func (r *Repository) GetTeamByName(ctx context.Context, teamName string) (*repository.TeamModel, error) {
+	var tm repository.TeamModel
+	err := r.read(ctx, func() error {
+		id, ok := r.st.teamNames[teamName]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		tm = r.st.teams[id]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tm, nil
+}
+
+// GetTeamByID получает команду по ID
+func (r *Repository) GetTeamByID(ctx context.Context, teamID int64) (*repository.TeamModel, error) {
+	var tm repository.TeamModel
+	err := r.read(ctx, func() error {
+		var err error
+		tm, err = r.st.team(teamID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tm, nil
+}
+
+// TeamExists проверяет существование команды по имени
+func (r *Repository) TeamExists(ctx context.Context, teamName string) (bool, error) {
+	var exists bool
+	err := r.read(ctx, func() error {
+		_, exists = r.st.teamNames[teamName]
+		return nil
+	})
+	return exists, err
+}
+
+// ==================== Team Settings Repository Methods ====================
+
+// GetTeamSettings получает настройки команды, nil если они не задавались
+func (r *Repository) GetTeamSettings(ctx context.Context, teamID int64) (*repository.TeamSettingsModel, error) {
+	var ts repository.TeamSettingsModel
+	var ok bool
+	err := r.read(ctx, func() error {
+		ts, ok = r.st.settings[teamID]
+		return nil
+	})
+	if err != nil || !ok {
+		return nil, err
+	}
+	return &ts, nil
+}
+
+// UpsertTeamSettings создает или обновляет настройки команды
+func (r *Repository) UpsertTeamSettings(ctx context.Context, settings repository.TeamSettingsModel) (*repository.TeamSettingsModel, error) {
+	var ts repository.TeamSettingsModel
+	err := r.inTx(ctx, func(tx *Repository) error {
+		if _, err := tx.st.team(settings.TeamID); err != nil {
+			return fmt.Errorf("team settings reference team %d: %w", settings.TeamID, err)
+		}
+		if settings.ReviewersCount < 0 || settings.MaxOpenReviews < 0 {
+			return fmt.Errorf("team settings: reviewers_count and max_open_reviews must not be negative")
+		}
+		ts = settings
+		ts.CreatedAt, ts.UpdatedAt = tx.log.now, tx.log.now
+		if prev, ok := tx.st.settings[settings.TeamID]; ok {
+			ts.CreatedAt = prev.CreatedAt
+		}
+		put(tx.log, tx.st.settings, ts.TeamID, ts)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ts, nil
+}
+
+// GetFallbackTeams получает резервные команды в порядке приоритета
+func (r *Repository) GetFallbackTeams(ctx context.Context, teamID int64) ([]repository.TeamModel, error) {
+	var res []repository.TeamModel
+	err := r.read(ctx, func() error {
+		for _, id := range r.st.fallbacks[teamID] {
+			res = append(res, r.st.teams[id])
+		}
+		return nil
+	})
+	return res, err
+}
+
+// SetFallbackTeams заменяет список резервных команд
+func (r *Repository) SetFallbackTeams(ctx context.Context, teamID int64, fallbackTeamIDs []int64) error {
+	return r.inTx(ctx, func(tx *Repository) error {
+		seen := make(map[int64]struct{}, len(fallbackTeamIDs))
+		for _, id := range fallbackTeamIDs {
+			if _, err := tx.st.team(id); err != nil {
+				return fmt.Errorf("fallback team %d: %w", id, err)
+			}
+			if _, dup := seen[id]; dup || id == teamID {
+				return fmt.Errorf("fallback team %d is duplicated or equal to team %d", id, teamID)
+			}
+			seen[id] = struct{}{}
+		}
+		if len(fallbackTeamIDs) == 0 {
+			put(tx.log, tx.st.fallbacks, teamID, nil)
+			return nil
+		}
+		put(tx.log, tx.st.fallbacks, teamID, slices.Clone(fallbackTeamIDs))
+		return nil
+	})
+}
+
+// ==================== User Repository Methods ====================
+
+// CreateUser создает нового пользователя
+func (r *Repository) CreateUser(ctx context.Context, userID, username string, teamID int64, isActive bool) (*repository.UserModel, error) {
+	var u repository.UserModel
+	err := r.inTx(ctx, func(tx *Repository) error {
+		if _, ok := tx.st.users[userID]; ok {
+			return fmt.Errorf("user %q: %w", userID, repository.ErrAlreadyExists)
+		}
+		if _, err := tx.st.team(teamID); err != nil {
+			return fmt.Errorf("user %q references team %d: %w", userID, teamID, err)
+		}
+		u = repository.UserModel{
+			ID: nextID(&tx.st.userSeq), UserID: userID, Username: username, TeamID: teamID, IsActive: isActive,
+			Role: "member", CreatedAt: tx.log.now, UpdatedAt: tx.log.now,
+		}
+		put(tx.log, tx.st.users, userID, u)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// UpdateUser обновляет данные пользователя
+func (r *Repository) UpdateUser(ctx context.Context, userID, username string, teamID int64, isActive bool) (*repository.UserModel, error) {
+	return r.updateUser(ctx, userID, func(tx *Repository, u *repository.UserModel) error {
+		if _, err := tx.st.team(teamID); err != nil {
+			return fmt.Errorf("user %q references team %d: %w", userID, teamID, err)
+		}
+		u.Username, u.TeamID, u.IsActive = username, teamID, isActive
+		return nil
+	})
+}
+
+// GetUserByID получает пользователя по userID
+func (r *Repository) GetUserByID(ctx context.Context, userID string) (*repository.UserModel, error) {
+	var u repository.UserModel
+	err := r.read(ctx, func() error {
+		var err error
+		u, err = r.st.user(userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// GetUserWithTeam получает пользователя с названием команды
+func (r *Repository) GetUserWithTeam(ctx context.Context, userID string) (*repository.UserWithTeam, error) {
+	var uwt repository.UserWithTeam
+	err := r.read(ctx, func() error {
+		u, err := r.st.user(userID)
+		if err != nil {
+			return err
+		}
+		uwt = repository.UserWithTeam{UserID: u.UserID, Username: u.Username, TeamName: r.st.teams[u.TeamID].TeamName, IsActive: u.IsActive}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &uwt, nil
+}
+
+// GetUsersByTeamID получает всех пользователей команды
+func (r *Repository) GetUsersByTeamID(ctx context.Context, teamID int64) ([]repository.UserModel, error) {
+	var res []repository.UserModel
+	err := r.read(ctx, func() error {
+		res = r.st.teamUsers(teamID, false)
+		return nil
+	})
+	return res, err
+}
+
+// SetIsActive обновляет флаг активности пользователя
+func (r *Repository) SetIsActive(ctx context.Context, userID string, isActive bool) (*repository.UserModel, error) {
+	return r.updateUser(ctx, userID, func(_ *Repository, u *repository.UserModel) error {
+		u.IsActive = isActive
+		return nil
+	})
+}
+
+// SetUserRole обновляет роль пользователя
+func (r *Repository) SetUserRole(ctx context.Context, userID, role string) (*repository.UserModel, error) {
+	return r.updateUser(ctx, userID, func(_ *Repository, u *repository.UserModel) error {
+		if _, ok := roles[role]; !ok {
+			return fmt.Errorf("user %q: invalid role %q", userID, role)
+		}
+		u.Role = role
+		return nil
+	})
+}
+
+// DeactivateTeamMembers деактивирует активных участников команды из userIDs или всех, кроме userIDs
+func (r *Repository) DeactivateTeamMembers(ctx context.Context, teamID int64, userIDs []string, allExcept bool) ([]string, error) {
+	var res []string
+	err := r.inTx(ctx, func(tx *Repository) error {
+		for _, u := range tx.st.teamUsers(teamID, true) {
+			if slices.Contains(userIDs, u.UserID) == allExcept {
+				continue
+			}
+			u.IsActive, u.UpdatedAt = false, tx.log.now
+			put(tx.log, tx.st.users, u.UserID, u)
+			res = append(res, u.UserID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// UserExists проверяет существование пользователя
+func (r *Repository) UserExists(ctx context.Context, userID string) (bool, error) {
+	var exists bool
+	err := r.read(ctx, func() error {
+		_, exists = r.st.users[userID]
+		return nil
+	})
+	return exists, err
+}
+
+// GetActiveUsersInTeam получает активных пользователей команды
+func (r *Repository) GetActiveUsersInTeam(ctx context.Context, teamID int64) ([]repository.UserModel, error) {
+	var res []repository.UserModel
+	err := r.read(ctx, func() error {
+		res = r.st.teamUsers(teamID, true)
+		return nil
+	})
+	return res, err
+}
+
+// GetActiveUsersWithLoad получает активных пользователей команды с количеством открытых ревью
+func (r *Repository) GetActiveUsersWithLoad(ctx context.Context, teamID int64) ([]repository.ReviewerCandidate, error) {
+	var res []repository.ReviewerCandidate
+	err := r.read(ctx, func() error {
+		load := r.st.openReviews()
+		for _, u := range r.st.teamUsers(teamID, true) {
+			res = append(res, repository.ReviewerCandidate{UserModel: u, OpenReviews: load[u.UserID]})
+		}
+		return nil
+	})
+	return res, err
+}
+
+// updateUser изменяет пользователя через fn и обновляет updated_at
+func (r *Repository) updateUser(ctx context.Context, userID string, fn func(tx *Repository, u *repository.UserModel) error) (*repository.UserModel, error) {
+	var u repository.UserModel
+	err := r.inTx(ctx, func(tx *Repository) error {
+		var err error
+		if u, err = tx.st.user(userID); err != nil {
+			return err
+		}
+		if err := fn(tx, &u); err != nil {
+			return err
+		}
+		u.UpdatedAt = tx.log.now
+		put(tx.log, tx.st.users, userID, u)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// ==================== Pull Request Repository Methods ====================
+
+// CreatePullRequest создает новый Pull Request
+// Возвращает ErrAlreadyExists, если PR с таким pull_request_id уже есть
+func (r *Repository) CreatePullRequest(ctx context.Context, prID, prName, authorID string) (*repository.PullRequestModel, error) {
+	var pr repository.PullRequestModel
+	err := r.inTx(ctx, func(tx *Repository) error {
+		if _, ok := tx.st.prs[prID]; ok {
+			return repository.ErrAlreadyExists
+		}
+		if _, err := tx.st.user(authorID); err != nil {
+			return fmt.Errorf("pull request %q references author %q: %w", prID, authorID, err)
+		}
+		pr = repository.PullRequestModel{
+			ID: nextID(&tx.st.prSeq), PullRequestID: prID, PullRequestName: prName, AuthorID: authorID,
+			Status: statusOpen, CreatedAt: tx.log.now, UpdatedAt: tx.log.now,
+		}
+		put(tx.log, tx.st.prs, prID, pr)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// GetPullRequestByID получает Pull Request по его ID
+func (r *Repository) GetPullRequestByID(ctx context.Context, prID string) (*repository.PullRequestModel, error) {
+	var pr repository.PullRequestModel
+	err := r.read(ctx, func() error {
+		var err error
+		pr, err = r.st.pr(prID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// LockPullRequest получает Pull Request по его ID
+// Отдельная блокировка не нужна: транзакция и так владеет хранилищем эксклюзивно
+func (r *Repository) LockPullRequest(ctx context.Context, prID string) (*repository.PullRequestModel, error) {
+	return r.GetPullRequestByID(ctx, prID)
+}
+
+// GetPullRequestWithReviewers получает Pull Request вместе с назначенными ревьюверами
+func (r *Repository) GetPullRequestWithReviewers(ctx context.Context, prID string) (*repository.PRWithReviewers, error) {
+	var res repository.PRWithReviewers
+	err := r.read(ctx, func() error {
+		pr, err := r.st.pr(prID)
+		if err != nil {
+			return err
+		}
+		res.PullRequest = &pr
+		for _, a := range r.st.reviewers[prID] {
+			res.Reviewers = append(res.Reviewers, a.ReviewerUserID)
+			if a.IsFallback {
+				res.FallbackReviewers = append(res.FallbackReviewers, a.ReviewerUserID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// MergePullRequest помечает Pull Request как замерженный
+func (r *Repository) MergePullRequest(ctx context.Context, prID string) (*repository.PullRequestModel, error) {
+	var pr repository.PullRequestModel
+	err := r.inTx(ctx, func(tx *Repository) error {
+		var err error
+		if pr, err = tx.st.pr(prID); err != nil {
+			return err
+		}
+		mergedAt := tx.log.now
+		pr.Status, pr.MergedAt, pr.UpdatedAt = statusMerged, &mergedAt, tx.log.now
+		put(tx.log, tx.st.prs, prID, pr)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pr, nil
+}
+
+// PullRequestExists проверяет существование Pull Request по его ID
+func (r *Repository) PullRequestExists(ctx context.Context, prID string) (bool, error) {
+	var exists bool
+	err := r.read(ctx, func() error {
+		_, exists = r.st.prs[prID]
+		return nil
+	})
+	return exists, err
+}
+
+// GetPullRequestsByAuthor получает все Pull Request, созданные автором
+func (r *Repository) GetPullRequestsByAuthor(ctx context.Context, authorID string) ([]repository.PullRequestModel, error) {
+	var res []repository.PullRequestModel
+	err := r.read(ctx, func() error {
+		res = r.st.filterPRs(func(pr repository.PullRequestModel) bool { return pr.AuthorID == authorID })
+		return nil
+	})
+	return res, err
+}
+
+// SetNeedMoreReviewers обновляет флаг нехватки ревьюверов на Pull Request
+func (r *Repository) SetNeedMoreReviewers(ctx context.Context, prID string, needMore bool) error {
+	return r.inTx(ctx, func(tx *Repository) error {
+		pr, ok := tx.st.prs[prID]
+		if !ok {
+			return nil
+		}
+		pr.NeedMoreReviewers, pr.UpdatedAt = needMore, tx.log.now
+		put(tx.log, tx.st.prs, prID, pr)
+		return nil
+	})
+}
+
+// GetOpenPRsNeedingReviewers получает OPEN Pull Request авторов команды с флагом нехватки ревьюверов
+func (r *Repository) GetOpenPRsNeedingReviewers(ctx context.Context, teamID int64) ([]repository.PullRequestModel, error) {
+	var res []repository.PullRequestModel
+	err := r.read(ctx, func() error {
+		res = r.st.filterPRs(func(pr repository.PullRequestModel) bool {
+			return pr.Status == statusOpen && pr.NeedMoreReviewers && r.st.users[pr.AuthorID].TeamID == teamID
+		})
+		slices.SortStableFunc(res, func(a, b repository.PullRequestModel) int { return a.CreatedAt.Compare(b.CreatedAt) })
+		return nil
+	})
+	return res, err
+}
+
+// ==================== PR Reviewer Repository Methods ====================
+
+// AssignReviewer назначает ревьювера на Pull Request и записывает назначение в историю
+func (r *Repository) AssignReviewer(ctx context.Context, prID, reviewerUserID string, isFallback bool) error {
+	return r.inTx(ctx, func(tx *Repository) error {
+		inserted, err := tx.insertReviewer(prID, reviewerUserID, isFallback)
+		if err != nil || !inserted {
+			return err
+		}
+
+		return tx.insertAssignmentHistory(prID, nil, reviewerUserID, nil)
+	})
+}
+
+// RemoveReviewer удаляет ревьювера с Pull Request
+func (r *Repository) RemoveReviewer(ctx context.Context, prID, reviewerUserID string) error {
+	return r.inTx(ctx, func(tx *Repository) error {
+		tx.deleteReviewer(prID, reviewerUserID)
+		return nil
+	})
+}
+
+// GetReviewersByPRID получает всех ревьюверов Pull Request по его ID
+func (r *Repository) GetReviewersByPRID(ctx context.Context, prID string) ([]string, error) {
+	var res []string
+	err := r.read(ctx, func() error {
+		for _, a := range r.st.reviewers[prID] {
+			res = append(res, a.ReviewerUserID)
+		}
+		return nil
+	})
+	return res, err
+}
+
+// GetReviewerAssignmentsByPRID получает назначения ревьюверов Pull Request по его ID
+func (r *Repository) GetReviewerAssignmentsByPRID(ctx context.Context, prID string) ([]repository.PRReviewerModel, error) {
+	var res []repository.PRReviewerModel
+	err := r.read(ctx, func() error {
+		res = slices.Clone(r.st.reviewers[prID])
+		return nil
+	})
+	return res, err
+}
+
+// GetPRsByReviewerID получает все Pull Request, где пользователь назначен ревьювером
+func (r *Repository) GetPRsByReviewerID(ctx context.Context, reviewerUserID string) ([]repository.PullRequestModel, error) {
+	var res []repository.PullRequestModel
+	err := r.read(ctx, func() error {
+		res = r.st.filterPRs(func(pr repository.PullRequestModel) bool {
+			return r.st.assigned(pr.PullRequestID, reviewerUserID)
+		})
+		return nil
+	})
+	return res, err
+}
+
+// IsReviewerAssigned проверяет, назначен ли ревьювер на Pull Request
+func (r *Repository) IsReviewerAssigned(ctx context.Context, prID, reviewerUserID string) (bool, error) {
+	var assigned bool
+	err := r.read(ctx, func() error {
+		assigned = r.st.assigned(prID, reviewerUserID)
+		return nil
+	})
+	return assigned, err
+}
+
+// ReplaceReviewer заменяет одного ревьювера на другого для заданного Pull Request
+func (r *Repository) ReplaceReviewer(ctx context.Context, prID, oldReviewerID, newReviewerID string, isFallback bool, reason *string) error {
+	return r.inTx(ctx, func(tx *Repository) error {
+		tx.deleteReviewer(prID, oldReviewerID)
+		inserted, err := tx.insertReviewer(prID, newReviewerID, isFallback)
+		if err != nil {
+			return err
+		}
+		if !inserted {
+			return repository.ErrAlreadyExists
+		}
+
+		return tx.insertAssignmentHistory(prID, &oldReviewerID, newReviewerID, reason)
+	})
+}
+
+// GetAssignmentHistory получает историю назначений ревьюверов Pull Request в хронологическом порядке
+func (r *Repository) GetAssignmentHistory(ctx context.Context, prID string) ([]repository.ReviewerAssignmentHistoryModel, error) {
+	var res []repository.ReviewerAssignmentHistoryModel
+	err := r.read(ctx, func() error {
+		res = slices.Clone(r.st.history[prID])
+		return nil
+	})
+	return res, err
+}
+
+// LockOpenReviews возвращает OPEN Pull Request, где назначен кто-то из reviewerIDs, с их ревьюверами.
+// Отдельная блокировка не нужна: транзакция хранилища в памяти исключительная
+func (r *Repository) LockOpenReviews(ctx context.Context, reviewerIDs []string) ([]repository.OpenReview, error) {
+	if len(reviewerIDs) == 0 {
+		return nil, nil
+	}
+	var res []repository.OpenReview
+	err := r.read(ctx, func() error {
+		prs := r.st.filterPRs(func(pr repository.PullRequestModel) bool { return pr.Status == statusOpen })
+		slices.SortFunc(prs, func(a, b repository.PullRequestModel) int { return cmp.Compare(a.PullRequestID, b.PullRequestID) })
+		for _, pr := range prs {
+			var reviewers []string
+			affected := false
+			for _, a := range r.st.reviewers[pr.PullRequestID] {
+				reviewers = append(reviewers, a.ReviewerUserID)
+				affected = affected || slices.Contains(reviewerIDs, a.ReviewerUserID)
+			}
+			if !affected {
+				continue
+			}
+			slices.Sort(reviewers)
+			res = append(res, repository.OpenReview{PullRequestID: pr.PullRequestID, AuthorID: pr.AuthorID, Reviewers: reviewers})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ApplyReviewerReplacements применяет замены ревьюверов: удаление, вставка и история
+// Назначения без кандидата остаются как есть
+func (r *Repository) ApplyReviewerReplacements(ctx context.Context, replacements []repository.ReviewerReplacement, reason *string) error {
+	return r.inTx(ctx, func(tx *Repository) error {
+		for _, rp := range replacements {
+			if rp.NewReviewerUserID != nil {
+				tx.deleteReviewer(rp.PullRequestID, rp.OldReviewerUserID)
+			}
+		}
+		for _, rp := range replacements {
+			if rp.NewReviewerUserID == nil {
+				continue
+			}
+			inserted, err := tx.insertReviewer(rp.PullRequestID, *rp.NewReviewerUserID, rp.IsFallback)
+			if err != nil {
+				return err
+			}
+			if !inserted {
+				return fmt.Errorf("reviewer %q on pull request %q: %w", *rp.NewReviewerUserID, rp.PullRequestID, repository.ErrAlreadyExists)
+			}
+		}
+		for _, rp := range replacements {
+			if rp.NewReviewerUserID == nil {
+				continue
+			}
+			old := rp.OldReviewerUserID
+			if err := tx.insertAssignmentHistory(rp.PullRequestID, &old, *rp.NewReviewerUserID, reason); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CountReviewersByPRID подсчитывает количество ревьюверов, назначенных на Pull Request
+func (r *Repository) CountReviewersByPRID(ctx context.Context, prID string) (int, error) {
+	var cnt int
+	err := r.read(ctx, func() error {
+		cnt = len(r.st.reviewers[prID])
+		return nil
+	})
+	return cnt, err
+}
+
+// insertReviewer добавляет ревьювера на Pull Request без записи в историю
+// Возвращает false, если ревьювер уже был назначен
+func (r *Repository) insertReviewer(prID, reviewerUserID string, isFallback bool) (bool, error) {
+	if _, err := r.st.pr(prID); err != nil {
+		return false, fmt.Errorf("reviewer assignment references pull request %q: %w", prID, err)
+	}
+	if _, err := r.st.user(reviewerUserID); err != nil {
+		return false, fmt.Errorf("reviewer assignment references user %q: %w", reviewerUserID, err)
+	}
+	if r.st.assigned(prID, reviewerUserID) {
+		return false, nil
+	}
+	a := repository.PRReviewerModel{
+		ID: nextID(&r.st.reviewerSeq), PullRequestID: prID, ReviewerUserID: reviewerUserID, IsFallback: isFallback, AssignedAt: r.log.now,
+	}
+	// срез не меняется на месте: журнал отмены хранит прежнее значение
+	put(r.log, r.st.reviewers, prID, append(slices.Clip(r.st.reviewers[prID]), a))
+	return true, nil
+}
+
+// deleteReviewer снимает ревьювера с Pull Request, если он назначен
+func (r *Repository) deleteReviewer(prID, reviewerUserID string) {
+	if !r.st.assigned(prID, reviewerUserID) {
+		return
+	}
+	rest := slices.DeleteFunc(slices.Clone(r.st.reviewers[prID]), func(a repository.PRReviewerModel) bool {
+		return a.ReviewerUserID == reviewerUserID
+	})
+	put(r.log, r.st.reviewers, prID, rest)
+}
+
+// insertAssignmentHistory записывает назначение ревьювера в историю
+// oldReviewerID пустой для первичного назначения
+func (r *Repository) insertAssignmentHistory(prID string, oldReviewerID *string, newReviewerID string, reason *string) error {
+	if _, err := r.st.pr(prID); err != nil {
+		return fmt.Errorf("assignment history references pull request %q: %w", prID, err)
+	}
+	h := repository.ReviewerAssignmentHistoryModel{
+		ID: nextID(&r.st.historySeq), PullRequestID: prID, OldReviewerUserID: oldReviewerID, NewReviewerUserID: newReviewerID,
+		ReassignedAt: r.log.now, Reason: reason,
+	}
+	put(r.log, r.st.history, prID, append(slices.Clip(r.st.history[prID]), h))
+	return nil
+}
+
+// ==================== Stats Repository Methods ====================
+
+// GetReviewerStats получает статистику по ревьюверам (кол-во назначений)
+func (r *Repository) GetReviewerStats(ctx context.Context) ([]repository.ReviewerStatRow, error) {
+	var stats []repository.ReviewerStatRow
+	err := r.read(ctx, func() error {
+		counts := map[string]int{}
+		for _, assignments := range r.st.reviewers {
+			for _, a := range assignments {
+				counts[a.ReviewerUserID]++
+			}
+		}
+		users := sortedByID(r.st.users, func(u repository.UserModel) int64 { return u.ID })
+		for _, u := range users {
+			stats = append(stats, repository.ReviewerStatRow{UserID: u.UserID, Username: u.Username, AssignedCount: counts[u.UserID]})
+		}
+		slices.SortStableFunc(stats, func(a, b repository.ReviewerStatRow) int { return cmp.Compare(b.AssignedCount, a.AssignedCount) })
+		return nil
+	})
+	return stats, err
+}
+
+// GetPRStats получает статистику по Pull Requests (кол-во назначенных ревьюверов)
+func (r *Repository) GetPRStats(ctx context.Context) ([]repository.PRStatRow, error) {
+	var stats []repository.PRStatRow
+	err := r.read(ctx, func() error {
+		for _, pr := range r.st.filterPRs(func(repository.PullRequestModel) bool { return true }) {
+			stats = append(stats, repository.PRStatRow{
+				PullRequestID: pr.PullRequestID, PullRequestName: pr.PullRequestName, AuthorID: pr.AuthorID,
+				Status: pr.Status, ReviewerCount: len(r.st.reviewers[pr.PullRequestID]),
+			})
+		}
+		slices.SortStableFunc(stats, func(a, b repository.PRStatRow) int { return cmp.Compare(b.ReviewerCount, a.ReviewerCount) })
+		return nil
+	})
+	return stats, err
+}
+
+// ==================== store helpers ====================
+
+// team возвращает команду по id или ErrNotFound
+func (s *store) team(id int64) (repository.TeamModel, error) {
+	tm, ok := s.teams[id]
+	if !ok {
+		return tm, repository.ErrNotFound
+	}
+	return tm, nil
+}
+
+// user возвращает пользователя по user_id или ErrNotFound
+func (s *store) user(userID string) (repository.UserModel, error) {
+	u, ok := s.users[userID]
+	if !ok {
+		return u, repository.ErrNotFound
+	}
+	return u, nil
+}
+
+// pr возвращает Pull Request по pull_request_id или ErrNotFound
+func (s *store) pr(prID string) (repository.PullRequestModel, error) {
+	pr, ok := s.prs[prID]
+	if !ok {
+		return pr, repository.ErrNotFound
+	}
+	return pr, nil
+}
+
+// assigned проверяет, назначен ли ревьювер на Pull Request
+func (s *store) assigned(prID, reviewerUserID string) bool {
+	return slices.ContainsFunc(s.reviewers[prID], func(a repository.PRReviewerModel) bool {
+		return a.ReviewerUserID == reviewerUserID
+	})
+}
+
+// teamUsers возвращает участников команды в порядке id, activeOnly — только активных
+func (s *store) teamUsers(teamID int64, activeOnly bool) []repository.UserModel {
+	var res []repository.UserModel
+	for _, u := range sortedByID(s.users, func(u repository.UserModel) int64 { return u.ID }) {
+		if u.TeamID == teamID && (u.IsActive || !activeOnly) {
+			res = append(res, u)
+		}
+	}
+	return res
+}
+
+// filterPRs возвращает Pull Request, подходящие под условие, в порядке id
+func (s *store) filterPRs(match func(pr repository.PullRequestModel) bool) []repository.PullRequestModel {
+	var res []repository.PullRequestModel
+	for _, pr := range sortedByID(s.prs, func(pr repository.PullRequestModel) int64 { return pr.ID }) {
+		if match(pr) {
+			res = append(res, pr)
+		}
+	}
+	return res
+}
+
+// openReviews считает для каждого ревьювера назначения на OPEN Pull Request
+func (s *store) openReviews() map[string]int {
+	load := map[string]int{}
+	for prID, assignments := range s.reviewers {
+		if s.prs[prID].Status != statusOpen {
+			continue
+		}
+		for _, a := range assignments {
+			load[a.ReviewerUserID]++
+		}
+	}
+	return load
+}
+
+// sortedByID возвращает значения m, упорядоченные по id, как строки таблицы по первичному ключу
+func sortedByID[K comparable, V any](m map[K]V, id func(V) int64) []V {
+	res := make([]V, 0, len(m))
+	for _, v := range m {
+		res = append(res, v)
+	}
+	slices.SortFunc(res, func(a, b V) int { return cmp.Compare(id(a), id(b)) })
+	return res
+}
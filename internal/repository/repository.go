@@ -2,10 +2,13 @@ package repository
 
 import (
 	"context"
+	"errors"
 
 	"avito-test-quest/internal/logger"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"go.uber.org/zap"
 )
 
@@ -22,6 +25,32 @@ func NewPrRepository(db DB) Repository {
 	}
 }
 
+// WithTx выполняет fn в транзакции
+// fn получает репозиторий, работающий поверх транзакции; при ошибке или панике транзакция откатывается.
+// Вызов внутри другой транзакции создает savepoint
+func (r *PrRepository) WithTx(ctx context.Context, fn func(repo Repository) error) error {
+	return r.inTx(ctx, func(tx *PrRepository) error {
+		return fn(tx)
+	})
+}
+
+// inTx выполняет fn в транзакции поверх текущего соединения
+func (r *PrRepository) inTx(ctx context.Context, fn func(tx *PrRepository) error) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		logger.GetOrCreateLoggerFromCtx(ctx).Error(ctx, "failed to begin transaction", zap.Error(err))
+		return err
+	}
+	// после Commit откат вернет ErrTxClosed, его игнорируем
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := fn(&PrRepository{db: tx, psql: r.psql}); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
 // ==================== Team Repository Methods ====================
 
 // CreateTeam создает новую команду
@@ -35,6 +64,9 @@ func (r *PrRepository) CreateTeam(ctx context.Context, teamName string) (*TeamMo
 	var tm TeamModel
 	row := r.db.QueryRow(ctx, sql, args...)
 	if err := row.Scan(&tm.ID, &tm.TeamName, &tm.CreatedAt, &tm.UpdatedAt); err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrAlreadyExists
+		}
 		logger.GetOrCreateLoggerFromCtx(ctx).Error(ctx, "failed to scan CreateTeam", zap.Error(err))
 		return nil, err
 	}
@@ -85,18 +117,115 @@ func (r *PrRepository) TeamExists(ctx context.Context, teamName string) (bool, e
 	return cnt > 0, nil
 }
 
+// ==================== Team Settings Repository Methods ====================
+
+// GetTeamSettings получает настройки команды, nil если они не задавались
+func (r *PrRepository) GetTeamSettings(ctx context.Context, teamID int64) (*TeamSettingsModel, error) {
+	sql, args, err := r.psql.Select("team_id", "reviewers_count", "strategy", "max_open_reviews", "created_at", "updated_at").From("team_settings").Where(sq.Eq{"team_id": teamID}).ToSql()
+	if err != nil {
+		logger.GetOrCreateLoggerFromCtx(ctx).Error(ctx, "failed to build sql for GetTeamSettings", zap.Error(err))
+		return nil, err
+	}
+	var ts TeamSettingsModel
+	row := r.db.QueryRow(ctx, sql, args...)
+	if err := row.Scan(&ts.TeamID, &ts.ReviewersCount, &ts.Strategy, &ts.MaxOpenReviews, &ts.CreatedAt, &ts.UpdatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &ts, nil
+}
+
+// UpsertTeamSettings создает или обновляет настройки команды
+func (r *PrRepository) UpsertTeamSettings(ctx context.Context, settings TeamSettingsModel) (*TeamSettingsModel, error) {
+	sql, args, err := r.psql.Insert("team_settings").Columns("team_id", "reviewers_count", "strategy", "max_open_reviews").
+		Values(settings.TeamID, settings.ReviewersCount, settings.Strategy, settings.MaxOpenReviews).
+		Suffix("ON CONFLICT (team_id) DO UPDATE SET reviewers_count = EXCLUDED.reviewers_count, strategy = EXCLUDED.strategy, max_open_reviews = EXCLUDED.max_open_reviews, updated_at = CURRENT_TIMESTAMP").
+		Suffix("RETURNING team_id, reviewers_count, strategy, max_open_reviews, created_at, updated_at").ToSql()
+	if err != nil {
+		logger.GetOrCreateLoggerFromCtx(ctx).Error(ctx, "failed to build sql for UpsertTeamSettings", zap.Error(err))
+		return nil, err
+	}
+	var ts TeamSettingsModel
+	row := r.db.QueryRow(ctx, sql, args...)
+	if err := row.Scan(&ts.TeamID, &ts.ReviewersCount, &ts.Strategy, &ts.MaxOpenReviews, &ts.CreatedAt, &ts.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &ts, nil
+}
+
+// GetFallbackTeams получает резервные команды в порядке приоритета
+func (r *PrRepository) GetFallbackTeams(ctx context.Context, teamID int64) ([]TeamModel, error) {
+	sql, args, err := r.psql.Select("t.id", "t.team_name", "t.created_at", "t.updated_at").
+		From("team_fallbacks f").
+		Join("teams t ON t.id = f.fallback_team_id").
+		Where(sq.Eq{"f.team_id": teamID}).
+		OrderBy("f.position").
+		ToSql()
+	if err != nil {
+		logger.GetOrCreateLoggerFromCtx(ctx).Error(ctx, "failed to build sql for GetFallbackTeams", zap.Error(err))
+		return nil, err
+	}
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []TeamModel
+	for rows.Next() {
+		var tm TeamModel
+		if err := rows.Scan(&tm.ID, &tm.TeamName, &tm.CreatedAt, &tm.UpdatedAt); err != nil {
+			return nil, err
+		}
+		res = append(res, tm)
+	}
+	return res, nil
+}
+
+// SetFallbackTeams заменяет список резервных команд
+func (r *PrRepository) SetFallbackTeams(ctx context.Context, teamID int64, fallbackTeamIDs []int64) error {
+	// удаление старого списка и вставка нового атомарны
+	return r.inTx(ctx, func(tx *PrRepository) error {
+		sql, args, err := tx.psql.Delete("team_fallbacks").Where(sq.Eq{"team_id": teamID}).ToSql()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.db.Exec(ctx, sql, args...); err != nil {
+			return err
+		}
+		if len(fallbackTeamIDs) == 0 {
+			return nil
+		}
+		ib := tx.psql.Insert("team_fallbacks").Columns("team_id", "fallback_team_id", "position")
+		for i, id := range fallbackTeamIDs {
+			ib = ib.Values(teamID, id, i)
+		}
+		sql, args, err = ib.ToSql()
+		if err != nil {
+			return err
+		}
+		_, err = tx.db.Exec(ctx, sql, args...)
+
+		return err
+	})
+}
+
 // ==================== User Repository Methods ====================
 
 // CreateUser создает нового пользователя
 func (r *PrRepository) CreateUser(ctx context.Context, userID, username string, teamID int64, isActive bool) (*UserModel, error) {
 	sql, args, err := r.psql.Insert("users").Columns("user_id", "username", "team_id", "is_active").Values(userID, username, teamID, isActive).
-		Suffix("RETURNING id, user_id, username, team_id, is_active, created_at, updated_at").ToSql()
+		Suffix("RETURNING id, user_id, username, team_id, is_active, role, created_at, updated_at").ToSql()
 	if err != nil {
 		return nil, err
 	}
 	var u UserModel
 	row := r.db.QueryRow(ctx, sql, args...)
-	if err := row.Scan(&u.ID, &u.UserID, &u.Username, &u.TeamID, &u.IsActive, &u.CreatedAt, &u.UpdatedAt); err != nil {
+	if err := row.Scan(&u.ID, &u.UserID, &u.Username, &u.TeamID, &u.IsActive, &u.Role, &u.CreatedAt, &u.UpdatedAt); err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrAlreadyExists
+		}
 		return nil, err
 	}
 	return &u, nil
@@ -105,13 +234,13 @@ func (r *PrRepository) CreateUser(ctx context.Context, userID, username string,
 // UpdateUser обновляет данные пользователя
 func (r *PrRepository) UpdateUser(ctx context.Context, userID, username string, teamID int64, isActive bool) (*UserModel, error) {
 	sql, args, err := r.psql.Update("users").Set("username", username).Set("team_id", teamID).Set("is_active", isActive).
-		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).Where(sq.Eq{"user_id": userID}).Suffix("RETURNING id, user_id, username, team_id, is_active, created_at, updated_at").ToSql()
+		Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).Where(sq.Eq{"user_id": userID}).Suffix("RETURNING id, user_id, username, team_id, is_active, role, created_at, updated_at").ToSql()
 	if err != nil {
 		return nil, err
 	}
 	var u UserModel
 	row := r.db.QueryRow(ctx, sql, args...)
-	if err := row.Scan(&u.ID, &u.UserID, &u.Username, &u.TeamID, &u.IsActive, &u.CreatedAt, &u.UpdatedAt); err != nil {
+	if err := row.Scan(&u.ID, &u.UserID, &u.Username, &u.TeamID, &u.IsActive, &u.Role, &u.CreatedAt, &u.UpdatedAt); err != nil {
 		return nil, err
 	}
 	return &u, nil
@@ -119,13 +248,13 @@ func (r *PrRepository) UpdateUser(ctx context.Context, userID, username string,
 
 // GetUserByID получает пользователя по userID
 func (r *PrRepository) GetUserByID(ctx context.Context, userID string) (*UserModel, error) {
-	sql, args, err := r.psql.Select("id", "user_id", "username", "team_id", "is_active", "created_at", "updated_at").From("users").Where(sq.Eq{"user_id": userID}).ToSql()
+	sql, args, err := r.psql.Select("id", "user_id", "username", "team_id", "is_active", "role", "created_at", "updated_at").From("users").Where(sq.Eq{"user_id": userID}).ToSql()
 	if err != nil {
 		return nil, err
 	}
 	var u UserModel
 	row := r.db.QueryRow(ctx, sql, args...)
-	if err := row.Scan(&u.ID, &u.UserID, &u.Username, &u.TeamID, &u.IsActive, &u.CreatedAt, &u.UpdatedAt); err != nil {
+	if err := row.Scan(&u.ID, &u.UserID, &u.Username, &u.TeamID, &u.IsActive, &u.Role, &u.CreatedAt, &u.UpdatedAt); err != nil {
 		return nil, err
 	}
 	return &u, nil
@@ -148,7 +277,7 @@ func (r *PrRepository) GetUserWithTeam(ctx context.Context, userID string) (*Use
 
 // GetUsersByTeamID получает всех пользователей команды
 func (r *PrRepository) GetUsersByTeamID(ctx context.Context, teamID int64) ([]UserModel, error) {
-	sql, args, err := r.psql.Select("id", "user_id", "username", "team_id", "is_active", "created_at", "updated_at").From("users").Where(sq.Eq{"team_id": teamID}).ToSql()
+	sql, args, err := r.psql.Select("id", "user_id", "username", "team_id", "is_active", "role", "created_at", "updated_at").From("users").Where(sq.Eq{"team_id": teamID}).ToSql()
 	if err != nil {
 		return nil, err
 	}
@@ -160,7 +289,7 @@ func (r *PrRepository) GetUsersByTeamID(ctx context.Context, teamID int64) ([]Us
 	var res []UserModel
 	for rows.Next() {
 		var u UserModel
-		if err := rows.Scan(&u.ID, &u.UserID, &u.Username, &u.TeamID, &u.IsActive, &u.CreatedAt, &u.UpdatedAt); err != nil {
+		if err := rows.Scan(&u.ID, &u.UserID, &u.Username, &u.TeamID, &u.IsActive, &u.Role, &u.CreatedAt, &u.UpdatedAt); err != nil {
 			return nil, err
 		}
 		res = append(res, u)
@@ -170,18 +299,60 @@ func (r *PrRepository) GetUsersByTeamID(ctx context.Context, teamID int64) ([]Us
 
 // SetIsActive обновляет флаг активности пользователя
 func (r *PrRepository) SetIsActive(ctx context.Context, userID string, isActive bool) (*UserModel, error) {
-	sql, args, err := r.psql.Update("users").Set("is_active", isActive).Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).Where(sq.Eq{"user_id": userID}).Suffix("RETURNING id, user_id, username, team_id, is_active, created_at, updated_at").ToSql()
+	sql, args, err := r.psql.Update("users").Set("is_active", isActive).Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).Where(sq.Eq{"user_id": userID}).Suffix("RETURNING id, user_id, username, team_id, is_active, role, created_at, updated_at").ToSql()
 	if err != nil {
 		return nil, err
 	}
 	var u UserModel
 	row := r.db.QueryRow(ctx, sql, args...)
-	if err := row.Scan(&u.ID, &u.UserID, &u.Username, &u.TeamID, &u.IsActive, &u.CreatedAt, &u.UpdatedAt); err != nil {
+	if err := row.Scan(&u.ID, &u.UserID, &u.Username, &u.TeamID, &u.IsActive, &u.Role, &u.CreatedAt, &u.UpdatedAt); err != nil {
 		return nil, err
 	}
 	return &u, nil
 }
 
+// SetUserRole обновляет роль пользователя
+func (r *PrRepository) SetUserRole(ctx context.Context, userID, role string) (*UserModel, error) {
+	sql, args, err := r.psql.Update("users").Set("role", role).Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).Where(sq.Eq{"user_id": userID}).Suffix("RETURNING id, user_id, username, team_id, is_active, role, created_at, updated_at").ToSql()
+	if err != nil {
+		return nil, err
+	}
+	var u UserModel
+	row := r.db.QueryRow(ctx, sql, args...)
+	if err := row.Scan(&u.ID, &u.UserID, &u.Username, &u.TeamID, &u.IsActive, &u.Role, &u.CreatedAt, &u.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// DeactivateTeamMembers деактивирует участников команды одним запросом
+func (r *PrRepository) DeactivateTeamMembers(ctx context.Context, teamID int64, userIDs []string, allExcept bool) ([]string, error) {
+	ub := r.psql.Update("users").Set("is_active", false).Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).Where(sq.Eq{"team_id": teamID, "is_active": true})
+	if allExcept {
+		ub = ub.Where(sq.NotEq{"user_id": userIDs})
+	} else {
+		ub = ub.Where(sq.Eq{"user_id": userIDs})
+	}
+	sql, args, err := ub.Suffix("RETURNING user_id").ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []string
+	for rows.Next() {
+		var uid string
+		if err := rows.Scan(&uid); err != nil {
+			return nil, err
+		}
+		res = append(res, uid)
+	}
+	return res, rows.Err()
+}
+
 // UserExists проверяет существование пользователя
 func (r *PrRepository) UserExists(ctx context.Context, userID string) (bool, error) {
 	sql, args, err := r.psql.Select("count(1)").From("users").Where(sq.Eq{"user_id": userID}).ToSql()
@@ -198,7 +369,7 @@ func (r *PrRepository) UserExists(ctx context.Context, userID string) (bool, err
 
 // GetActiveUsersInTeam получает активных пользователей команды
 func (r *PrRepository) GetActiveUsersInTeam(ctx context.Context, teamID int64) ([]UserModel, error) {
-	sql, args, err := r.psql.Select("id", "user_id", "username", "team_id", "is_active", "created_at", "updated_at").From("users").Where(sq.Eq{"team_id": teamID, "is_active": true}).ToSql()
+	sql, args, err := r.psql.Select("id", "user_id", "username", "team_id", "is_active", "role", "created_at", "updated_at").From("users").Where(sq.Eq{"team_id": teamID, "is_active": true}).ToSql()
 	if err != nil {
 		return nil, err
 	}
@@ -210,7 +381,7 @@ func (r *PrRepository) GetActiveUsersInTeam(ctx context.Context, teamID int64) (
 	var res []UserModel
 	for rows.Next() {
 		var u UserModel
-		if err := rows.Scan(&u.ID, &u.UserID, &u.Username, &u.TeamID, &u.IsActive, &u.CreatedAt, &u.UpdatedAt); err != nil {
+		if err := rows.Scan(&u.ID, &u.UserID, &u.Username, &u.TeamID, &u.IsActive, &u.Role, &u.CreatedAt, &u.UpdatedAt); err != nil {
 			return nil, err
 		}
 		res = append(res, u)
@@ -218,18 +389,51 @@ func (r *PrRepository) GetActiveUsersInTeam(ctx context.Context, teamID int64) (
 	return res, nil
 }
 
+// GetActiveUsersWithLoad получает активных пользователей команды с количеством открытых ревью
+func (r *PrRepository) GetActiveUsersWithLoad(ctx context.Context, teamID int64) ([]ReviewerCandidate, error) {
+	sql, args, err := r.psql.Select("u.id", "u.user_id", "u.username", "u.team_id", "u.is_active", "u.created_at", "u.updated_at", "COUNT(p.id) AS open_reviews").
+		From("users u").
+		LeftJoin("pr_reviewers r ON r.reviewer_user_id = u.user_id").
+		LeftJoin("pull_requests p ON p.pull_request_id = r.pull_request_id AND p.status = 'OPEN'").
+		Where(sq.Eq{"u.team_id": teamID, "u.is_active": true}).
+		GroupBy("u.id").
+		ToSql()
+	if err != nil {
+		logger.GetOrCreateLoggerFromCtx(ctx).Error(ctx, "failed to build sql for GetActiveUsersWithLoad", zap.Error(err))
+		return nil, err
+	}
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []ReviewerCandidate
+	for rows.Next() {
+		var c ReviewerCandidate
+		if err := rows.Scan(&c.ID, &c.UserID, &c.Username, &c.TeamID, &c.IsActive, &c.CreatedAt, &c.UpdatedAt, &c.OpenReviews); err != nil {
+			return nil, err
+		}
+		res = append(res, c)
+	}
+	return res, nil
+}
+
 // ==================== Pull Request Repository Methods ====================
 
 // CreatePullRequest создает новый Pull Request
+// Конкурентная вставка того же pull_request_id ждет первую транзакцию и возвращает ErrAlreadyExists
 func (r *PrRepository) CreatePullRequest(ctx context.Context, prID, prName, authorID string) (*PullRequestModel, error) {
 	sql, args, err := r.psql.Insert("pull_requests").Columns("pull_request_id", "pull_request_name", "author_id").Values(prID, prName, authorID).
-		Suffix("RETURNING id, pull_request_id, pull_request_name, author_id, status, created_at, merged_at, updated_at").ToSql()
+		Suffix("ON CONFLICT (pull_request_id) DO NOTHING RETURNING id, pull_request_id, pull_request_name, author_id, status, need_more_reviewers, created_at, merged_at, updated_at").ToSql()
 	if err != nil {
 		return nil, err
 	}
 	var pr PullRequestModel
 	row := r.db.QueryRow(ctx, sql, args...)
-	if err := row.Scan(&pr.ID, &pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &pr.UpdatedAt); err != nil {
+	if err := row.Scan(&pr.ID, &pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &pr.NeedMoreReviewers, &pr.CreatedAt, &pr.MergedAt, &pr.UpdatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrAlreadyExists
+		}
 		return nil, err
 	}
 
@@ -238,13 +442,27 @@ func (r *PrRepository) CreatePullRequest(ctx context.Context, prID, prName, auth
 
 // GetPullRequestByID получает Pull Request по его ID
 func (r *PrRepository) GetPullRequestByID(ctx context.Context, prID string) (*PullRequestModel, error) {
-	sql, args, err := r.psql.Select("id", "pull_request_id", "pull_request_name", "author_id", "status", "created_at", "merged_at", "updated_at").From("pull_requests").Where(sq.Eq{"pull_request_id": prID}).ToSql()
+	return r.getPullRequest(ctx, prID, "")
+}
+
+// LockPullRequest получает Pull Request по его ID и блокирует строку до конца транзакции
+func (r *PrRepository) LockPullRequest(ctx context.Context, prID string) (*PullRequestModel, error) {
+	return r.getPullRequest(ctx, prID, "FOR UPDATE")
+}
+
+// getPullRequest получает Pull Request по его ID, suffix задает режим блокировки
+func (r *PrRepository) getPullRequest(ctx context.Context, prID, suffix string) (*PullRequestModel, error) {
+	sb := r.psql.Select("id", "pull_request_id", "pull_request_name", "author_id", "status", "need_more_reviewers", "created_at", "merged_at", "updated_at").From("pull_requests").Where(sq.Eq{"pull_request_id": prID})
+	if suffix != "" {
+		sb = sb.Suffix(suffix)
+	}
+	sql, args, err := sb.ToSql()
 	if err != nil {
 		return nil, err
 	}
 	var pr PullRequestModel
 	row := r.db.QueryRow(ctx, sql, args...)
-	if err := row.Scan(&pr.ID, &pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &pr.UpdatedAt); err != nil {
+	if err := row.Scan(&pr.ID, &pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &pr.NeedMoreReviewers, &pr.CreatedAt, &pr.MergedAt, &pr.UpdatedAt); err != nil {
 		return nil, err
 	}
 
@@ -257,23 +475,30 @@ func (r *PrRepository) GetPullRequestWithReviewers(ctx context.Context, prID str
 	if err != nil {
 		return nil, err
 	}
-	reviewers, err := r.GetReviewersByPRID(ctx, prID)
+	assignments, err := r.GetReviewerAssignmentsByPRID(ctx, prID)
 	if err != nil {
 		return nil, err
 	}
+	res := &PRWithReviewers{PullRequest: pr}
+	for _, a := range assignments {
+		res.Reviewers = append(res.Reviewers, a.ReviewerUserID)
+		if a.IsFallback {
+			res.FallbackReviewers = append(res.FallbackReviewers, a.ReviewerUserID)
+		}
+	}
 
-	return &PRWithReviewers{PullRequest: pr, Reviewers: reviewers}, nil
+	return res, nil
 }
 
 // MergePullRequest помечает Pull Request как замерженный
 func (r *PrRepository) MergePullRequest(ctx context.Context, prID string) (*PullRequestModel, error) {
-	sql, args, err := r.psql.Update("pull_requests").Set("status", "MERGED").Set("merged_at", sq.Expr("CURRENT_TIMESTAMP")).Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).Where(sq.Eq{"pull_request_id": prID}).Suffix("RETURNING id, pull_request_id, pull_request_name, author_id, status, created_at, merged_at, updated_at").ToSql()
+	sql, args, err := r.psql.Update("pull_requests").Set("status", "MERGED").Set("merged_at", sq.Expr("CURRENT_TIMESTAMP")).Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).Where(sq.Eq{"pull_request_id": prID}).Suffix("RETURNING id, pull_request_id, pull_request_name, author_id, status, need_more_reviewers, created_at, merged_at, updated_at").ToSql()
 	if err != nil {
 		return nil, err
 	}
 	var pr PullRequestModel
 	row := r.db.QueryRow(ctx, sql, args...)
-	if err := row.Scan(&pr.ID, &pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &pr.UpdatedAt); err != nil {
+	if err := row.Scan(&pr.ID, &pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &pr.NeedMoreReviewers, &pr.CreatedAt, &pr.MergedAt, &pr.UpdatedAt); err != nil {
 		return nil, err
 	}
 
@@ -297,7 +522,7 @@ func (r *PrRepository) PullRequestExists(ctx context.Context, prID string) (bool
 
 // GetPullRequestsByAuthor получает все Pull Request, созданные автором
 func (r *PrRepository) GetPullRequestsByAuthor(ctx context.Context, authorID string) ([]PullRequestModel, error) {
-	sql, args, err := r.psql.Select("id", "pull_request_id", "pull_request_name", "author_id", "status", "created_at", "merged_at", "updated_at").From("pull_requests").Where(sq.Eq{"author_id": authorID}).ToSql()
+	sql, args, err := r.psql.Select("id", "pull_request_id", "pull_request_name", "author_id", "status", "need_more_reviewers", "created_at", "merged_at", "updated_at").From("pull_requests").Where(sq.Eq{"author_id": authorID}).ToSql()
 	if err != nil {
 		return nil, err
 	}
@@ -309,7 +534,7 @@ func (r *PrRepository) GetPullRequestsByAuthor(ctx context.Context, authorID str
 	var res []PullRequestModel
 	for rows.Next() {
 		var pr PullRequestModel
-		if err := rows.Scan(&pr.ID, &pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &pr.UpdatedAt); err != nil {
+		if err := rows.Scan(&pr.ID, &pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &pr.NeedMoreReviewers, &pr.CreatedAt, &pr.MergedAt, &pr.UpdatedAt); err != nil {
 			return nil, err
 		}
 		res = append(res, pr)
@@ -318,20 +543,58 @@ func (r *PrRepository) GetPullRequestsByAuthor(ctx context.Context, authorID str
 	return res, nil
 }
 
-// ==================== PR Reviewer Repository Methods ====================
-
-// AssignReviewer назначает ревьювера на Pull Request
-func (r *PrRepository) AssignReviewer(ctx context.Context, prID, reviewerUserID string) error {
-	sql, args, err := r.psql.Insert("pr_reviewers").Columns("pull_request_id", "reviewer_user_id").Values(prID, reviewerUserID).Suffix("ON CONFLICT DO NOTHING").ToSql()
+// SetNeedMoreReviewers обновляет флаг нехватки ревьюверов на Pull Request
+func (r *PrRepository) SetNeedMoreReviewers(ctx context.Context, prID string, needMore bool) error {
+	sql, args, err := r.psql.Update("pull_requests").Set("need_more_reviewers", needMore).Set("updated_at", sq.Expr("CURRENT_TIMESTAMP")).Where(sq.Eq{"pull_request_id": prID}).ToSql()
 	if err != nil {
 		return err
 	}
 	_, err = r.db.Exec(ctx, sql, args...)
+
+	return err
+}
+
+// GetOpenPRsNeedingReviewers получает OPEN Pull Request авторов команды с флагом нехватки ревьюверов
+// Строки PR блокируются, чтобы параллельный добор не назначил лишних ревьюверов
+func (r *PrRepository) GetOpenPRsNeedingReviewers(ctx context.Context, teamID int64) ([]PullRequestModel, error) {
+	sql, args, err := r.psql.Select("p.id", "p.pull_request_id", "p.pull_request_name", "p.author_id", "p.status", "p.need_more_reviewers", "p.created_at", "p.merged_at", "p.updated_at").
+		From("pull_requests p").
+		Join("users u ON u.user_id = p.author_id").
+		Where(sq.Eq{"u.team_id": teamID, "p.status": "OPEN", "p.need_more_reviewers": true}).
+		OrderBy("p.created_at", "p.id").
+		Suffix("FOR UPDATE OF p").ToSql()
 	if err != nil {
-		return err
+		return nil, err
+	}
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []PullRequestModel
+	for rows.Next() {
+		var pr PullRequestModel
+		if err := rows.Scan(&pr.ID, &pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &pr.NeedMoreReviewers, &pr.CreatedAt, &pr.MergedAt, &pr.UpdatedAt); err != nil {
+			return nil, err
+		}
+		res = append(res, pr)
 	}
 
-	return nil
+	return res, nil
+}
+
+// ==================== PR Reviewer Repository Methods ====================
+
+// AssignReviewer назначает ревьювера на Pull Request и записывает назначение в историю
+func (r *PrRepository) AssignReviewer(ctx context.Context, prID, reviewerUserID string, isFallback bool) error {
+	return r.inTx(ctx, func(tx *PrRepository) error {
+		inserted, err := tx.insertReviewer(ctx, prID, reviewerUserID, isFallback)
+		if err != nil || !inserted {
+			return err
+		}
+
+		return tx.insertAssignmentHistory(ctx, prID, nil, reviewerUserID, nil)
+	})
 }
 
 // RemoveReviewer удаляет ревьювера с Pull Request
@@ -368,9 +631,32 @@ func (r *PrRepository) GetReviewersByPRID(ctx context.Context, prID string) ([]s
 	return res, nil
 }
 
+// GetReviewerAssignmentsByPRID получает назначения ревьюверов Pull Request по его ID
+func (r *PrRepository) GetReviewerAssignmentsByPRID(ctx context.Context, prID string) ([]PRReviewerModel, error) {
+	sql, args, err := r.psql.Select("id", "pull_request_id", "reviewer_user_id", "is_fallback", "assigned_at").From("pr_reviewers").Where(sq.Eq{"pull_request_id": prID}).OrderBy("assigned_at", "id").ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []PRReviewerModel
+	for rows.Next() {
+		var a PRReviewerModel
+		if err := rows.Scan(&a.ID, &a.PullRequestID, &a.ReviewerUserID, &a.IsFallback, &a.AssignedAt); err != nil {
+			return nil, err
+		}
+		res = append(res, a)
+	}
+
+	return res, nil
+}
+
 // GetPRsByReviewerID получает все Pull Request, где пользователь назначен ревьювером
 func (r *PrRepository) GetPRsByReviewerID(ctx context.Context, reviewerUserID string) ([]PullRequestModel, error) {
-	sb := r.psql.Select("p.id", "p.pull_request_id", "p.pull_request_name", "p.author_id", "p.status", "p.created_at", "p.merged_at", "p.updated_at").From("pull_requests p").Join("pr_reviewers r ON p.pull_request_id = r.pull_request_id").Where(sq.Eq{"r.reviewer_user_id": reviewerUserID})
+	sb := r.psql.Select("p.id", "p.pull_request_id", "p.pull_request_name", "p.author_id", "p.status", "p.need_more_reviewers", "p.created_at", "p.merged_at", "p.updated_at").From("pull_requests p").Join("pr_reviewers r ON p.pull_request_id = r.pull_request_id").Where(sq.Eq{"r.reviewer_user_id": reviewerUserID})
 	sql, args, err := sb.ToSql()
 	if err != nil {
 		return nil, err
@@ -383,7 +669,7 @@ func (r *PrRepository) GetPRsByReviewerID(ctx context.Context, reviewerUserID st
 	var res []PullRequestModel
 	for rows.Next() {
 		var pr PullRequestModel
-		if err := rows.Scan(&pr.ID, &pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &pr.UpdatedAt); err != nil {
+		if err := rows.Scan(&pr.ID, &pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &pr.NeedMoreReviewers, &pr.CreatedAt, &pr.MergedAt, &pr.UpdatedAt); err != nil {
 			return nil, err
 		}
 		res = append(res, pr)
@@ -408,18 +694,156 @@ func (r *PrRepository) IsReviewerAssigned(ctx context.Context, prID, reviewerUse
 }
 
 // ReplaceReviewer заменяет одного ревьювера на другого для заданного Pull Request
-func (r *PrRepository) ReplaceReviewer(ctx context.Context, prID, oldReviewerID, newReviewerID string) error {
-	// простая реализация: удалить старого и назначить нового.
-	if err := r.RemoveReviewer(ctx, prID, oldReviewerID); err != nil {
-		return err
+func (r *PrRepository) ReplaceReviewer(ctx context.Context, prID, oldReviewerID, newReviewerID string, isFallback bool, reason *string) error {
+	// удаление старого, назначение нового и запись в историю атомарны
+	return r.inTx(ctx, func(tx *PrRepository) error {
+		if err := tx.RemoveReviewer(ctx, prID, oldReviewerID); err != nil {
+			return err
+		}
+		inserted, err := tx.insertReviewer(ctx, prID, newReviewerID, isFallback)
+		if err != nil {
+			return err
+		}
+		// новый ревьювер уже назначен: замены не было, удаление откатывается и в историю ничего не пишется
+		if !inserted {
+			return ErrAlreadyExists
+		}
+
+		return tx.insertAssignmentHistory(ctx, prID, &oldReviewerID, newReviewerID, reason)
+	})
+}
+
+// GetAssignmentHistory получает историю назначений ревьюверов Pull Request в хронологическом порядке
+func (r *PrRepository) GetAssignmentHistory(ctx context.Context, prID string) ([]ReviewerAssignmentHistoryModel, error) {
+	sql, args, err := r.psql.Select("id", "pull_request_id", "old_reviewer_user_id", "new_reviewer_user_id", "reassigned_at", "reason").From("reviewer_assignment_history").Where(sq.Eq{"pull_request_id": prID}).OrderBy("reassigned_at", "id").ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []ReviewerAssignmentHistoryModel
+	for rows.Next() {
+		var h ReviewerAssignmentHistoryModel
+		if err := rows.Scan(&h.ID, &h.PullRequestID, &h.OldReviewerUserID, &h.NewReviewerUserID, &h.ReassignedAt, &h.Reason); err != nil {
+			return nil, err
+		}
+		res = append(res, h)
+	}
+
+	return res, nil
+}
+
+// insertReviewer добавляет ревьювера на Pull Request без записи в историю
+// Возвращает false, если ревьювер уже был назначен
+func (r *PrRepository) insertReviewer(ctx context.Context, prID, reviewerUserID string, isFallback bool) (bool, error) {
+	sql, args, err := r.psql.Insert("pr_reviewers").Columns("pull_request_id", "reviewer_user_id", "is_fallback").Values(prID, reviewerUserID, isFallback).Suffix("ON CONFLICT DO NOTHING").ToSql()
+	if err != nil {
+		return false, err
+	}
+	tag, err := r.db.Exec(ctx, sql, args...)
+	if err != nil {
+		return false, err
 	}
-	if err := r.AssignReviewer(ctx, prID, newReviewerID); err != nil {
-		// попытаться откатить удаление старого в случае ошибки назначения
-		_ = r.AssignReviewer(ctx, prID, oldReviewerID)
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// insertAssignmentHistory записывает назначение ревьювера в историю
+// oldReviewerID пустой для первичного назначения
+func (r *PrRepository) insertAssignmentHistory(ctx context.Context, prID string, oldReviewerID *string, newReviewerID string, reason *string) error {
+	sql, args, err := r.psql.Insert("reviewer_assignment_history").Columns("pull_request_id", "old_reviewer_user_id", "new_reviewer_user_id", "reason").Values(prID, oldReviewerID, newReviewerID, reason).ToSql()
+	if err != nil {
 		return err
 	}
+	_, err = r.db.Exec(ctx, sql, args...)
+
+	return err
+}
+
+// LockOpenReviews блокирует OPEN Pull Request, где назначен кто-то из reviewerIDs, и возвращает их ревьюверов
+func (r *PrRepository) LockOpenReviews(ctx context.Context, reviewerIDs []string) ([]OpenReview, error) {
+	if len(reviewerIDs) == 0 {
+		return nil, nil
+	}
+	// блокируем затронутые PR в порядке id, чтобы не пересечься с переназначением и мержем
+	sql, args, err := r.psql.Select("p.id").From("pull_requests p").
+		Join("pr_reviewers r ON r.pull_request_id = p.pull_request_id").
+		Where(sq.Eq{"p.status": "OPEN", "r.reviewer_user_id": reviewerIDs}).
+		OrderBy("p.id").
+		Suffix("FOR UPDATE OF p").ToSql()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.db.Exec(ctx, sql, args...); err != nil {
+		return nil, err
+	}
+	sql, args, err = r.psql.Select("p.pull_request_id", "p.author_id", "array_agg(r.reviewer_user_id ORDER BY r.reviewer_user_id)").
+		From("pull_requests p").
+		Join("pr_reviewers r ON r.pull_request_id = p.pull_request_id").
+		Where(sq.Eq{"p.status": "OPEN"}).
+		Where(sq.Expr("p.pull_request_id IN (SELECT pull_request_id FROM pr_reviewers WHERE reviewer_user_id = ANY(?))", reviewerIDs)).
+		GroupBy("p.pull_request_id", "p.author_id").
+		OrderBy("p.pull_request_id").ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		logger.GetOrCreateLoggerFromCtx(ctx).Error(ctx, "failed to get open reviews", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+	var res []OpenReview
+	for rows.Next() {
+		var rv OpenReview
+		if err := rows.Scan(&rv.PullRequestID, &rv.AuthorID, &rv.Reviewers); err != nil {
+			return nil, err
+		}
+		res = append(res, rv)
+	}
+	return res, rows.Err()
+}
+
+// ApplyReviewerReplacements применяет замены ревьюверов пакетно: удаление, вставка и история — по одному запросу
+// Назначения без кандидата остаются как есть
+func (r *PrRepository) ApplyReviewerReplacements(ctx context.Context, replacements []ReviewerReplacement, reason *string) error {
+	var prIDs, oldIDs, newIDs []string
+	var fallback []bool
+	for _, rp := range replacements {
+		if rp.NewReviewerUserID == nil {
+			continue
+		}
+		prIDs = append(prIDs, rp.PullRequestID)
+		oldIDs = append(oldIDs, rp.OldReviewerUserID)
+		newIDs = append(newIDs, *rp.NewReviewerUserID)
+		fallback = append(fallback, rp.IsFallback)
+	}
+	if len(prIDs) == 0 {
+		return nil
+	}
 
-	return nil
+	return r.inTx(ctx, func(tx *PrRepository) error {
+		if _, err := tx.db.Exec(ctx, `
+DELETE FROM pr_reviewers r
+USING unnest($1::varchar[], $2::varchar[]) AS d(pull_request_id, reviewer_user_id)
+WHERE r.pull_request_id = d.pull_request_id AND r.reviewer_user_id = d.reviewer_user_id`, prIDs, oldIDs); err != nil {
+			return err
+		}
+		if _, err := tx.db.Exec(ctx, `
+INSERT INTO pr_reviewers (pull_request_id, reviewer_user_id, is_fallback)
+SELECT * FROM unnest($1::varchar[], $2::varchar[], $3::boolean[])`, prIDs, newIDs, fallback); err != nil {
+			return err
+		}
+		_, err := tx.db.Exec(ctx, `
+INSERT INTO reviewer_assignment_history (pull_request_id, old_reviewer_user_id, new_reviewer_user_id, reason)
+SELECT h.pull_request_id, h.old_reviewer_user_id, h.new_reviewer_user_id, $4::text
+FROM unnest($1::varchar[], $2::varchar[], $3::varchar[]) AS h(pull_request_id, old_reviewer_user_id, new_reviewer_user_id)`, prIDs, oldIDs, newIDs, reason)
+
+		return err
+	})
 }
 
 // CountReviewersByPRID подсчитывает количество ревьюверов, назначенных на Pull Request
@@ -503,5 +927,14 @@ func (r *PrRepository) GetPRStats(ctx context.Context) ([]PRStatRow, error) {
 	return stats, nil
 }
 
+// uniqueViolation код ошибки Postgres при нарушении уникального ключа
+const uniqueViolation = "23505"
+
+// isUniqueViolation проверяет, что err — нарушение уникального ключа
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
+
 // проверка реализации интерфейса Repository
 var _ Repository = (*PrRepository)(nil)
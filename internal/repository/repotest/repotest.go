@@ -0,0 +1,740 @@
+// Package repotest общий набор тестов поведения repository.Repository.
+// Один и тот же набор запускается для каждой реализации хранилища, поэтому их поведение не расходится
+package repotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"avito-test-quest/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Factory возвращает репозиторий с пустым хранилищем; вызывается перед каждым подтестом
+type Factory func(t *testing.T) repository.Repository
+
+// Run запускает набор тестов для реализации, создаваемой newRepo.
+// Порядок строк проверяется только там, где его гарантирует интерфейс
+func Run(t *testing.T, newRepo Factory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, repo repository.Repository)
+	}{
+		{"Teams", testTeams},
+		{"TeamSettings", testTeamSettings},
+		{"FallbackTeams", testFallbackTeams},
+		{"Users", testUsers},
+		{"DeactivateTeamMembers", testDeactivateTeamMembers},
+		{"PullRequests", testPullRequests},
+		{"OpenPRsNeedingReviewers", testOpenPRsNeedingReviewers},
+		{"Reviewers", testReviewers},
+		{"ReplaceReviewer", testReplaceReviewer},
+		{"ActiveUsersWithLoad", testActiveUsersWithLoad},
+		{"ReviewerReplacements", testReviewerReplacements},
+		{"Stats", testStats},
+		{"WithTx", testWithTx},
+		{"ConcurrentCreatePullRequest", testConcurrentCreatePullRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newRepo(t))
+		})
+	}
+}
+
+// member участник команды для подготовки данных
+type member struct {
+	userID   string
+	isActive bool
+}
+
+// active и inactive сокращения для описания участников
+func active(userID string) member   { return member{userID: userID, isActive: true} }
+func inactive(userID string) member { return member{userID: userID} }
+
+// seedTeam создает команду с участниками
+func seedTeam(t *testing.T, repo repository.Repository, name string, members ...member) *repository.TeamModel {
+	t.Helper()
+	ctx := context.Background()
+	tm, err := repo.CreateTeam(ctx, name)
+	require.NoError(t, err)
+	for _, m := range members {
+		_, err := repo.CreateUser(ctx, m.userID, "name-"+m.userID, tm.ID, m.isActive)
+		require.NoError(t, err)
+	}
+	return tm
+}
+
+// seedPR создает PR и назначает ревьюверов
+func seedPR(t *testing.T, repo repository.Repository, prID, authorID string, reviewers ...string) {
+	t.Helper()
+	ctx := context.Background()
+	_, err := repo.CreatePullRequest(ctx, prID, "PR "+prID, authorID)
+	require.NoError(t, err)
+	for _, r := range reviewers {
+		require.NoError(t, repo.AssignReviewer(ctx, prID, r, false))
+	}
+}
+
+// reviewersOf возвращает ревьюверов PR
+func reviewersOf(t *testing.T, repo repository.Repository, prID string) []string {
+	t.Helper()
+	reviewers, err := repo.GetReviewersByPRID(context.Background(), prID)
+	require.NoError(t, err)
+	return reviewers
+}
+
+// ptr возвращает указатель на v
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// userIDs собирает user_id пользователей
+func userIDs(users []repository.UserModel) []string {
+	var res []string
+	for _, u := range users {
+		res = append(res, u.UserID)
+	}
+	return res
+}
+
+func testTeams(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	tm, err := repo.CreateTeam(ctx, "backend")
+	require.NoError(t, err)
+	assert.NotZero(t, tm.ID)
+	assert.Equal(t, "backend", tm.TeamName)
+	assert.False(t, tm.CreatedAt.IsZero())
+
+	_, err = repo.CreateTeam(ctx, "backend")
+	assert.ErrorIs(t, err, repository.ErrAlreadyExists)
+
+	byName, err := repo.GetTeamByName(ctx, "backend")
+	require.NoError(t, err)
+	assert.Equal(t, tm.ID, byName.ID)
+
+	byID, err := repo.GetTeamByID(ctx, tm.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "backend", byID.TeamName)
+
+	_, err = repo.GetTeamByName(ctx, "missing")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	_, err = repo.GetTeamByID(ctx, tm.ID+1000)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	exists, err := repo.TeamExists(ctx, "backend")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = repo.TeamExists(ctx, "missing")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func testTeamSettings(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	tm := seedTeam(t, repo, "backend")
+
+	settings, err := repo.GetTeamSettings(ctx, tm.ID)
+	require.NoError(t, err)
+	assert.Nil(t, settings, "settings are nil until set")
+
+	strategy := "round_robin"
+	created, err := repo.UpsertTeamSettings(ctx, repository.TeamSettingsModel{TeamID: tm.ID, ReviewersCount: 3, Strategy: &strategy, MaxOpenReviews: 5})
+	require.NoError(t, err)
+	assert.Equal(t, 3, created.ReviewersCount)
+	require.NotNil(t, created.Strategy)
+	assert.Equal(t, strategy, *created.Strategy)
+	assert.Equal(t, 5, created.MaxOpenReviews)
+
+	updated, err := repo.UpsertTeamSettings(ctx, repository.TeamSettingsModel{TeamID: tm.ID, ReviewersCount: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 1, updated.ReviewersCount)
+	assert.Nil(t, updated.Strategy)
+	assert.Zero(t, updated.MaxOpenReviews)
+
+	got, err := repo.GetTeamSettings(ctx, tm.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, 1, got.ReviewersCount)
+	assert.Nil(t, got.Strategy)
+
+	_, err = repo.UpsertTeamSettings(ctx, repository.TeamSettingsModel{TeamID: tm.ID, ReviewersCount: -1})
+	assert.Error(t, err, "negative reviewers_count violates the check constraint")
+	_, err = repo.UpsertTeamSettings(ctx, repository.TeamSettingsModel{TeamID: tm.ID + 1000, ReviewersCount: 1})
+	assert.Error(t, err, "settings of a missing team")
+}
+
+func testFallbackTeams(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	backend := seedTeam(t, repo, "backend")
+	frontend := seedTeam(t, repo, "frontend")
+	qa := seedTeam(t, repo, "qa")
+
+	fallbacks, err := repo.GetFallbackTeams(ctx, backend.ID)
+	require.NoError(t, err)
+	assert.Empty(t, fallbacks)
+
+	// порядок задает приоритет
+	require.NoError(t, repo.SetFallbackTeams(ctx, backend.ID, []int64{qa.ID, frontend.ID}))
+	fallbacks, err = repo.GetFallbackTeams(ctx, backend.ID)
+	require.NoError(t, err)
+	require.Len(t, fallbacks, 2)
+	assert.Equal(t, "qa", fallbacks[0].TeamName)
+	assert.Equal(t, "frontend", fallbacks[1].TeamName)
+
+	// новый список заменяет старый
+	require.NoError(t, repo.SetFallbackTeams(ctx, backend.ID, []int64{frontend.ID}))
+	fallbacks, err = repo.GetFallbackTeams(ctx, backend.ID)
+	require.NoError(t, err)
+	require.Len(t, fallbacks, 1)
+	assert.Equal(t, frontend.ID, fallbacks[0].ID)
+
+	assert.Error(t, repo.SetFallbackTeams(ctx, backend.ID, []int64{backend.ID}), "team cannot be its own fallback")
+	assert.Error(t, repo.SetFallbackTeams(ctx, backend.ID, []int64{qa.ID + 1000}), "fallback team must exist")
+
+	// неудачная замена не трогает прежний список
+	fallbacks, err = repo.GetFallbackTeams(ctx, backend.ID)
+	require.NoError(t, err)
+	require.Len(t, fallbacks, 1)
+	assert.Equal(t, frontend.ID, fallbacks[0].ID)
+
+	require.NoError(t, repo.SetFallbackTeams(ctx, backend.ID, nil))
+	fallbacks, err = repo.GetFallbackTeams(ctx, backend.ID)
+	require.NoError(t, err)
+	assert.Empty(t, fallbacks)
+}
+
+func testUsers(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	backend := seedTeam(t, repo, "backend")
+	frontend := seedTeam(t, repo, "frontend")
+
+	u, err := repo.CreateUser(ctx, "u1", "Alice", backend.ID, true)
+	require.NoError(t, err)
+	assert.NotZero(t, u.ID)
+	assert.Equal(t, "member", u.Role, "new users are members")
+	assert.True(t, u.IsActive)
+
+	_, err = repo.CreateUser(ctx, "u1", "Alice", backend.ID, true)
+	assert.ErrorIs(t, err, repository.ErrAlreadyExists)
+	_, err = repo.CreateUser(ctx, "u9", "Nobody", backend.ID+1000, true)
+	assert.Error(t, err, "user must reference an existing team")
+
+	_, err = repo.CreateUser(ctx, "u2", "Bob", backend.ID, false)
+	require.NoError(t, err)
+
+	users, err := repo.GetUsersByTeamID(ctx, backend.ID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"u1", "u2"}, userIDs(users))
+	activeUsers, err := repo.GetActiveUsersInTeam(ctx, backend.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u1"}, userIDs(activeUsers))
+
+	moved, err := repo.UpdateUser(ctx, "u1", "Alice Updated", frontend.ID, false)
+	require.NoError(t, err)
+	assert.Equal(t, u.ID, moved.ID)
+	assert.Equal(t, "Alice Updated", moved.Username)
+	assert.Equal(t, frontend.ID, moved.TeamID)
+	assert.False(t, moved.IsActive)
+	_, err = repo.UpdateUser(ctx, "missing", "X", backend.ID, true)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	withTeam, err := repo.GetUserWithTeam(ctx, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, repository.UserWithTeam{UserID: "u1", Username: "Alice Updated", TeamName: "frontend", IsActive: false}, *withTeam)
+	_, err = repo.GetUserWithTeam(ctx, "missing")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	activated, err := repo.SetIsActive(ctx, "u1", true)
+	require.NoError(t, err)
+	assert.True(t, activated.IsActive)
+	_, err = repo.SetIsActive(ctx, "missing", true)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	lead, err := repo.SetUserRole(ctx, "u1", "team_lead")
+	require.NoError(t, err)
+	assert.Equal(t, "team_lead", lead.Role)
+	_, err = repo.SetUserRole(ctx, "u1", "owner")
+	assert.Error(t, err, "role must be admin, team_lead or member")
+	_, err = repo.SetUserRole(ctx, "missing", "admin")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	got, err := repo.GetUserByID(ctx, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, "team_lead", got.Role)
+	assert.True(t, got.IsActive)
+	_, err = repo.GetUserByID(ctx, "missing")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	exists, err := repo.UserExists(ctx, "u2")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = repo.UserExists(ctx, "missing")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func testDeactivateTeamMembers(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	backend := seedTeam(t, repo, "backend", active("u1"), active("u2"), active("u3"), inactive("u4"))
+	seedTeam(t, repo, "frontend", active("u5"))
+
+	// неактивные и чужие пользователи не попадают в результат
+	deactivated, err := repo.DeactivateTeamMembers(ctx, backend.ID, []string{"u1", "u4", "u5"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u1"}, deactivated)
+
+	deactivated, err = repo.DeactivateTeamMembers(ctx, backend.ID, []string{"u2"}, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u3"}, deactivated)
+
+	activeUsers, err := repo.GetActiveUsersInTeam(ctx, backend.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u2"}, userIDs(activeUsers))
+	u5, err := repo.GetUserByID(ctx, "u5")
+	require.NoError(t, err)
+	assert.True(t, u5.IsActive, "other teams are not affected")
+
+	deactivated, err = repo.DeactivateTeamMembers(ctx, backend.ID, nil, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u2"}, deactivated)
+	deactivated, err = repo.DeactivateTeamMembers(ctx, backend.ID, nil, true)
+	require.NoError(t, err)
+	assert.Empty(t, deactivated)
+}
+
+func testPullRequests(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	seedTeam(t, repo, "backend", active("u1"), active("u2"))
+
+	pr, err := repo.CreatePullRequest(ctx, "pr-1", "Add feature", "u1")
+	require.NoError(t, err)
+	assert.NotZero(t, pr.ID)
+	assert.Equal(t, "OPEN", pr.Status)
+	assert.False(t, pr.NeedMoreReviewers)
+	assert.Nil(t, pr.MergedAt)
+	assert.False(t, pr.CreatedAt.IsZero())
+
+	_, err = repo.CreatePullRequest(ctx, "pr-1", "Again", "u2")
+	assert.ErrorIs(t, err, repository.ErrAlreadyExists)
+	_, err = repo.CreatePullRequest(ctx, "pr-x", "Orphan", "missing")
+	assert.Error(t, err, "author must exist")
+
+	got, err := repo.GetPullRequestByID(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Add feature", got.PullRequestName)
+	_, err = repo.GetPullRequestByID(ctx, "missing")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	_, err = repo.LockPullRequest(ctx, "missing")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	require.NoError(t, repo.WithTx(ctx, func(tx repository.Repository) error {
+		locked, err := tx.LockPullRequest(ctx, "pr-1")
+		require.NoError(t, err)
+		assert.Equal(t, "pr-1", locked.PullRequestID)
+		return nil
+	}))
+
+	exists, err := repo.PullRequestExists(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = repo.PullRequestExists(ctx, "missing")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	require.NoError(t, repo.SetNeedMoreReviewers(ctx, "pr-1", true))
+	got, err = repo.GetPullRequestByID(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.True(t, got.NeedMoreReviewers)
+
+	_, err = repo.CreatePullRequest(ctx, "pr-2", "Fix bug", "u1")
+	require.NoError(t, err)
+	_, err = repo.CreatePullRequest(ctx, "pr-3", "Other", "u2")
+	require.NoError(t, err)
+	byAuthor, err := repo.GetPullRequestsByAuthor(ctx, "u1")
+	require.NoError(t, err)
+	var ids []string
+	for _, p := range byAuthor {
+		ids = append(ids, p.PullRequestID)
+	}
+	assert.ElementsMatch(t, []string{"pr-1", "pr-2"}, ids)
+
+	merged, err := repo.MergePullRequest(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, "MERGED", merged.Status)
+	require.NotNil(t, merged.MergedAt)
+	got, err = repo.GetPullRequestByID(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, "MERGED", got.Status)
+	assert.NotNil(t, got.MergedAt)
+	_, err = repo.MergePullRequest(ctx, "missing")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func testOpenPRsNeedingReviewers(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	backend := seedTeam(t, repo, "backend", active("u1"), active("u2"))
+	seedTeam(t, repo, "frontend", active("u3"))
+
+	for _, pr := range []struct{ id, author string }{{"pr-1", "u1"}, {"pr-2", "u2"}, {"pr-3", "u1"}, {"pr-4", "u3"}, {"pr-5", "u1"}} {
+		seedPR(t, repo, pr.id, pr.author)
+	}
+	for _, id := range []string{"pr-1", "pr-2", "pr-4", "pr-5"} {
+		require.NoError(t, repo.SetNeedMoreReviewers(ctx, id, true))
+	}
+	_, err := repo.MergePullRequest(ctx, "pr-5")
+	require.NoError(t, err)
+
+	prs, err := repo.GetOpenPRsNeedingReviewers(ctx, backend.ID)
+	require.NoError(t, err)
+	var ids []string
+	for _, p := range prs {
+		ids = append(ids, p.PullRequestID)
+	}
+	// только OPEN PR авторов команды с флагом, в порядке создания
+	assert.Equal(t, []string{"pr-1", "pr-2"}, ids)
+}
+
+func testReviewers(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	seedTeam(t, repo, "backend", active("u1"), active("u2"), active("u3"), active("u4"))
+	seedPR(t, repo, "pr-1", "u1")
+	seedPR(t, repo, "pr-2", "u1")
+
+	require.NoError(t, repo.AssignReviewer(ctx, "pr-1", "u2", false))
+	require.NoError(t, repo.AssignReviewer(ctx, "pr-1", "u3", true))
+	// повторное назначение ничего не меняет и не пишет историю
+	require.NoError(t, repo.AssignReviewer(ctx, "pr-1", "u2", false))
+	require.NoError(t, repo.AssignReviewer(ctx, "pr-2", "u2", false))
+
+	assert.Error(t, repo.AssignReviewer(ctx, "pr-1", "missing", false), "reviewer must exist")
+	assert.Error(t, repo.AssignReviewer(ctx, "missing", "u2", false), "pull request must exist")
+
+	assert.Equal(t, []string{"u2", "u3"}, reviewersOf(t, repo, "pr-1"))
+	cnt, err := repo.CountReviewersByPRID(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, cnt)
+
+	assignments, err := repo.GetReviewerAssignmentsByPRID(ctx, "pr-1")
+	require.NoError(t, err)
+	require.Len(t, assignments, 2)
+	assert.Equal(t, "u2", assignments[0].ReviewerUserID)
+	assert.False(t, assignments[0].IsFallback)
+	assert.Equal(t, "u3", assignments[1].ReviewerUserID)
+	assert.True(t, assignments[1].IsFallback)
+
+	withReviewers, err := repo.GetPullRequestWithReviewers(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, "pr-1", withReviewers.PullRequest.PullRequestID)
+	assert.Equal(t, []string{"u2", "u3"}, withReviewers.Reviewers)
+	assert.Equal(t, []string{"u3"}, withReviewers.FallbackReviewers)
+	_, err = repo.GetPullRequestWithReviewers(ctx, "missing")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	assigned, err := repo.IsReviewerAssigned(ctx, "pr-1", "u3")
+	require.NoError(t, err)
+	assert.True(t, assigned)
+	assigned, err = repo.IsReviewerAssigned(ctx, "pr-2", "u3")
+	require.NoError(t, err)
+	assert.False(t, assigned)
+
+	prs, err := repo.GetPRsByReviewerID(ctx, "u2")
+	require.NoError(t, err)
+	var ids []string
+	for _, p := range prs {
+		ids = append(ids, p.PullRequestID)
+	}
+	assert.ElementsMatch(t, []string{"pr-1", "pr-2"}, ids)
+	prs, err = repo.GetPRsByReviewerID(ctx, "u4")
+	require.NoError(t, err)
+	assert.Empty(t, prs)
+
+	history, err := repo.GetAssignmentHistory(ctx, "pr-1")
+	require.NoError(t, err)
+	require.Len(t, history, 2, "each new assignment is recorded once")
+	assert.Nil(t, history[0].OldReviewerUserID)
+	assert.Equal(t, "u2", history[0].NewReviewerUserID)
+	assert.Equal(t, "u3", history[1].NewReviewerUserID)
+
+	require.NoError(t, repo.RemoveReviewer(ctx, "pr-1", "u2"))
+	require.NoError(t, repo.RemoveReviewer(ctx, "pr-1", "u4"), "removing an unassigned reviewer is a no-op")
+	assert.Equal(t, []string{"u3"}, reviewersOf(t, repo, "pr-1"))
+}
+
+func testReplaceReviewer(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	seedTeam(t, repo, "backend", active("u1"), active("u2"), active("u3"), active("u4"))
+	seedPR(t, repo, "pr-1", "u1", "u2", "u3")
+
+	reason := "vacation"
+	require.NoError(t, repo.ReplaceReviewer(ctx, "pr-1", "u2", "u4", true, &reason))
+	assert.ElementsMatch(t, []string{"u3", "u4"}, reviewersOf(t, repo, "pr-1"))
+
+	withReviewers, err := repo.GetPullRequestWithReviewers(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u4"}, withReviewers.FallbackReviewers)
+
+	history, err := repo.GetAssignmentHistory(ctx, "pr-1")
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	last := history[2]
+	require.NotNil(t, last.OldReviewerUserID)
+	assert.Equal(t, "u2", *last.OldReviewerUserID)
+	assert.Equal(t, "u4", last.NewReviewerUserID)
+	require.NotNil(t, last.Reason)
+	assert.Equal(t, reason, *last.Reason)
+
+	// замена на несуществующего пользователя откатывается целиком
+	assert.Error(t, repo.ReplaceReviewer(ctx, "pr-1", "u3", "missing", false, nil))
+	assert.ElementsMatch(t, []string{"u3", "u4"}, reviewersOf(t, repo, "pr-1"))
+	history, err = repo.GetAssignmentHistory(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Len(t, history, 3)
+
+	// замена на уже назначенного ревьювера не происходит и не попадает в историю
+	err = repo.ReplaceReviewer(ctx, "pr-1", "u3", "u4", false, nil)
+	assert.ErrorIs(t, err, repository.ErrAlreadyExists)
+	assert.ElementsMatch(t, []string{"u3", "u4"}, reviewersOf(t, repo, "pr-1"))
+	history, err = repo.GetAssignmentHistory(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Len(t, history, 3)
+}
+
+func testActiveUsersWithLoad(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	backend := seedTeam(t, repo, "backend", active("u1"), active("u2"), active("u3"), inactive("u4"))
+	seedPR(t, repo, "pr-1", "u1", "u2", "u3")
+	seedPR(t, repo, "pr-2", "u1", "u2")
+	seedPR(t, repo, "pr-3", "u1", "u3", "u4")
+	// ревью на замерженных PR не считаются
+	_, err := repo.MergePullRequest(ctx, "pr-3")
+	require.NoError(t, err)
+
+	candidates, err := repo.GetActiveUsersWithLoad(ctx, backend.ID)
+	require.NoError(t, err)
+	load := map[string]int{}
+	for _, c := range candidates {
+		assert.True(t, c.IsActive)
+		assert.Equal(t, backend.ID, c.TeamID)
+		load[c.UserID] = c.OpenReviews
+	}
+	assert.Equal(t, map[string]int{"u1": 0, "u2": 2, "u3": 1}, load)
+}
+
+func testReviewerReplacements(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	backend := seedTeam(t, repo, "backend", active("u1"), active("u2"), active("u3"), active("u4"))
+	seedTeam(t, repo, "frontend", active("f1"))
+
+	seedPR(t, repo, "pr-1", "u1", "u3", "u2")
+	seedPR(t, repo, "pr-2", "u4", "u2")
+	seedPR(t, repo, "pr-3", "u1", "u2")
+	seedPR(t, repo, "pr-4", "u1", "u4")
+	_, err := repo.MergePullRequest(ctx, "pr-3")
+	require.NoError(t, err)
+
+	none, err := repo.LockOpenReviews(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, none)
+
+	deactivated, err := repo.DeactivateTeamMembers(ctx, backend.ID, []string{"u2", "u3"}, false)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"u2", "u3"}, deactivated)
+
+	// замерженный pr-3 и pr-4 без заменяемых ревьюверов не затрагиваются, ревьюверы упорядочены
+	reviews, err := repo.LockOpenReviews(ctx, deactivated)
+	require.NoError(t, err)
+	assert.Equal(t, []repository.OpenReview{
+		{PullRequestID: "pr-1", AuthorID: "u1", Reviewers: []string{"u2", "u3"}},
+		{PullRequestID: "pr-2", AuthorID: "u4", Reviewers: []string{"u2"}},
+	}, reviews)
+
+	reason := "offboarding"
+	plan := []repository.ReviewerReplacement{
+		{PullRequestID: "pr-1", OldReviewerUserID: "u2", NewReviewerUserID: ptr("u4")},
+		{PullRequestID: "pr-1", OldReviewerUserID: "u3", NewReviewerUserID: ptr("f1"), IsFallback: true},
+		{PullRequestID: "pr-2", OldReviewerUserID: "u2", NewReviewerUserID: ptr("u1")},
+	}
+	require.NoError(t, repo.ApplyReviewerReplacements(ctx, plan, &reason))
+	assert.ElementsMatch(t, []string{"u4", "f1"}, reviewersOf(t, repo, "pr-1"))
+	assert.Equal(t, []string{"u1"}, reviewersOf(t, repo, "pr-2"))
+	assert.Equal(t, []string{"u2"}, reviewersOf(t, repo, "pr-3"))
+
+	withReviewers, err := repo.GetPullRequestWithReviewers(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"f1"}, withReviewers.FallbackReviewers)
+
+	history, err := repo.GetAssignmentHistory(ctx, "pr-2")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.NotNil(t, history[1].OldReviewerUserID)
+	assert.Equal(t, "u2", *history[1].OldReviewerUserID)
+	assert.Equal(t, "u1", history[1].NewReviewerUserID)
+	require.NotNil(t, history[1].Reason)
+	assert.Equal(t, reason, *history[1].Reason)
+
+	// замена без нового ревьювера пропускается
+	require.NoError(t, repo.ApplyReviewerReplacements(ctx, []repository.ReviewerReplacement{
+		{PullRequestID: "pr-2", OldReviewerUserID: "u1"},
+	}, nil))
+	assert.Equal(t, []string{"u1"}, reviewersOf(t, repo, "pr-2"))
+}
+
+func testStats(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+
+	reviewerStats, err := repo.GetReviewerStats(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, reviewerStats)
+
+	seedTeam(t, repo, "backend", active("u1"), active("u2"), active("u3"), active("u4"))
+	seedPR(t, repo, "pr-1", "u1", "u2", "u3")
+	seedPR(t, repo, "pr-2", "u1", "u2")
+	seedPR(t, repo, "pr-3", "u4")
+	_, err = repo.MergePullRequest(ctx, "pr-2")
+	require.NoError(t, err)
+
+	reviewerStats, err = repo.GetReviewerStats(ctx)
+	require.NoError(t, err)
+	require.Len(t, reviewerStats, 4, "users without reviews are included")
+	assert.Equal(t, repository.ReviewerStatRow{UserID: "u2", Username: "name-u2", AssignedCount: 2}, reviewerStats[0])
+	assert.Equal(t, repository.ReviewerStatRow{UserID: "u3", Username: "name-u3", AssignedCount: 1}, reviewerStats[1])
+	assert.Zero(t, reviewerStats[2].AssignedCount)
+	assert.Zero(t, reviewerStats[3].AssignedCount)
+
+	prStats, err := repo.GetPRStats(ctx)
+	require.NoError(t, err)
+	require.Len(t, prStats, 3)
+	assert.Equal(t, repository.PRStatRow{PullRequestID: "pr-1", PullRequestName: "PR pr-1", AuthorID: "u1", Status: "OPEN", ReviewerCount: 2}, prStats[0])
+	assert.Equal(t, repository.PRStatRow{PullRequestID: "pr-2", PullRequestName: "PR pr-2", AuthorID: "u1", Status: "MERGED", ReviewerCount: 1}, prStats[1])
+	assert.Equal(t, repository.PRStatRow{PullRequestID: "pr-3", PullRequestName: "PR pr-3", AuthorID: "u4", Status: "OPEN", ReviewerCount: 0}, prStats[2])
+}
+
+func testWithTx(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	errAbort := errors.New("abort")
+
+	t.Run("Commit", func(t *testing.T) {
+		err := repo.WithTx(ctx, func(tx repository.Repository) error {
+			seedTeam(t, tx, "committed", active("c1"))
+			seedPR(t, tx, "pr-c", "c1")
+			return nil
+		})
+		require.NoError(t, err)
+		exists, err := repo.PullRequestExists(ctx, "pr-c")
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		tm := seedTeam(t, repo, "rollback", active("r1"), active("r2"))
+		seedPR(t, repo, "pr-r", "r1")
+
+		err := repo.WithTx(ctx, func(tx repository.Repository) error {
+			_, err := tx.CreateTeam(ctx, "rolled-back")
+			require.NoError(t, err)
+			require.NoError(t, tx.AssignReviewer(ctx, "pr-r", "r2", false))
+			_, err = tx.SetUserRole(ctx, "r1", "admin")
+			require.NoError(t, err)
+			_, err = tx.UpsertTeamSettings(ctx, repository.TeamSettingsModel{TeamID: tm.ID, ReviewersCount: 4})
+			require.NoError(t, err)
+			_, err = tx.MergePullRequest(ctx, "pr-r")
+			require.NoError(t, err)
+			return errAbort
+		})
+		require.ErrorIs(t, err, errAbort)
+
+		exists, err := repo.TeamExists(ctx, "rolled-back")
+		require.NoError(t, err)
+		assert.False(t, exists)
+		assert.Empty(t, reviewersOf(t, repo, "pr-r"))
+		history, err := repo.GetAssignmentHistory(ctx, "pr-r")
+		require.NoError(t, err)
+		assert.Empty(t, history)
+		u, err := repo.GetUserByID(ctx, "r1")
+		require.NoError(t, err)
+		assert.Equal(t, "member", u.Role)
+		settings, err := repo.GetTeamSettings(ctx, tm.ID)
+		require.NoError(t, err)
+		assert.Nil(t, settings)
+		pr, err := repo.GetPullRequestByID(ctx, "pr-r")
+		require.NoError(t, err)
+		assert.Equal(t, "OPEN", pr.Status)
+		assert.Nil(t, pr.MergedAt)
+	})
+
+	t.Run("NestedRollback", func(t *testing.T) {
+		err := repo.WithTx(ctx, func(tx repository.Repository) error {
+			_, err := tx.CreateTeam(ctx, "outer")
+			require.NoError(t, err)
+			inner := tx.WithTx(ctx, func(tx repository.Repository) error {
+				_, err := tx.CreateTeam(ctx, "inner")
+				require.NoError(t, err)
+				return errAbort
+			})
+			require.ErrorIs(t, inner, errAbort)
+			// внешняя транзакция видит откат вложенной и продолжается
+			exists, err := tx.TeamExists(ctx, "inner")
+			require.NoError(t, err)
+			assert.False(t, exists)
+			return nil
+		})
+		require.NoError(t, err)
+
+		exists, err := repo.TeamExists(ctx, "outer")
+		require.NoError(t, err)
+		assert.True(t, exists)
+		exists, err = repo.TeamExists(ctx, "inner")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("PanicRollsBack", func(t *testing.T) {
+		assert.Panics(t, func() {
+			_ = repo.WithTx(ctx, func(tx repository.Repository) error {
+				_, err := tx.CreateTeam(ctx, "panicked")
+				require.NoError(t, err)
+				panic("boom")
+			})
+		})
+		exists, err := repo.TeamExists(ctx, "panicked")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+}
+
+func testConcurrentCreatePullRequest(t *testing.T, repo repository.Repository) {
+	ctx := context.Background()
+	seedTeam(t, repo, "backend", active("u1"))
+
+	const workers = 20
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = repo.WithTx(ctx, func(tx repository.Repository) error {
+				_, err := tx.CreatePullRequest(ctx, "pr-1", fmt.Sprintf("attempt %d", i), "u1")
+				return err
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	created := 0
+	for _, err := range errs {
+		if err == nil {
+			created++
+			continue
+		}
+		assert.ErrorIs(t, err, repository.ErrAlreadyExists)
+	}
+	assert.Equal(t, 1, created, "exactly one create must succeed")
+}
@@ -0,0 +1,718 @@
+package sqlite
+
+import (
+	"context"
+
+	"avito-test-quest/internal/logger"
+	"avito-test-quest/internal/repository"
+
+	sq "github.com/Masterminds/squirrel"
+	"go.uber.org/zap"
+)
+
+// колонки моделей в порядке полей scan-функций
+var (
+	teamColumns     = []string{"id", "team_name", "created_at", "updated_at"}
+	settingsColumns = []string{"team_id", "reviewers_count", "strategy", "max_open_reviews", "created_at", "updated_at"}
+	userColumns     = []string{"id", "user_id", "username", "team_id", "is_active", "role", "created_at", "updated_at"}
+	prColumns       = []string{"id", "pull_request_id", "pull_request_name", "author_id", "status", "need_more_reviewers", "created_at", "merged_at", "updated_at"}
+)
+
+const (
+	returningUser = "RETURNING id, user_id, username, team_id, is_active, role, created_at, updated_at"
+	returningPR   = "RETURNING id, pull_request_id, pull_request_name, author_id, status, need_more_reviewers, created_at, merged_at, updated_at"
+)
+
+// ==================== Team Repository Methods ====================
+
+// CreateTeam создает новую команду
+func (r *Repository) CreateTeam(ctx context.Context, teamName string) (*repository.TeamModel, error) {
+	sql, args, err := r.psql.Insert("teams").Columns("team_name").Values(teamName).
+		Suffix("RETURNING id, team_name, created_at, updated_at").ToSql()
+	if err != nil {
+		logger.GetOrCreateLoggerFromCtx(ctx).Error(ctx, "failed to build sql for CreateTeam", zap.Error(err))
+		return nil, err
+	}
+	tm, err := scanTeam(r.q.QueryRowContext(ctx, sql, args...))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, repository.ErrAlreadyExists
+		}
+		logger.GetOrCreateLoggerFromCtx(ctx).Error(ctx, "failed to scan CreateTeam", zap.Error(err))
+		return nil, err
+	}
+	return &tm, nil
+}
+
+// GetTeamByName получает команду по имени
+func (r *Repository) GetTeamByName(ctx context.Context, teamName string) (*repository.TeamModel, error) {
+	return r.getTeam(ctx, sq.Eq{"team_name": teamName})
+}
+
+// GetTeamByID получает команду по ID
+func (r *Repository) GetTeamByID(ctx context.Context, teamID int64) (*repository.TeamModel, error) {
+	return r.getTeam(ctx, sq.Eq{"id": teamID})
+}
+
+// getTeam получает команду по условию
+func (r *Repository) getTeam(ctx context.Context, where sq.Eq) (*repository.TeamModel, error) {
+	sql, args, err := r.psql.Select(teamColumns...).From("teams").Where(where).ToSql()
+	if err != nil {
+		logger.GetOrCreateLoggerFromCtx(ctx).Error(ctx, "failed to build sql for getTeam", zap.Error(err))
+		return nil, err
+	}
+	tm, err := scanTeam(r.q.QueryRowContext(ctx, sql, args...))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &tm, nil
+}
+
+// TeamExists проверяет существование команды по имени
+func (r *Repository) TeamExists(ctx context.Context, teamName string) (bool, error) {
+	return r.exists(ctx, "teams", sq.Eq{"team_name": teamName})
+}
+
+// ==================== Team Settings Repository Methods ====================
+
+// GetTeamSettings получает настройки команды, nil если они не задавались
+func (r *Repository) GetTeamSettings(ctx context.Context, teamID int64) (*repository.TeamSettingsModel, error) {
+	sql, args, err := r.psql.Select(settingsColumns...).From("team_settings").Where(sq.Eq{"team_id": teamID}).ToSql()
+	if err != nil {
+		logger.GetOrCreateLoggerFromCtx(ctx).Error(ctx, "failed to build sql for GetTeamSettings", zap.Error(err))
+		return nil, err
+	}
+	ts, err := scanSettings(r.q.QueryRowContext(ctx, sql, args...))
+	if err != nil {
+		if isNoRows(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &ts, nil
+}
+
+// UpsertTeamSettings создает или обновляет настройки команды
+func (r *Repository) UpsertTeamSettings(ctx context.Context, settings repository.TeamSettingsModel) (*repository.TeamSettingsModel, error) {
+	sql, args, err := r.psql.Insert("team_settings").Columns("team_id", "reviewers_count", "strategy", "max_open_reviews").
+		Values(settings.TeamID, settings.ReviewersCount, settings.Strategy, settings.MaxOpenReviews).
+		Suffix("ON CONFLICT (team_id) DO UPDATE SET reviewers_count = excluded.reviewers_count, strategy = excluded.strategy, max_open_reviews = excluded.max_open_reviews, updated_at = " + nowSQL).
+		Suffix("RETURNING team_id, reviewers_count, strategy, max_open_reviews, created_at, updated_at").ToSql()
+	if err != nil {
+		logger.GetOrCreateLoggerFromCtx(ctx).Error(ctx, "failed to build sql for UpsertTeamSettings", zap.Error(err))
+		return nil, err
+	}
+	ts, err := scanSettings(r.q.QueryRowContext(ctx, sql, args...))
+	if err != nil {
+		return nil, err
+	}
+	return &ts, nil
+}
+
+// GetFallbackTeams получает резервные команды в порядке приоритета
+func (r *Repository) GetFallbackTeams(ctx context.Context, teamID int64) ([]repository.TeamModel, error) {
+	sql, args, err := r.psql.Select("t.id", "t.team_name", "t.created_at", "t.updated_at").
+		From("team_fallbacks f").
+		Join("teams t ON t.id = f.fallback_team_id").
+		Where(sq.Eq{"f.team_id": teamID}).
+		OrderBy("f.position").
+		ToSql()
+	if err != nil {
+		logger.GetOrCreateLoggerFromCtx(ctx).Error(ctx, "failed to build sql for GetFallbackTeams", zap.Error(err))
+		return nil, err
+	}
+	rows, err := r.q.QueryContext(ctx, sql, args...)
+	return collect(rows, err, scanTeam)
+}
+
+// SetFallbackTeams заменяет список резервных команд
+func (r *Repository) SetFallbackTeams(ctx context.Context, teamID int64, fallbackTeamIDs []int64) error {
+	// удаление старого списка и вставка нового атомарны
+	return r.inTx(ctx, func(tx *Repository) error {
+		sql, args, err := tx.psql.Delete("team_fallbacks").Where(sq.Eq{"team_id": teamID}).ToSql()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.q.ExecContext(ctx, sql, args...); err != nil {
+			return err
+		}
+		if len(fallbackTeamIDs) == 0 {
+			return nil
+		}
+		ib := tx.psql.Insert("team_fallbacks").Columns("team_id", "fallback_team_id", "position")
+		for i, id := range fallbackTeamIDs {
+			ib = ib.Values(teamID, id, i)
+		}
+		sql, args, err = ib.ToSql()
+		if err != nil {
+			return err
+		}
+		_, err = tx.q.ExecContext(ctx, sql, args...)
+
+		return err
+	})
+}
+
+// ==================== User Repository Methods ====================
+
+// CreateUser создает нового пользователя
+func (r *Repository) CreateUser(ctx context.Context, userID, username string, teamID int64, isActive bool) (*repository.UserModel, error) {
+	sql, args, err := r.psql.Insert("users").Columns("user_id", "username", "team_id", "is_active").Values(userID, username, teamID, isActive).
+		Suffix(returningUser).ToSql()
+	if err != nil {
+		return nil, err
+	}
+	u, err := scanUser(r.q.QueryRowContext(ctx, sql, args...))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, repository.ErrAlreadyExists
+		}
+		return nil, err
+	}
+	return &u, nil
+}
+
+// UpdateUser обновляет данные пользователя
+func (r *Repository) UpdateUser(ctx context.Context, userID, username string, teamID int64, isActive bool) (*repository.UserModel, error) {
+	return r.updateUser(ctx, userID, sq.Eq{"username": username, "team_id": teamID, "is_active": isActive})
+}
+
+// GetUserByID получает пользователя по userID
+func (r *Repository) GetUserByID(ctx context.Context, userID string) (*repository.UserModel, error) {
+	sql, args, err := r.psql.Select(userColumns...).From("users").Where(sq.Eq{"user_id": userID}).ToSql()
+	if err != nil {
+		return nil, err
+	}
+	u, err := scanUser(r.q.QueryRowContext(ctx, sql, args...))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &u, nil
+}
+
+// GetUserWithTeam получает пользователя с названием команды
+func (r *Repository) GetUserWithTeam(ctx context.Context, userID string) (*repository.UserWithTeam, error) {
+	sql, args, err := r.psql.Select("u.user_id", "u.username", "t.team_name", "u.is_active").From("users u").Join("teams t ON u.team_id = t.id").Where(sq.Eq{"u.user_id": userID}).ToSql()
+	if err != nil {
+		return nil, err
+	}
+	var uwt repository.UserWithTeam
+	row := r.q.QueryRowContext(ctx, sql, args...)
+	if err := row.Scan(&uwt.UserID, &uwt.Username, &uwt.TeamName, &uwt.IsActive); err != nil {
+		return nil, notFound(err)
+	}
+	return &uwt, nil
+}
+
+// GetUsersByTeamID получает всех пользователей команды
+func (r *Repository) GetUsersByTeamID(ctx context.Context, teamID int64) ([]repository.UserModel, error) {
+	return r.listUsers(ctx, sq.Eq{"team_id": teamID})
+}
+
+// SetIsActive обновляет флаг активности пользователя
+func (r *Repository) SetIsActive(ctx context.Context, userID string, isActive bool) (*repository.UserModel, error) {
+	return r.updateUser(ctx, userID, sq.Eq{"is_active": isActive})
+}
+
+// SetUserRole обновляет роль пользователя
+func (r *Repository) SetUserRole(ctx context.Context, userID, role string) (*repository.UserModel, error) {
+	return r.updateUser(ctx, userID, sq.Eq{"role": role})
+}
+
+// DeactivateTeamMembers деактивирует участников команды одним запросом
+func (r *Repository) DeactivateTeamMembers(ctx context.Context, teamID int64, userIDs []string, allExcept bool) ([]string, error) {
+	ub := r.psql.Update("users").Set("is_active", false).Set("updated_at", sq.Expr(nowSQL)).Where(sq.Eq{"team_id": teamID, "is_active": true})
+	if allExcept {
+		ub = ub.Where(sq.NotEq{"user_id": userIDs})
+	} else {
+		ub = ub.Where(sq.Eq{"user_id": userIDs})
+	}
+	sql, args, err := ub.Suffix("RETURNING user_id").ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.QueryContext(ctx, sql, args...)
+	return collect(rows, err, scanString)
+}
+
+// UserExists проверяет существование пользователя
+func (r *Repository) UserExists(ctx context.Context, userID string) (bool, error) {
+	return r.exists(ctx, "users", sq.Eq{"user_id": userID})
+}
+
+// GetActiveUsersInTeam получает активных пользователей команды
+func (r *Repository) GetActiveUsersInTeam(ctx context.Context, teamID int64) ([]repository.UserModel, error) {
+	return r.listUsers(ctx, sq.Eq{"team_id": teamID, "is_active": true})
+}
+
+// GetActiveUsersWithLoad получает активных пользователей команды с количеством открытых ревью
+func (r *Repository) GetActiveUsersWithLoad(ctx context.Context, teamID int64) ([]repository.ReviewerCandidate, error) {
+	sql, args, err := r.psql.Select("u.id", "u.user_id", "u.username", "u.team_id", "u.is_active", "u.created_at", "u.updated_at", "COUNT(p.id) AS open_reviews").
+		From("users u").
+		LeftJoin("pr_reviewers r ON r.reviewer_user_id = u.user_id").
+		LeftJoin("pull_requests p ON p.pull_request_id = r.pull_request_id AND p.status = 'OPEN'").
+		Where(sq.Eq{"u.team_id": teamID, "u.is_active": true}).
+		GroupBy("u.id").
+		ToSql()
+	if err != nil {
+		logger.GetOrCreateLoggerFromCtx(ctx).Error(ctx, "failed to build sql for GetActiveUsersWithLoad", zap.Error(err))
+		return nil, err
+	}
+	rows, err := r.q.QueryContext(ctx, sql, args...)
+	return collect(rows, err, func(s scanner) (repository.ReviewerCandidate, error) {
+		var c repository.ReviewerCandidate
+		err := s.Scan(&c.ID, &c.UserID, &c.Username, &c.TeamID, &c.IsActive, &c.CreatedAt, &c.UpdatedAt, &c.OpenReviews)
+		return c, err
+	})
+}
+
+// updateUser обновляет колонки set пользователя и возвращает его
+func (r *Repository) updateUser(ctx context.Context, userID string, set sq.Eq) (*repository.UserModel, error) {
+	sql, args, err := r.psql.Update("users").SetMap(set).Set("updated_at", sq.Expr(nowSQL)).
+		Where(sq.Eq{"user_id": userID}).Suffix(returningUser).ToSql()
+	if err != nil {
+		return nil, err
+	}
+	u, err := scanUser(r.q.QueryRowContext(ctx, sql, args...))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &u, nil
+}
+
+// listUsers получает пользователей по условию
+func (r *Repository) listUsers(ctx context.Context, where sq.Eq) ([]repository.UserModel, error) {
+	sql, args, err := r.psql.Select(userColumns...).From("users").Where(where).ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.QueryContext(ctx, sql, args...)
+	return collect(rows, err, scanUser)
+}
+
+// ==================== Pull Request Repository Methods ====================
+
+// CreatePullRequest создает новый Pull Request
+// Занятый pull_request_id возвращает ErrAlreadyExists
+func (r *Repository) CreatePullRequest(ctx context.Context, prID, prName, authorID string) (*repository.PullRequestModel, error) {
+	sql, args, err := r.psql.Insert("pull_requests").Columns("pull_request_id", "pull_request_name", "author_id").Values(prID, prName, authorID).
+		Suffix("ON CONFLICT (pull_request_id) DO NOTHING " + returningPR).ToSql()
+	if err != nil {
+		return nil, err
+	}
+	pr, err := scanPR(r.q.QueryRowContext(ctx, sql, args...))
+	if err != nil {
+		if isNoRows(err) {
+			return nil, repository.ErrAlreadyExists
+		}
+		return nil, err
+	}
+
+	return &pr, nil
+}
+
+// GetPullRequestByID получает Pull Request по его ID
+func (r *Repository) GetPullRequestByID(ctx context.Context, prID string) (*repository.PullRequestModel, error) {
+	sql, args, err := r.psql.Select(prColumns...).From("pull_requests").Where(sq.Eq{"pull_request_id": prID}).ToSql()
+	if err != nil {
+		return nil, err
+	}
+	pr, err := scanPR(r.q.QueryRowContext(ctx, sql, args...))
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	return &pr, nil
+}
+
+// LockPullRequest получает Pull Request по его ID
+// Отдельная блокировка строки не нужна: транзакции SQLite начинаются с блокировки записи в БД
+func (r *Repository) LockPullRequest(ctx context.Context, prID string) (*repository.PullRequestModel, error) {
+	return r.GetPullRequestByID(ctx, prID)
+}
+
+// GetPullRequestWithReviewers получает Pull Request вместе с назначенными ревьюверами
+func (r *Repository) GetPullRequestWithReviewers(ctx context.Context, prID string) (*repository.PRWithReviewers, error) {
+	pr, err := r.GetPullRequestByID(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := r.GetReviewerAssignmentsByPRID(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+	res := &repository.PRWithReviewers{PullRequest: pr}
+	for _, a := range assignments {
+		res.Reviewers = append(res.Reviewers, a.ReviewerUserID)
+		if a.IsFallback {
+			res.FallbackReviewers = append(res.FallbackReviewers, a.ReviewerUserID)
+		}
+	}
+
+	return res, nil
+}
+
+// MergePullRequest помечает Pull Request как замерженный
+func (r *Repository) MergePullRequest(ctx context.Context, prID string) (*repository.PullRequestModel, error) {
+	sql, args, err := r.psql.Update("pull_requests").Set("status", "MERGED").Set("merged_at", sq.Expr(nowSQL)).Set("updated_at", sq.Expr(nowSQL)).
+		Where(sq.Eq{"pull_request_id": prID}).Suffix(returningPR).ToSql()
+	if err != nil {
+		return nil, err
+	}
+	pr, err := scanPR(r.q.QueryRowContext(ctx, sql, args...))
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	return &pr, nil
+}
+
+// PullRequestExists проверяет существование Pull Request по его ID
+func (r *Repository) PullRequestExists(ctx context.Context, prID string) (bool, error) {
+	return r.exists(ctx, "pull_requests", sq.Eq{"pull_request_id": prID})
+}
+
+// GetPullRequestsByAuthor получает все Pull Request, созданные автором
+func (r *Repository) GetPullRequestsByAuthor(ctx context.Context, authorID string) ([]repository.PullRequestModel, error) {
+	sql, args, err := r.psql.Select(prColumns...).From("pull_requests").Where(sq.Eq{"author_id": authorID}).ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.QueryContext(ctx, sql, args...)
+	return collect(rows, err, scanPR)
+}
+
+// SetNeedMoreReviewers обновляет флаг нехватки ревьюверов на Pull Request
+func (r *Repository) SetNeedMoreReviewers(ctx context.Context, prID string, needMore bool) error {
+	sql, args, err := r.psql.Update("pull_requests").Set("need_more_reviewers", needMore).Set("updated_at", sq.Expr(nowSQL)).Where(sq.Eq{"pull_request_id": prID}).ToSql()
+	if err != nil {
+		return err
+	}
+	_, err = r.q.ExecContext(ctx, sql, args...)
+
+	return err
+}
+
+// GetOpenPRsNeedingReviewers получает OPEN Pull Request авторов команды с флагом нехватки ревьюверов
+func (r *Repository) GetOpenPRsNeedingReviewers(ctx context.Context, teamID int64) ([]repository.PullRequestModel, error) {
+	sql, args, err := r.psql.Select("p.id", "p.pull_request_id", "p.pull_request_name", "p.author_id", "p.status", "p.need_more_reviewers", "p.created_at", "p.merged_at", "p.updated_at").
+		From("pull_requests p").
+		Join("users u ON u.user_id = p.author_id").
+		Where(sq.Eq{"u.team_id": teamID, "p.status": "OPEN", "p.need_more_reviewers": true}).
+		OrderBy("p.created_at", "p.id").ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.QueryContext(ctx, sql, args...)
+	return collect(rows, err, scanPR)
+}
+
+// ==================== PR Reviewer Repository Methods ====================
+
+// AssignReviewer назначает ревьювера на Pull Request и записывает назначение в историю
+func (r *Repository) AssignReviewer(ctx context.Context, prID, reviewerUserID string, isFallback bool) error {
+	return r.inTx(ctx, func(tx *Repository) error {
+		inserted, err := tx.insertReviewer(ctx, prID, reviewerUserID, isFallback)
+		if err != nil || !inserted {
+			return err
+		}
+
+		return tx.insertAssignmentHistory(ctx, prID, nil, reviewerUserID, nil)
+	})
+}
+
+// RemoveReviewer удаляет ревьювера с Pull Request
+func (r *Repository) RemoveReviewer(ctx context.Context, prID, reviewerUserID string) error {
+	sql, args, err := r.psql.Delete("pr_reviewers").Where(sq.Eq{"pull_request_id": prID, "reviewer_user_id": reviewerUserID}).ToSql()
+	if err != nil {
+		return err
+	}
+	_, err = r.q.ExecContext(ctx, sql, args...)
+
+	return err
+}
+
+// GetReviewersByPRID получает всех ревьюверов Pull Request по его ID
+func (r *Repository) GetReviewersByPRID(ctx context.Context, prID string) ([]string, error) {
+	// время назначения хранится с точностью до миллисекунд, порядок внутри одной миллисекунды задает id
+	sql, args, err := r.psql.Select("reviewer_user_id").From("pr_reviewers").Where(sq.Eq{"pull_request_id": prID}).OrderBy("assigned_at", "id").ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.QueryContext(ctx, sql, args...)
+	return collect(rows, err, scanString)
+}
+
+// GetReviewerAssignmentsByPRID получает назначения ревьюверов Pull Request по его ID
+func (r *Repository) GetReviewerAssignmentsByPRID(ctx context.Context, prID string) ([]repository.PRReviewerModel, error) {
+	sql, args, err := r.psql.Select("id", "pull_request_id", "reviewer_user_id", "is_fallback", "assigned_at").From("pr_reviewers").Where(sq.Eq{"pull_request_id": prID}).OrderBy("assigned_at", "id").ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.QueryContext(ctx, sql, args...)
+	return collect(rows, err, func(s scanner) (repository.PRReviewerModel, error) {
+		var a repository.PRReviewerModel
+		err := s.Scan(&a.ID, &a.PullRequestID, &a.ReviewerUserID, &a.IsFallback, &a.AssignedAt)
+		return a, err
+	})
+}
+
+// GetPRsByReviewerID получает все Pull Request, где пользователь назначен ревьювером
+func (r *Repository) GetPRsByReviewerID(ctx context.Context, reviewerUserID string) ([]repository.PullRequestModel, error) {
+	sql, args, err := r.psql.Select("p.id", "p.pull_request_id", "p.pull_request_name", "p.author_id", "p.status", "p.need_more_reviewers", "p.created_at", "p.merged_at", "p.updated_at").
+		From("pull_requests p").Join("pr_reviewers r ON p.pull_request_id = r.pull_request_id").Where(sq.Eq{"r.reviewer_user_id": reviewerUserID}).ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.QueryContext(ctx, sql, args...)
+	return collect(rows, err, scanPR)
+}
+
+// IsReviewerAssigned проверяет, назначен ли ревьювер на Pull Request
+func (r *Repository) IsReviewerAssigned(ctx context.Context, prID, reviewerUserID string) (bool, error) {
+	return r.exists(ctx, "pr_reviewers", sq.Eq{"pull_request_id": prID, "reviewer_user_id": reviewerUserID})
+}
+
+// ReplaceReviewer заменяет одного ревьювера на другого для заданного Pull Request
+func (r *Repository) ReplaceReviewer(ctx context.Context, prID, oldReviewerID, newReviewerID string, isFallback bool, reason *string) error {
+	// удаление старого, назначение нового и запись в историю атомарны
+	return r.inTx(ctx, func(tx *Repository) error {
+		if err := tx.RemoveReviewer(ctx, prID, oldReviewerID); err != nil {
+			return err
+		}
+		inserted, err := tx.insertReviewer(ctx, prID, newReviewerID, isFallback)
+		if err != nil {
+			return err
+		}
+		if !inserted {
+			return repository.ErrAlreadyExists
+		}
+
+		return tx.insertAssignmentHistory(ctx, prID, &oldReviewerID, newReviewerID, reason)
+	})
+}
+
+// GetAssignmentHistory получает историю назначений ревьюверов Pull Request в хронологическом порядке
+func (r *Repository) GetAssignmentHistory(ctx context.Context, prID string) ([]repository.ReviewerAssignmentHistoryModel, error) {
+	sql, args, err := r.psql.Select("id", "pull_request_id", "old_reviewer_user_id", "new_reviewer_user_id", "reassigned_at", "reason").From("reviewer_assignment_history").Where(sq.Eq{"pull_request_id": prID}).OrderBy("reassigned_at", "id").ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.QueryContext(ctx, sql, args...)
+	return collect(rows, err, func(s scanner) (repository.ReviewerAssignmentHistoryModel, error) {
+		var h repository.ReviewerAssignmentHistoryModel
+		err := s.Scan(&h.ID, &h.PullRequestID, &h.OldReviewerUserID, &h.NewReviewerUserID, &h.ReassignedAt, &h.Reason)
+		return h, err
+	})
+}
+
+// insertReviewer добавляет ревьювера на Pull Request без записи в историю
+// Возвращает false, если ревьювер уже был назначен
+func (r *Repository) insertReviewer(ctx context.Context, prID, reviewerUserID string, isFallback bool) (bool, error) {
+	sql, args, err := r.psql.Insert("pr_reviewers").Columns("pull_request_id", "reviewer_user_id", "is_fallback").Values(prID, reviewerUserID, isFallback).Suffix("ON CONFLICT DO NOTHING").ToSql()
+	if err != nil {
+		return false, err
+	}
+	res, err := r.q.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+
+	return n > 0, err
+}
+
+// insertAssignmentHistory записывает назначение ревьювера в историю
+// oldReviewerID пустой для первичного назначения
+func (r *Repository) insertAssignmentHistory(ctx context.Context, prID string, oldReviewerID *string, newReviewerID string, reason *string) error {
+	sql, args, err := r.psql.Insert("reviewer_assignment_history").Columns("pull_request_id", "old_reviewer_user_id", "new_reviewer_user_id", "reason").Values(prID, oldReviewerID, newReviewerID, reason).ToSql()
+	if err != nil {
+		return err
+	}
+	_, err = r.q.ExecContext(ctx, sql, args...)
+
+	return err
+}
+
+// LockOpenReviews возвращает OPEN Pull Request, где назначен кто-то из reviewerIDs, с их ревьюверами.
+// SQLite блокирует базу на запись целиком, поэтому отдельная блокировка строк не нужна
+func (r *Repository) LockOpenReviews(ctx context.Context, reviewerIDs []string) ([]repository.OpenReview, error) {
+	if len(reviewerIDs) == 0 {
+		return nil, nil
+	}
+	affected, affectedArgs, err := r.psql.Select("pull_request_id").From("pr_reviewers").Where(sq.Eq{"reviewer_user_id": reviewerIDs}).ToSql()
+	if err != nil {
+		return nil, err
+	}
+	sql, args, err := r.psql.Select("p.pull_request_id", "p.author_id", "r.reviewer_user_id").
+		From("pull_requests p").
+		Join("pr_reviewers r ON r.pull_request_id = p.pull_request_id").
+		Where(sq.Eq{"p.status": "OPEN"}).
+		Where(sq.Expr("p.pull_request_id IN ("+affected+")", affectedArgs...)).
+		OrderBy("p.pull_request_id", "r.reviewer_user_id").ToSql()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.q.QueryContext(ctx, sql, args...)
+	if err != nil {
+		logger.GetOrCreateLoggerFromCtx(ctx).Error(ctx, "failed to get open reviews", zap.Error(err))
+		return nil, err
+	}
+	type assignment struct{ prID, authorID, reviewerID string }
+	assignments, err := collect(rows, nil, func(s scanner) (assignment, error) {
+		var a assignment
+		err := s.Scan(&a.prID, &a.authorID, &a.reviewerID)
+		return a, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	// строки упорядочены по PR, собираем ревьюверов каждого PR
+	var res []repository.OpenReview
+	for _, a := range assignments {
+		if len(res) == 0 || res[len(res)-1].PullRequestID != a.prID {
+			res = append(res, repository.OpenReview{PullRequestID: a.prID, AuthorID: a.authorID})
+		}
+		last := &res[len(res)-1]
+		last.Reviewers = append(last.Reviewers, a.reviewerID)
+	}
+	return res, nil
+}
+
+// ApplyReviewerReplacements применяет замены ревьюверов пакетно: удаление, вставка и история — по одному запросу
+// Назначения без кандидата остаются как есть
+func (r *Repository) ApplyReviewerReplacements(ctx context.Context, replacements []repository.ReviewerReplacement, reason *string) error {
+	del := sq.Or{}
+	ins := r.psql.Insert("pr_reviewers").Columns("pull_request_id", "reviewer_user_id", "is_fallback")
+	hist := r.psql.Insert("reviewer_assignment_history").Columns("pull_request_id", "old_reviewer_user_id", "new_reviewer_user_id", "reason")
+	for _, rp := range replacements {
+		if rp.NewReviewerUserID == nil {
+			continue
+		}
+		del = append(del, sq.Eq{"pull_request_id": rp.PullRequestID, "reviewer_user_id": rp.OldReviewerUserID})
+		ins = ins.Values(rp.PullRequestID, *rp.NewReviewerUserID, rp.IsFallback)
+		hist = hist.Values(rp.PullRequestID, rp.OldReviewerUserID, *rp.NewReviewerUserID, reason)
+	}
+	if len(del) == 0 {
+		return nil
+	}
+
+	return r.inTx(ctx, func(tx *Repository) error {
+		sql, args, err := tx.psql.Delete("pr_reviewers").Where(del).ToSql()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.q.ExecContext(ctx, sql, args...); err != nil {
+			return err
+		}
+		for _, b := range []sq.InsertBuilder{ins, hist} {
+			sql, args, err := b.ToSql()
+			if err != nil {
+				return err
+			}
+			if _, err := tx.q.ExecContext(ctx, sql, args...); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// CountReviewersByPRID подсчитывает количество ревьюверов, назначенных на Pull Request
+func (r *Repository) CountReviewersByPRID(ctx context.Context, prID string) (int, error) {
+	return r.count(ctx, "pr_reviewers", sq.Eq{"pull_request_id": prID})
+}
+
+// ==================== Stats Repository Methods ====================
+
+// GetReviewerStats получает статистику по ревьюверам (кол-во назначений)
+func (r *Repository) GetReviewerStats(ctx context.Context) ([]repository.ReviewerStatRow, error) {
+	sql, args, err := r.psql.Select("u.user_id", "u.username", "COUNT(pr.id) as assigned_count").
+		From("users u").
+		LeftJoin("pr_reviewers pr ON u.user_id = pr.reviewer_user_id").
+		GroupBy("u.user_id", "u.username").
+		OrderBy("assigned_count DESC").
+		ToSql()
+	if err != nil {
+		logger.GetOrCreateLoggerFromCtx(ctx).Error(ctx, "failed to build sql for GetReviewerStats", zap.Error(err))
+		return nil, err
+	}
+	rows, err := r.q.QueryContext(ctx, sql, args...)
+	return collect(rows, err, func(s scanner) (repository.ReviewerStatRow, error) {
+		var stat repository.ReviewerStatRow
+		err := s.Scan(&stat.UserID, &stat.Username, &stat.AssignedCount)
+		return stat, err
+	})
+}
+
+// GetPRStats получает статистику по Pull Requests (кол-во назначенных ревьюверов)
+func (r *Repository) GetPRStats(ctx context.Context) ([]repository.PRStatRow, error) {
+	sql, args, err := r.psql.Select("p.pull_request_id", "p.pull_request_name", "p.author_id", "p.status", "COUNT(pr.id) as reviewer_count").
+		From("pull_requests p").
+		LeftJoin("pr_reviewers pr ON p.pull_request_id = pr.pull_request_id").
+		GroupBy("p.id", "p.pull_request_id", "p.pull_request_name", "p.author_id", "p.status").
+		OrderBy("reviewer_count DESC").
+		ToSql()
+	if err != nil {
+		logger.GetOrCreateLoggerFromCtx(ctx).Error(ctx, "failed to build sql for GetPRStats", zap.Error(err))
+		return nil, err
+	}
+	rows, err := r.q.QueryContext(ctx, sql, args...)
+	return collect(rows, err, func(s scanner) (repository.PRStatRow, error) {
+		var stat repository.PRStatRow
+		err := s.Scan(&stat.PullRequestID, &stat.PullRequestName, &stat.AuthorID, &stat.Status, &stat.ReviewerCount)
+		return stat, err
+	})
+}
+
+// ==================== helpers ====================
+
+// exists проверяет, есть ли в table строки по условию
+func (r *Repository) exists(ctx context.Context, table string, where sq.Eq) (bool, error) {
+	cnt, err := r.count(ctx, table, where)
+	return cnt > 0, err
+}
+
+// count подсчитывает строки table по условию
+func (r *Repository) count(ctx context.Context, table string, where sq.Eq) (int, error) {
+	sql, args, err := r.psql.Select("count(1)").From(table).Where(where).ToSql()
+	if err != nil {
+		return 0, err
+	}
+	var cnt int
+	if err := r.q.QueryRowContext(ctx, sql, args...).Scan(&cnt); err != nil {
+		return 0, err
+	}
+	return cnt, nil
+}
+
+func scanTeam(s scanner) (repository.TeamModel, error) {
+	var tm repository.TeamModel
+	err := s.Scan(&tm.ID, &tm.TeamName, &tm.CreatedAt, &tm.UpdatedAt)
+	return tm, err
+}
+
+func scanSettings(s scanner) (repository.TeamSettingsModel, error) {
+	var ts repository.TeamSettingsModel
+	err := s.Scan(&ts.TeamID, &ts.ReviewersCount, &ts.Strategy, &ts.MaxOpenReviews, &ts.CreatedAt, &ts.UpdatedAt)
+	return ts, err
+}
+
+func scanUser(s scanner) (repository.UserModel, error) {
+	var u repository.UserModel
+	err := s.Scan(&u.ID, &u.UserID, &u.Username, &u.TeamID, &u.IsActive, &u.Role, &u.CreatedAt, &u.UpdatedAt)
+	return u, err
+}
+
+func scanPR(s scanner) (repository.PullRequestModel, error) {
+	var pr repository.PullRequestModel
+	err := s.Scan(&pr.ID, &pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &pr.NeedMoreReviewers, &pr.CreatedAt, &pr.MergedAt, &pr.UpdatedAt)
+	return pr, err
+}
+
+func scanString(s scanner) (string, error) {
+	var v string
+	err := s.Scan(&v)
+	return v, err
+}
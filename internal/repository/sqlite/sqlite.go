@@ -0,0 +1,160 @@
+// Package sqlite реализует repository.Repository поверх SQLite (database/sql, драйвер modernc.org/sqlite).
+// Запросы те же, что у PrRepository, с поправками на диалект: плейсхолдеры ?, статус PR — TEXT с CHECK
+// вместо enum pr_status, без FOR UPDATE и массивов Postgres.
+// Поведение совпадает с PrRepository, это проверяет общий набор тестов repotest
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"avito-test-quest/internal/logger"
+	"avito-test-quest/internal/repository"
+
+	sq "github.com/Masterminds/squirrel"
+	"go.uber.org/zap"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// nowSQL текущее время в UTC с миллисекундами, как DEFAULT колонок в миграциях SQLite.
+// В отличие от CURRENT_TIMESTAMP Postgres фиксировано в пределах запроса, а не транзакции
+const nowSQL = "strftime('%Y-%m-%d %H:%M:%f', 'now')"
+
+// querier общие методы *sql.DB и *sql.Tx
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Repository хранилище SQLite
+type Repository struct {
+	db   *sql.DB
+	q    querier  // db вне транзакции, иначе *sql.Tx
+	tx   *txState // nil вне транзакции
+	psql sq.StatementBuilderType
+}
+
+// txState состояние открытой транзакции, общее для вложенных WithTx
+type txState struct {
+	savepoints int // счетчик для уникальных имен savepoint
+}
+
+// NewRepository создает репозиторий поверх открытой БД с примененными миграциями
+func NewRepository(db *sql.DB) repository.Repository {
+	return &Repository{
+		db:   db,
+		q:    db,
+		psql: sq.StatementBuilder.PlaceholderFormat(sq.Question),
+	}
+}
+
+// WithTx выполняет fn в транзакции
+// fn получает репозиторий, работающий поверх транзакции; при ошибке или панике транзакция откатывается.
+// Вызов внутри другой транзакции создает savepoint
+func (r *Repository) WithTx(ctx context.Context, fn func(repo repository.Repository) error) error {
+	return r.inTx(ctx, func(tx *Repository) error {
+		return fn(tx)
+	})
+}
+
+// inTx выполняет fn в транзакции, внутри транзакции — в savepoint
+func (r *Repository) inTx(ctx context.Context, fn func(tx *Repository) error) error {
+	if r.tx != nil {
+		return r.inSavepoint(ctx, fn)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		logger.GetOrCreateLoggerFromCtx(ctx).Error(ctx, "failed to begin transaction", zap.Error(err))
+		return err
+	}
+	// после Commit откат вернет sql.ErrTxDone, его игнорируем
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(&Repository{db: r.db, q: tx, tx: &txState{}, psql: r.psql}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// inSavepoint выполняет fn внутри savepoint текущей транзакции; при ошибке или панике откатывает только его
+func (r *Repository) inSavepoint(ctx context.Context, fn func(tx *Repository) error) error {
+	r.tx.savepoints++
+	name := fmt.Sprintf("sp_%d", r.tx.savepoints)
+	if _, err := r.q.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return err
+	}
+	released := false
+	defer func() {
+		if !released {
+			// откат не должен зависеть от отмены контекста запроса
+			ctx := context.WithoutCancel(ctx)
+			_, _ = r.q.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name)
+			_, _ = r.q.ExecContext(ctx, "RELEASE SAVEPOINT "+name)
+		}
+	}()
+
+	if err := fn(&Repository{db: r.db, q: r.q, tx: r.tx, psql: r.psql}); err != nil {
+		return err
+	}
+	if _, err := r.q.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		return err
+	}
+	released = true
+
+	return nil
+}
+
+// scanner строка результата: *sql.Row или *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// collect читает все строки rows функцией scan и закрывает rows
+func collect[T any](rows *sql.Rows, err error, scan func(s scanner) (T, error)) ([]T, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var res []T
+	for rows.Next() {
+		v, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, v)
+	}
+
+	return res, rows.Err()
+}
+
+// isNoRows проверяет, что запрос не вернул строк
+func isNoRows(err error) bool {
+	return errors.Is(err, sql.ErrNoRows)
+}
+
+// notFound заменяет sql.ErrNoRows на repository.ErrNotFound, которую ожидает сервис
+func notFound(err error) error {
+	if isNoRows(err) {
+		return repository.ErrNotFound
+	}
+	return err
+}
+
+// isUniqueViolation проверяет, что err — нарушение уникального ключа или первичного ключа
+func isUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	code := sqliteErr.Code()
+	return code == sqlite3.SQLITE_CONSTRAINT_UNIQUE || code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+}
+
+// проверка реализации интерфейса Repository
+var _ repository.Repository = (*Repository)(nil)
@@ -0,0 +1,27 @@
+package sqlite_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"avito-test-quest/internal/repository"
+	"avito-test-quest/internal/repository/repotest"
+	sqliterepo "avito-test-quest/internal/repository/sqlite"
+	"avito-test-quest/internal/sqlite"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepositoryConformance(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repository.Repository {
+		cfg := sqlite.Config{Path: filepath.Join(t.TempDir(), "test.db")}
+		require.NoError(t, sqlite.Migrate(context.Background(), cfg))
+
+		db, err := sqlite.New(context.Background(), cfg)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+
+		return sqliterepo.NewRepository(db)
+	})
+}
@@ -0,0 +1,335 @@
+package repository
+
+import (
+	"context"
+
+	"avito-test-quest/internal/tracing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracedRepository оборачивает каждый метод репозитория в спан OpenTelemetry.
+// Спаны отдельных SQL запросов с их текстом создает pgx tracer, они становятся дочерними
+type tracedRepository struct {
+	next   Repository
+	tracer trace.Tracer
+	// txSpan спан WithTx для репозитория внутри транзакции. fn получает только repo,
+	// а ctx берет из внешней функции, поэтому родитель задается здесь
+	txSpan trace.Span
+}
+
+// NewTracedRepository создает обертку над next, открывающую спан на каждый вызов.
+// Без настроенного TracerProvider спаны не записываются
+func NewTracedRepository(next Repository) Repository {
+	return &tracedRepository{next: next, tracer: otel.Tracer("avito-test-quest/internal/repository")}
+}
+
+// start открывает спан метода. Внутри транзакции спан становится дочерним спану WithTx,
+// остальные значения ctx (логгер, отмена) сохраняются
+func (r *tracedRepository) start(ctx context.Context, name string) (context.Context, trace.Span) {
+	if r.txSpan != nil {
+		ctx = trace.ContextWithSpan(ctx, r.txSpan)
+	}
+	return r.tracer.Start(ctx, name)
+}
+
+// WithTx открывает спан транзакции; вызовы репозитория внутри fn становятся его дочерними спанами
+func (r *tracedRepository) WithTx(ctx context.Context, fn func(repo Repository) error) error {
+	ctx, span := r.start(ctx, "PrRepository.WithTx")
+	err := r.next.WithTx(ctx, func(repo Repository) error {
+		return fn(&tracedRepository{next: repo, tracer: r.tracer, txSpan: span})
+	})
+	tracing.End(span, err)
+	return err
+}
+
+func (r *tracedRepository) CreateTeam(ctx context.Context, teamName string) (*TeamModel, error) {
+	ctx, span := r.start(ctx, "PrRepository.CreateTeam")
+	res, err := r.next.CreateTeam(ctx, teamName)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) GetTeamByName(ctx context.Context, teamName string) (*TeamModel, error) {
+	ctx, span := r.start(ctx, "PrRepository.GetTeamByName")
+	res, err := r.next.GetTeamByName(ctx, teamName)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) GetTeamByID(ctx context.Context, teamID int64) (*TeamModel, error) {
+	ctx, span := r.start(ctx, "PrRepository.GetTeamByID")
+	res, err := r.next.GetTeamByID(ctx, teamID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) TeamExists(ctx context.Context, teamName string) (bool, error) {
+	ctx, span := r.start(ctx, "PrRepository.TeamExists")
+	res, err := r.next.TeamExists(ctx, teamName)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) GetTeamSettings(ctx context.Context, teamID int64) (*TeamSettingsModel, error) {
+	ctx, span := r.start(ctx, "PrRepository.GetTeamSettings")
+	res, err := r.next.GetTeamSettings(ctx, teamID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) UpsertTeamSettings(ctx context.Context, settings TeamSettingsModel) (*TeamSettingsModel, error) {
+	ctx, span := r.start(ctx, "PrRepository.UpsertTeamSettings")
+	res, err := r.next.UpsertTeamSettings(ctx, settings)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) GetFallbackTeams(ctx context.Context, teamID int64) ([]TeamModel, error) {
+	ctx, span := r.start(ctx, "PrRepository.GetFallbackTeams")
+	res, err := r.next.GetFallbackTeams(ctx, teamID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) SetFallbackTeams(ctx context.Context, teamID int64, fallbackTeamIDs []int64) error {
+	ctx, span := r.start(ctx, "PrRepository.SetFallbackTeams")
+	err := r.next.SetFallbackTeams(ctx, teamID, fallbackTeamIDs)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *tracedRepository) CreateUser(ctx context.Context, userID, username string, teamID int64, isActive bool) (*UserModel, error) {
+	ctx, span := r.start(ctx, "PrRepository.CreateUser")
+	res, err := r.next.CreateUser(ctx, userID, username, teamID, isActive)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) UpdateUser(ctx context.Context, userID, username string, teamID int64, isActive bool) (*UserModel, error) {
+	ctx, span := r.start(ctx, "PrRepository.UpdateUser")
+	res, err := r.next.UpdateUser(ctx, userID, username, teamID, isActive)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) GetUserByID(ctx context.Context, userID string) (*UserModel, error) {
+	ctx, span := r.start(ctx, "PrRepository.GetUserByID")
+	res, err := r.next.GetUserByID(ctx, userID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) GetUserWithTeam(ctx context.Context, userID string) (*UserWithTeam, error) {
+	ctx, span := r.start(ctx, "PrRepository.GetUserWithTeam")
+	res, err := r.next.GetUserWithTeam(ctx, userID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) GetUsersByTeamID(ctx context.Context, teamID int64) ([]UserModel, error) {
+	ctx, span := r.start(ctx, "PrRepository.GetUsersByTeamID")
+	res, err := r.next.GetUsersByTeamID(ctx, teamID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) SetIsActive(ctx context.Context, userID string, isActive bool) (*UserModel, error) {
+	ctx, span := r.start(ctx, "PrRepository.SetIsActive")
+	res, err := r.next.SetIsActive(ctx, userID, isActive)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) SetUserRole(ctx context.Context, userID, role string) (*UserModel, error) {
+	ctx, span := r.start(ctx, "PrRepository.SetUserRole")
+	res, err := r.next.SetUserRole(ctx, userID, role)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) UserExists(ctx context.Context, userID string) (bool, error) {
+	ctx, span := r.start(ctx, "PrRepository.UserExists")
+	res, err := r.next.UserExists(ctx, userID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) GetActiveUsersInTeam(ctx context.Context, teamID int64) ([]UserModel, error) {
+	ctx, span := r.start(ctx, "PrRepository.GetActiveUsersInTeam")
+	res, err := r.next.GetActiveUsersInTeam(ctx, teamID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) DeactivateTeamMembers(ctx context.Context, teamID int64, userIDs []string, allExcept bool) ([]string, error) {
+	ctx, span := r.start(ctx, "PrRepository.DeactivateTeamMembers")
+	res, err := r.next.DeactivateTeamMembers(ctx, teamID, userIDs, allExcept)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) GetActiveUsersWithLoad(ctx context.Context, teamID int64) ([]ReviewerCandidate, error) {
+	ctx, span := r.start(ctx, "PrRepository.GetActiveUsersWithLoad")
+	res, err := r.next.GetActiveUsersWithLoad(ctx, teamID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) CreatePullRequest(ctx context.Context, prID, prName, authorID string) (*PullRequestModel, error) {
+	ctx, span := r.start(ctx, "PrRepository.CreatePullRequest")
+	res, err := r.next.CreatePullRequest(ctx, prID, prName, authorID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) GetPullRequestByID(ctx context.Context, prID string) (*PullRequestModel, error) {
+	ctx, span := r.start(ctx, "PrRepository.GetPullRequestByID")
+	res, err := r.next.GetPullRequestByID(ctx, prID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) LockPullRequest(ctx context.Context, prID string) (*PullRequestModel, error) {
+	ctx, span := r.start(ctx, "PrRepository.LockPullRequest")
+	res, err := r.next.LockPullRequest(ctx, prID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) GetPullRequestWithReviewers(ctx context.Context, prID string) (*PRWithReviewers, error) {
+	ctx, span := r.start(ctx, "PrRepository.GetPullRequestWithReviewers")
+	res, err := r.next.GetPullRequestWithReviewers(ctx, prID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) MergePullRequest(ctx context.Context, prID string) (*PullRequestModel, error) {
+	ctx, span := r.start(ctx, "PrRepository.MergePullRequest")
+	res, err := r.next.MergePullRequest(ctx, prID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) PullRequestExists(ctx context.Context, prID string) (bool, error) {
+	ctx, span := r.start(ctx, "PrRepository.PullRequestExists")
+	res, err := r.next.PullRequestExists(ctx, prID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) GetPullRequestsByAuthor(ctx context.Context, authorID string) ([]PullRequestModel, error) {
+	ctx, span := r.start(ctx, "PrRepository.GetPullRequestsByAuthor")
+	res, err := r.next.GetPullRequestsByAuthor(ctx, authorID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) SetNeedMoreReviewers(ctx context.Context, prID string, needMore bool) error {
+	ctx, span := r.start(ctx, "PrRepository.SetNeedMoreReviewers")
+	err := r.next.SetNeedMoreReviewers(ctx, prID, needMore)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *tracedRepository) GetOpenPRsNeedingReviewers(ctx context.Context, teamID int64) ([]PullRequestModel, error) {
+	ctx, span := r.start(ctx, "PrRepository.GetOpenPRsNeedingReviewers")
+	res, err := r.next.GetOpenPRsNeedingReviewers(ctx, teamID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) AssignReviewer(ctx context.Context, prID, reviewerUserID string, isFallback bool) error {
+	ctx, span := r.start(ctx, "PrRepository.AssignReviewer")
+	err := r.next.AssignReviewer(ctx, prID, reviewerUserID, isFallback)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *tracedRepository) RemoveReviewer(ctx context.Context, prID, reviewerUserID string) error {
+	ctx, span := r.start(ctx, "PrRepository.RemoveReviewer")
+	err := r.next.RemoveReviewer(ctx, prID, reviewerUserID)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *tracedRepository) GetReviewersByPRID(ctx context.Context, prID string) ([]string, error) {
+	ctx, span := r.start(ctx, "PrRepository.GetReviewersByPRID")
+	res, err := r.next.GetReviewersByPRID(ctx, prID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) GetReviewerAssignmentsByPRID(ctx context.Context, prID string) ([]PRReviewerModel, error) {
+	ctx, span := r.start(ctx, "PrRepository.GetReviewerAssignmentsByPRID")
+	res, err := r.next.GetReviewerAssignmentsByPRID(ctx, prID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) GetPRsByReviewerID(ctx context.Context, reviewerUserID string) ([]PullRequestModel, error) {
+	ctx, span := r.start(ctx, "PrRepository.GetPRsByReviewerID")
+	res, err := r.next.GetPRsByReviewerID(ctx, reviewerUserID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) IsReviewerAssigned(ctx context.Context, prID, reviewerUserID string) (bool, error) {
+	ctx, span := r.start(ctx, "PrRepository.IsReviewerAssigned")
+	res, err := r.next.IsReviewerAssigned(ctx, prID, reviewerUserID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) ReplaceReviewer(ctx context.Context, prID, oldReviewerID, newReviewerID string, isFallback bool, reason *string) error {
+	ctx, span := r.start(ctx, "PrRepository.ReplaceReviewer")
+	err := r.next.ReplaceReviewer(ctx, prID, oldReviewerID, newReviewerID, isFallback, reason)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *tracedRepository) GetAssignmentHistory(ctx context.Context, prID string) ([]ReviewerAssignmentHistoryModel, error) {
+	ctx, span := r.start(ctx, "PrRepository.GetAssignmentHistory")
+	res, err := r.next.GetAssignmentHistory(ctx, prID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) LockOpenReviews(ctx context.Context, reviewerIDs []string) ([]OpenReview, error) {
+	ctx, span := r.start(ctx, "PrRepository.LockOpenReviews")
+	res, err := r.next.LockOpenReviews(ctx, reviewerIDs)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) ApplyReviewerReplacements(ctx context.Context, replacements []ReviewerReplacement, reason *string) error {
+	ctx, span := r.start(ctx, "PrRepository.ApplyReviewerReplacements")
+	err := r.next.ApplyReviewerReplacements(ctx, replacements, reason)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *tracedRepository) CountReviewersByPRID(ctx context.Context, prID string) (int, error) {
+	ctx, span := r.start(ctx, "PrRepository.CountReviewersByPRID")
+	res, err := r.next.CountReviewersByPRID(ctx, prID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) GetReviewerStats(ctx context.Context) ([]ReviewerStatRow, error) {
+	ctx, span := r.start(ctx, "PrRepository.GetReviewerStats")
+	res, err := r.next.GetReviewerStats(ctx)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (r *tracedRepository) GetPRStats(ctx context.Context) ([]PRStatRow, error) {
+	ctx, span := r.start(ctx, "PrRepository.GetPRStats")
+	res, err := r.next.GetPRStats(ctx)
+	tracing.End(span, err)
+	return res, err
+}
+
+// проверка реализации интерфейса Repository
+var _ Repository = (*tracedRepository)(nil)
@@ -0,0 +1,45 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"avito-test-quest/internal/repository"
+	"avito-test-quest/internal/repository/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// TestTracedRepositoryTxSpans вызовы внутри WithTx с внешним ctx — дочерние спаны транзакции
+func TestTracedRepositoryTxSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	ctx := context.Background()
+	repo := repository.NewTracedRepository(memory.New())
+	err := repo.WithTx(ctx, func(repo repository.Repository) error {
+		_, err := repo.CreateTeam(ctx, "backend")
+		return err
+	})
+	require.NoError(t, err)
+	_, err = repo.GetTeamByName(ctx, "backend")
+	require.NoError(t, err)
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, s := range recorder.Ended() {
+		spans[s.Name()] = s
+	}
+	require.Len(t, spans, 3)
+	tx := spans["PrRepository.WithTx"]
+	assert.Equal(t, tx.SpanContext().SpanID(), spans["PrRepository.CreateTeam"].Parent().SpanID())
+	assert.Equal(t, tx.SpanContext().TraceID(), spans["PrRepository.CreateTeam"].SpanContext().TraceID())
+	// после транзакции спаны снова корневые
+	assert.False(t, spans["PrRepository.GetTeamByName"].Parent().IsValid())
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"avito-test-quest/internal/apperr"
+	"avito-test-quest/internal/auth"
+	"avito-test-quest/internal/repository"
+	"context"
+	"errors"
+)
+
+// роли пользователей, хранятся в users.role
+const (
+	RoleAdmin    = "admin"
+	RoleTeamLead = "team_lead"
+	RoleMember   = "member"
+)
+
+// actor вызывающая сторона с ролью и командой из БД
+type actor struct {
+	userID string
+	role   string
+	teamID int64 // 0 для админских токенов без пользователя
+}
+
+// currentActor определяет вызывающую сторону по Principal из контекста.
+// Админский токен или JWT с ролью admin дают роль admin, иначе роль берется из users.role
+func currentActor(ctx context.Context, repo repository.Repository) (*actor, error) {
+	p, ok := auth.PrincipalFromCtx(ctx)
+	if !ok {
+		return nil, apperr.New(apperr.CodeUnauthorized, "authentication required")
+	}
+	if p.Role == auth.RoleAdmin {
+		return &actor{userID: p.UserID, role: RoleAdmin}, nil
+	}
+	u, err := repo.GetUserByID(ctx, p.UserID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, apperr.Wrap(err, apperr.CodeForbidden, "caller is not a known user")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &actor{userID: u.UserID, role: u.Role, teamID: u.TeamID}, nil
+}
+
+// requireAdmin разрешает операцию только админу
+func requireAdmin(ctx context.Context, repo repository.Repository) error {
+	a, err := currentActor(ctx, repo)
+	if err != nil {
+		return err
+	}
+	if a.role != RoleAdmin {
+		return apperr.New(apperr.CodeForbidden, "admin role required")
+	}
+	return nil
+}
+
+// leads тимлид управляет только участниками своей команды
+func (a *actor) leads(teamID int64) bool {
+	return a.role == RoleTeamLead && a.teamID == teamID
+}
+
+// canManageTeam админ или тимлид этой команды
+func (a *actor) canManageTeam(teamID int64) bool {
+	return a.role == RoleAdmin || a.leads(teamID)
+}
+
+// canSetIsActive админ или тимлид команды пользователя
+func (a *actor) canSetIsActive(target *repository.UserModel) bool {
+	return a.role == RoleAdmin || a.leads(target.TeamID)
+}
+
+// canReassign админ, тимлид команды ревьювера либо сам ревьювер.
+// Команда автора PR не учитывается: тимлид не снимает с ревью участников других команд
+func (a *actor) canReassign(oldReviewer *repository.UserModel) bool {
+	switch a.role {
+	case RoleAdmin:
+		return true
+	case RoleTeamLead:
+		return a.leads(oldReviewer.TeamID) || a.userID == oldReviewer.UserID
+	default:
+		return a.userID == oldReviewer.UserID
+	}
+}
@@ -14,14 +14,31 @@ type TeamService interface {
 	// GetTeam получает команду по имени
 	// Возвращает команду или ошибку NOT_FOUND
 	GetTeam(ctx context.Context, teamName string) (*models.Team, error)
+
+	// GetTeamSettings получает настройки назначения ревьюверов команды
+	// Возвращает настройки (по умолчанию, если не задавались) или ошибку NOT_FOUND
+	GetTeamSettings(ctx context.Context, teamName string) (*models.TeamSettings, error)
+
+	// UpdateTeamSettings изменяет настройки назначения ревьюверов команды
+	// Возвращает обновленные настройки или ошибки: NOT_FOUND, INVALID_STRATEGY, INVALID_FALLBACK_TEAM
+	UpdateTeamSettings(ctx context.Context, input models.UpdateTeamSettingsInput) (*models.TeamSettings, error)
+
+	// DeactivateTeamMembers деактивирует участников команды и переназначает их OPEN ревью
+	// на оставшихся активных участников (затем на резервные команды) в одной транзакции
+	// Возвращает деактивированных, замены и ревью без кандидата или ошибку NOT_FOUND
+	DeactivateTeamMembers(ctx context.Context, input models.DeactivateTeamMembersInput) (*models.DeactivateTeamMembersOutput, error)
 }
 
 // UserService интерфейс для работы с пользователями
 type UserService interface {
-	// SetIsActive устанавливает флаг активности пользователя
-	// Возвращает обновленного пользователя или ошибку NOT_FOUND
+	// SetIsActive устанавливает флаг активности пользователя (админ или тимлид его команды)
+	// Возвращает обновленного пользователя или ошибки: FORBIDDEN, NOT_FOUND
 	SetIsActive(ctx context.Context, input models.SetIsActiveInput) (*models.User, error)
 
+	// SetUserRole устанавливает роль пользователя: admin, team_lead или member (только для админа)
+	// Возвращает обновленного пользователя или ошибки: FORBIDDEN, NOT_FOUND
+	SetUserRole(ctx context.Context, input models.SetRoleInput) (*models.User, error)
+
 	// GetUserReviews получает список PR, где пользователь назначен ревьювером
 	// Возвращает список PR или ошибку NOT_FOUND
 	GetUserReviews(ctx context.Context, userID string) (*models.UserReviewsOutput, error)
@@ -29,7 +46,7 @@ type UserService interface {
 
 // PullRequestService интерфейс для работы с Pull Request
 type PullRequestService interface {
-	// CreatePullRequest создает PR и назначает до 2 ревьюверов
+	// CreatePullRequest создает PR и назначает ревьюверов согласно настройкам команды автора
 	// Возвращает созданный PR или ошибки: NOT_FOUND, PR_EXISTS
 	CreatePullRequest(ctx context.Context, input models.CreatePullRequestInput) (*models.PullRequest, error)
 
@@ -37,10 +54,17 @@ type PullRequestService interface {
 	// Возвращает обновленный PR или ошибку NOT_FOUND
 	MergePullRequest(ctx context.Context, input models.MergePullRequestInput) (*models.PullRequest, error)
 
-	// ReassignReviewer переназначает ревьювера на другого из команды
+	// ReassignReviewer переназначает ревьювера на другого из команды,
+	// а если в ней нет кандидатов — из резервных команд
 	// Возвращает обновленный PR и ID нового ревьювера
-	// Ошибки: NOT_FOUND, PR_MERGED, NOT_ASSIGNED, NO_CANDIDATE
+	// Админ переназначает любого ревьювера, тимлид — ревьюверов своей команды или на PR своей команды,
+	// участник — только себя
+	// Ошибки: FORBIDDEN, NOT_FOUND, PR_MERGED, NOT_ASSIGNED, NO_CANDIDATE
 	ReassignReviewer(ctx context.Context, input models.ReassignReviewerInput) (*models.ReassignReviewerOutput, error)
+
+	// GetPullRequestHistory получает историю назначений и переназначений ревьюверов PR
+	// Возвращает историю в хронологическом порядке или ошибку NOT_FOUND
+	GetPullRequestHistory(ctx context.Context, prID string) (*models.PullRequestHistory, error)
 }
 
 // StatsService интерфейс для получения статистики
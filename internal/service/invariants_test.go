@@ -0,0 +1,428 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"avito-test-quest/internal/apperr"
+	"avito-test-quest/internal/models"
+	"avito-test-quest/internal/repository"
+	"avito-test-quest/internal/repository/memory"
+	"avito-test-quest/internal/service"
+)
+
+// Модельный тест назначения ревьюверов: последовательность операций create/reassign/merge/setIsActive
+// выполняется над сервисом с хранилищем в памяти. После каждого шага состояние хранилища сверяется
+// с моделью, а инварианты проверяются по самому хранилищу:
+//   - автор не ревьюит свой PR, ревьюверы не повторяются;
+//   - ревьюверов не больше, чем reviewers_count команды автора, флаг нехватки OPEN PR соответствует их числу;
+//   - новые назначения получают только активные пользователи;
+//   - набор ревьюверов MERGED PR не меняется.
+// Последовательность задается байтами, поэтому ее же перебирает fuzzing (FuzzAssignmentInvariants)
+
+// invTeam настройки команды в модельном тесте
+type invTeam struct {
+	name      string
+	reviewers int
+	strategy  string
+	maxOpen   int
+	fallbacks []int // индексы резервных команд
+}
+
+// invTeams команды с разными стратегиями, лимитами и резервной командой
+var invTeams = []invTeam{
+	{name: "team-0", reviewers: 2, strategy: models.StrategyRoundRobin, fallbacks: []int{1}},
+	{name: "team-1", reviewers: 1, strategy: models.StrategyLeastLoaded, maxOpen: 2},
+	{name: "team-2", reviewers: 3, strategy: models.StrategyRandom, maxOpen: 3},
+}
+
+const (
+	invUsersPerTeam = 4
+	invPRs          = 8  // pr-0..pr-7, повторные create дают PR_EXISTS
+	invMaxSteps     = 64 // шагов в одной последовательности
+)
+
+// invUsers участники команд и один неизвестный пользователь для NOT_FOUND
+var invUsers = len(invTeams)*invUsersPerTeam + 1
+
+// modelUser пользователь в модели
+type modelUser struct {
+	team   int
+	active bool
+}
+
+// modelPR PR в модели
+type modelPR struct {
+	author    string
+	merged    bool
+	reviewers []string
+	needMore  bool
+}
+
+// harness сервис над хранилищем в памяти и модель его состояния
+type harness struct {
+	t    *testing.T
+	ctx  context.Context
+	svc  service.Service
+	repo repository.Repository
+
+	users map[string]*modelUser
+	prs   map[string]*modelPR
+
+	step   int
+	op     string
+	stored map[string][]string // ревьюверы PR в хранилище после предыдущего шага
+	frozen map[string][]string // ревьюверы PR на момент мержа
+}
+
+func newHarness(t *testing.T) *harness {
+	t.Helper()
+	repo := memory.New()
+	h := &harness{
+		t:      t,
+		ctx:    adminCtx(t),
+		svc:    service.NewPrService(repo, service.NewRandomSelector(), nil),
+		repo:   repo,
+		users:  map[string]*modelUser{},
+		prs:    map[string]*modelPR{},
+		stored: map[string][]string{},
+		frozen: map[string][]string{},
+	}
+	for i, tm := range invTeams {
+		input := models.CreateTeamInput{TeamName: tm.name}
+		for j := range invUsersPerTeam {
+			id := fmt.Sprintf("u%d", i*invUsersPerTeam+j)
+			input.Members = append(input.Members, models.TeamMember{UserID: id, Username: id, IsActive: true})
+			h.users[id] = &modelUser{team: i, active: true}
+		}
+		if _, err := h.svc.CreateTeam(h.ctx, input); err != nil {
+			t.Fatalf("create %s: %v", tm.name, err)
+		}
+	}
+	for _, tm := range invTeams {
+		fallbacks := []string{}
+		for _, fb := range tm.fallbacks {
+			fallbacks = append(fallbacks, invTeams[fb].name)
+		}
+		_, err := h.svc.UpdateTeamSettings(h.ctx, models.UpdateTeamSettingsInput{
+			TeamName: tm.name, ReviewersCount: &tm.reviewers, Strategy: &tm.strategy, MaxOpenReviews: &tm.maxOpen,
+			FallbackTeams: fallbacks,
+		})
+		if err != nil {
+			t.Fatalf("settings %s: %v", tm.name, err)
+		}
+	}
+	return h
+}
+
+// run выполняет операции, закодированные тройками байт: операция и два аргумента
+func (h *harness) run(data []byte) {
+	for len(data) >= 3 && h.step < invMaxSteps {
+		op, a, b := data[0], data[1], data[2]
+		data = data[3:]
+		h.step++
+		prID := fmt.Sprintf("pr-%d", int(a)%invPRs)
+		switch op % 4 {
+		case 0:
+			h.create(prID, invUserID(b))
+		case 1:
+			h.reassign(prID, h.reviewerOrAny(prID, b))
+		case 2:
+			h.merge(prID)
+		case 3:
+			h.setIsActive(invUserID(a), b%2 == 1)
+		}
+		h.check()
+	}
+}
+
+// invUserID пользователь по байту, включая неизвестного
+func invUserID(b byte) string {
+	return fmt.Sprintf("u%d", int(b)%invUsers)
+}
+
+// reviewerOrAny обычно выбирает назначенного ревьювера PR, иначе любого пользователя для NOT_ASSIGNED
+func (h *harness) reviewerOrAny(prID string, b byte) string {
+	if pr := h.prs[prID]; pr != nil && len(pr.reviewers) > 0 && b%4 != 0 {
+		return pr.reviewers[int(b/4)%len(pr.reviewers)]
+	}
+	return invUserID(b)
+}
+
+func (h *harness) create(prID, author string) {
+	h.op = fmt.Sprintf("create %s by %s", prID, author)
+	out, err := h.svc.CreatePullRequest(h.ctx, models.CreatePullRequestInput{PullRequestID: prID, PullRequestName: prID, AuthorID: author})
+	u := h.users[author]
+	switch {
+	case u == nil:
+		h.expectErr(err, apperr.ErrNotFound)
+	case h.prs[prID] != nil:
+		h.expectErr(err, apperr.ErrPRExists)
+	default:
+		h.expectOK(err)
+		count := invTeams[u.team].reviewers
+		want, allowed := h.plan(u.team, map[string]bool{author: true}, count)
+		if len(out.AssignedReviewers) != want {
+			h.fatalf("assigned %v, want %d reviewers", out.AssignedReviewers, want)
+		}
+		for _, r := range out.AssignedReviewers {
+			if !allowed[r] {
+				h.fatalf("assigned %s, eligible %v", r, allowed)
+			}
+		}
+		if out.NeedMoreReviewers != (want < count) {
+			h.fatalf("needMoreReviewers %t with %d of %d reviewers", out.NeedMoreReviewers, want, count)
+		}
+		h.prs[prID] = &modelPR{author: author, reviewers: out.AssignedReviewers, needMore: out.NeedMoreReviewers}
+	}
+}
+
+func (h *harness) reassign(prID, old string) {
+	h.op = fmt.Sprintf("reassign %s from %s", prID, old)
+	out, err := h.svc.ReassignReviewer(h.ctx, models.ReassignReviewerInput{PullRequestID: prID, OldReviewerID: old})
+	pr := h.prs[prID]
+	switch {
+	case pr == nil:
+		h.expectErr(err, apperr.ErrNotFound)
+	case pr.merged:
+		h.expectErr(err, apperr.ErrPRMerged)
+	case !slices.Contains(pr.reviewers, old):
+		h.expectErr(err, apperr.ErrNotAssigned)
+	default:
+		excluded := map[string]bool{pr.author: true}
+		for _, r := range pr.reviewers {
+			excluded[r] = true
+		}
+		want, allowed := h.plan(h.users[old].team, excluded, 1)
+		if want == 0 {
+			h.expectErr(err, apperr.ErrNoCandidate)
+			return
+		}
+		h.expectOK(err)
+		if !allowed[out.ReplacedBy] {
+			h.fatalf("replaced by %s, eligible %v", out.ReplacedBy, allowed)
+		}
+		pr.reviewers[slices.Index(pr.reviewers, old)] = out.ReplacedBy
+	}
+}
+
+func (h *harness) merge(prID string) {
+	h.op = "merge " + prID
+	out, err := h.svc.MergePullRequest(h.ctx, models.MergePullRequestInput{PullRequestID: prID})
+	pr := h.prs[prID]
+	if pr == nil {
+		h.expectErr(err, apperr.ErrNotFound)
+		return
+	}
+	h.expectOK(err)
+	if out.Status != "MERGED" || !sameSet(out.AssignedReviewers, pr.reviewers) {
+		h.fatalf("merged %s with %v, want MERGED with %v", out.Status, out.AssignedReviewers, pr.reviewers)
+	}
+	if !pr.merged {
+		h.frozen[prID] = slices.Clone(pr.reviewers)
+	}
+	pr.merged = true
+}
+
+func (h *harness) setIsActive(userID string, active bool) {
+	h.op = fmt.Sprintf("setIsActive %s %t", userID, active)
+	_, err := h.svc.SetIsActive(h.ctx, models.SetIsActiveInput{UserID: userID, IsActive: active})
+	u := h.users[userID]
+	if u == nil {
+		h.expectErr(err, apperr.ErrNotFound)
+		return
+	}
+	h.expectOK(err)
+	u.active = active
+	if !active {
+		return
+	}
+	// активация добирает ревьюверов на OPEN PR авторов команды; какие PR получат кандидатов первыми,
+	// зависит от стратегии, поэтому модель берет результат из хранилища и проверяет, что добор полный
+	var toppedUp []string
+	for id, pr := range h.prs {
+		if !pr.merged && pr.needMore && h.users[pr.author].team == u.team {
+			toppedUp = append(toppedUp, id)
+		}
+	}
+	for _, id := range toppedUp {
+		pr := h.prs[id]
+		got := h.load(id)
+		for _, r := range pr.reviewers {
+			if !slices.Contains(got.Reviewers, r) {
+				h.fatalf("%s lost reviewer %s on top-up", id, r)
+			}
+		}
+		pr.reviewers, pr.needMore = got.Reviewers, got.PullRequest.NeedMoreReviewers
+	}
+	for _, id := range toppedUp {
+		pr := h.prs[id]
+		if !pr.needMore {
+			continue
+		}
+		excluded := map[string]bool{pr.author: true}
+		for _, r := range pr.reviewers {
+			excluded[r] = true
+		}
+		team := h.users[pr.author].team
+		if n, allowed := h.plan(team, excluded, invTeams[team].reviewers-len(pr.reviewers)); n > 0 {
+			h.fatalf("%s still needs reviewers with eligible %v", id, allowed)
+		}
+	}
+}
+
+// plan повторяет выбор ревьюверов сервисом: до n кандидатов из команды team, затем из ее резервных команд.
+// Возвращает, сколько ревьюверов должно быть назначено, и всех допустимых кандидатов
+func (h *harness) plan(team int, excluded map[string]bool, n int) (int, map[string]bool) {
+	allowed := map[string]bool{}
+	picked := 0
+	for _, t := range append([]int{team}, invTeams[team].fallbacks...) {
+		if picked >= n {
+			break
+		}
+		eligible := h.eligible(t, excluded)
+		for _, id := range eligible {
+			allowed[id] = true
+		}
+		picked += min(n-picked, len(eligible))
+	}
+	return picked, allowed
+}
+
+// eligible активные участники команды вне excluded, не превысившие лимит открытых ревью
+func (h *harness) eligible(team int, excluded map[string]bool) []string {
+	var res []string
+	for id, u := range h.users {
+		if u.team != team || !u.active || excluded[id] {
+			continue
+		}
+		if limit := invTeams[team].maxOpen; limit > 0 && h.openReviews(id) >= limit {
+			continue
+		}
+		res = append(res, id)
+	}
+	return res
+}
+
+// openReviews количество OPEN PR, где пользователь назначен ревьювером
+func (h *harness) openReviews(userID string) int {
+	n := 0
+	for _, pr := range h.prs {
+		if !pr.merged && slices.Contains(pr.reviewers, userID) {
+			n++
+		}
+	}
+	return n
+}
+
+// check сверяет хранилище с моделью и проверяет инварианты после шага
+func (h *harness) check() {
+	for id, u := range h.users {
+		got, err := h.repo.GetUserByID(h.ctx, id)
+		if err != nil {
+			h.fatalf("load user %s: %v", id, err)
+		}
+		if got.IsActive != u.active {
+			h.fatalf("user %s is_active %t, model %t", id, got.IsActive, u.active)
+		}
+	}
+	for id, pr := range h.prs {
+		got := h.load(id)
+		reviewers := got.Reviewers
+		// инварианты по хранилищу
+		if slices.Contains(reviewers, pr.author) {
+			h.fatalf("author %s reviews own %s", pr.author, id)
+		}
+		if len(slices.Compact(slices.Sorted(slices.Values(reviewers)))) != len(reviewers) {
+			h.fatalf("%s has duplicate reviewers %v", id, reviewers)
+		}
+		count := invTeams[h.users[pr.author].team].reviewers
+		if len(reviewers) > count {
+			h.fatalf("%s has %d reviewers, allowed %d", id, len(reviewers), count)
+		}
+		if got.PullRequest.Status == "OPEN" && got.PullRequest.NeedMoreReviewers != (len(reviewers) < count) {
+			h.fatalf("%s needMoreReviewers %t with %d of %d reviewers", id, got.PullRequest.NeedMoreReviewers, len(reviewers), count)
+		}
+		for _, r := range reviewers {
+			if !slices.Contains(h.stored[id], r) && !h.users[r].active {
+				h.fatalf("inactive %s assigned to %s", r, id)
+			}
+		}
+		if frozen, ok := h.frozen[id]; ok && (got.PullRequest.Status != "MERGED" || !sameSet(reviewers, frozen)) {
+			h.fatalf("merged %s changed: %s with %v, merged with %v", id, got.PullRequest.Status, reviewers, frozen)
+		}
+		// сверка с моделью
+		if (got.PullRequest.Status == "MERGED") != pr.merged || !sameSet(reviewers, pr.reviewers) || got.PullRequest.NeedMoreReviewers != pr.needMore {
+			h.fatalf("%s is %s with %v (needMore %t), model merged=%t with %v (needMore %t)",
+				id, got.PullRequest.Status, reviewers, got.PullRequest.NeedMoreReviewers, pr.merged, pr.reviewers, pr.needMore)
+		}
+		h.stored[id] = slices.Clone(reviewers)
+	}
+}
+
+// load читает PR с ревьюверами из хранилища
+func (h *harness) load(prID string) *repository.PRWithReviewers {
+	got, err := h.repo.GetPullRequestWithReviewers(h.ctx, prID)
+	if err != nil {
+		h.fatalf("load %s: %v", prID, err)
+	}
+	return got
+}
+
+func (h *harness) expectOK(err error) {
+	if err != nil {
+		h.fatalf("unexpected error: %v", err)
+	}
+}
+
+func (h *harness) expectErr(err, want error) {
+	if apperr.CodeOf(err) != apperr.CodeOf(want) {
+		h.fatalf("error %v, want %s", err, apperr.CodeOf(want))
+	}
+}
+
+func (h *harness) fatalf(format string, args ...any) {
+	h.t.Helper()
+	h.t.Fatalf("step %d (%s): %s", h.step, h.op, fmt.Sprintf(format, args...))
+}
+
+// sameSet сравнивает списки без учета порядка
+func sameSet(a, b []string) bool {
+	return slices.Equal(slices.Sorted(slices.Values(a)), slices.Sorted(slices.Values(b)))
+}
+
+func TestAssignmentInvariants(t *testing.T) {
+	seeds := 200
+	if testing.Short() {
+		seeds = 20
+	}
+	for seed := range uint64(seeds) {
+		t.Run(fmt.Sprintf("seed=%d", seed), func(t *testing.T) {
+			rnd := rand.New(rand.NewPCG(seed, seed)) //nolint:gosec // воспроизводимая последовательность операций
+			data := make([]byte, 3*invMaxSteps)
+			for i := range data {
+				data[i] = byte(rnd.UintN(256))
+			}
+			newHarness(t).run(data)
+		})
+	}
+}
+
+// FuzzAssignmentInvariants перебирает последовательности операций:
+// go test -run='^$' -fuzz=FuzzAssignmentInvariants ./internal/service/
+func FuzzAssignmentInvariants(f *testing.F) {
+	// create, reassign, merge, reassign после мержа
+	f.Add([]byte{0, 0, 0, 1, 0, 1, 2, 0, 0, 1, 0, 1})
+	// деактивация команды, create без кандидатов, активация с добором
+	f.Add([]byte{3, 1, 0, 3, 2, 0, 3, 3, 0, 0, 0, 0, 3, 1, 1, 3, 2, 1})
+	// PR_EXISTS, неизвестный автор, NOT_ASSIGNED
+	f.Add([]byte{0, 1, 4, 0, 1, 5, 0, 2, 12, 1, 1, 0})
+	// лимит открытых ревью и резервная команда
+	f.Add([]byte{0, 0, 0, 0, 1, 1, 0, 2, 2, 0, 3, 3, 0, 4, 0, 0, 5, 1, 1, 4, 1, 2, 5, 0})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		newHarness(t).run(data)
+	})
+}
@@ -0,0 +1,481 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"avito-test-quest/internal/apperr"
+	"avito-test-quest/internal/models"
+	"avito-test-quest/internal/repository"
+	"avito-test-quest/internal/repository/mocks"
+	"avito-test-quest/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreatePullRequest(t *testing.T) {
+	input := models.CreatePullRequestInput{PullRequestID: "pr-1", PullRequestName: "name-pr-1", AuthorID: "u1"}
+	// автор из команды backend, PR создан
+	created := func(r *mocks.MockRepositoryMockRecorder) {
+		r.GetUserByID(gomock.Any(), "u1").Return(user("u1", 1, service.RoleMember), nil)
+		r.CreatePullRequest(gomock.Any(), "pr-1", "name-pr-1", "u1").Return(openPR("pr-1", "u1"), nil)
+	}
+
+	tests := []struct {
+		name    string
+		ctx     func(t *testing.T) context.Context
+		setup   func(r *mocks.MockRepositoryMockRecorder)
+		want    *models.PullRequest
+		wantErr error
+	}{
+		{
+			name: "assigns two reviewers from author's team",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				created(r)
+				r.GetTeamSettings(gomock.Any(), int64(1)).Return(nil, nil)
+				r.GetActiveUsersWithLoad(gomock.Any(), int64(1)).Return(candidates(1, "u1", "u2", "u3"), nil)
+				r.AssignReviewer(gomock.Any(), "pr-1", "u2", false).Return(nil)
+				r.AssignReviewer(gomock.Any(), "pr-1", "u3", false).Return(nil)
+			},
+			want: &models.PullRequest{PullRequestID: "pr-1", PullRequestName: "name-pr-1", AuthorID: "u1", Status: "OPEN", AssignedReviewers: []string{"u2", "u3"}},
+		},
+		{
+			name: "skips candidates over max open reviews",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				created(r)
+				r.GetTeamSettings(gomock.Any(), int64(1)).Return(&repository.TeamSettingsModel{TeamID: 1, ReviewersCount: 1, MaxOpenReviews: 2}, nil)
+				busy := candidates(1, "u2", "u3")
+				busy[0].OpenReviews = 2
+				r.GetActiveUsersWithLoad(gomock.Any(), int64(1)).Return(busy, nil)
+				r.AssignReviewer(gomock.Any(), "pr-1", "u3", false).Return(nil)
+			},
+			want: &models.PullRequest{PullRequestID: "pr-1", PullRequestName: "name-pr-1", AuthorID: "u1", Status: "OPEN", AssignedReviewers: []string{"u3"}},
+		},
+		{
+			name: "tops up from fallback team",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				created(r)
+				r.GetTeamSettings(gomock.Any(), int64(1)).Return(nil, nil)
+				r.GetActiveUsersWithLoad(gomock.Any(), int64(1)).Return(candidates(1, "u1", "u2"), nil)
+				r.GetFallbackTeams(gomock.Any(), int64(1)).Return([]repository.TeamModel{*frontend}, nil)
+				r.GetTeamSettings(gomock.Any(), int64(2)).Return(nil, nil)
+				r.GetActiveUsersWithLoad(gomock.Any(), int64(2)).Return(candidates(2, "f1"), nil)
+				r.AssignReviewer(gomock.Any(), "pr-1", "u2", false).Return(nil)
+				r.AssignReviewer(gomock.Any(), "pr-1", "f1", true).Return(nil)
+			},
+			want: &models.PullRequest{
+				PullRequestID: "pr-1", PullRequestName: "name-pr-1", AuthorID: "u1", Status: "OPEN",
+				AssignedReviewers: []string{"u2", "f1"}, FallbackReviewers: []string{"f1"},
+			},
+		},
+		{
+			name: "marks PR when no candidates",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				created(r)
+				r.GetTeamSettings(gomock.Any(), int64(1)).Return(nil, nil)
+				r.GetActiveUsersWithLoad(gomock.Any(), int64(1)).Return(candidates(1, "u1"), nil)
+				r.GetFallbackTeams(gomock.Any(), int64(1)).Return(nil, nil)
+				r.SetNeedMoreReviewers(gomock.Any(), "pr-1", true).Return(nil)
+			},
+			want: &models.PullRequest{PullRequestID: "pr-1", PullRequestName: "name-pr-1", AuthorID: "u1", Status: "OPEN", NeedMoreReviewers: true},
+		},
+		{
+			name: "FORBIDDEN for member",
+			ctx:  func(t *testing.T) context.Context { return userCtx(t, "u1") },
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetUserByID(gomock.Any(), "u1").Return(user("u1", 1, service.RoleMember), nil)
+			},
+			wantErr: apperr.ErrForbidden,
+		},
+		{
+			name: "NOT_FOUND for unknown author",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetUserByID(gomock.Any(), "u1").Return(nil, repository.ErrNotFound)
+			},
+			wantErr: apperr.ErrNotFound,
+		},
+		{
+			name: "repository failure on author",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetUserByID(gomock.Any(), "u1").Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name: "PR_EXISTS",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetUserByID(gomock.Any(), "u1").Return(user("u1", 1, service.RoleMember), nil)
+				r.CreatePullRequest(gomock.Any(), "pr-1", "name-pr-1", "u1").Return(nil, repository.ErrAlreadyExists)
+			},
+			wantErr: apperr.ErrPRExists,
+		},
+		{
+			name: "repository failure on assign",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				created(r)
+				r.GetTeamSettings(gomock.Any(), int64(1)).Return(nil, nil)
+				r.GetActiveUsersWithLoad(gomock.Any(), int64(1)).Return(candidates(1, "u1", "u2", "u3"), nil)
+				r.AssignReviewer(gomock.Any(), "pr-1", "u2", false).Return(errDB)
+			},
+			wantErr: errDB,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, r := newService(t)
+			tt.setup(r)
+
+			got, err := svc.CreatePullRequest(tt.ctx(t), input)
+			if tt.wantErr != nil {
+				requireErr(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMergePullRequest(t *testing.T) {
+	input := models.MergePullRequestInput{PullRequestID: "pr-1"}
+	merged := &models.PullRequest{
+		PullRequestID: "pr-1", PullRequestName: "name-pr-1", AuthorID: "u1", Status: "MERGED",
+		AssignedReviewers: []string{"u2"}, MergedAt: ptr("2025-01-02T03:04:05Z"),
+	}
+	withReviewers := &repository.PRWithReviewers{PullRequest: mergedPR("pr-1", "u1"), Reviewers: []string{"u2"}}
+
+	tests := []struct {
+		name    string
+		ctx     func(t *testing.T) context.Context
+		setup   func(r *mocks.MockRepositoryMockRecorder)
+		want    *models.PullRequest
+		wantErr error
+	}{
+		{
+			name: "merges open PR",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.LockPullRequest(gomock.Any(), "pr-1").Return(openPR("pr-1", "u1"), nil)
+				r.MergePullRequest(gomock.Any(), "pr-1").Return(mergedPR("pr-1", "u1"), nil)
+				r.GetPullRequestWithReviewers(gomock.Any(), "pr-1").Return(withReviewers, nil)
+			},
+			want: merged,
+		},
+		{
+			name: "idempotent for merged PR",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.LockPullRequest(gomock.Any(), "pr-1").Return(mergedPR("pr-1", "u1"), nil)
+				r.GetPullRequestWithReviewers(gomock.Any(), "pr-1").Return(withReviewers, nil)
+			},
+			want: merged,
+		},
+		{
+			name: "FORBIDDEN for team lead",
+			ctx:  func(t *testing.T) context.Context { return userCtx(t, "lead") },
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetUserByID(gomock.Any(), "lead").Return(user("lead", 1, service.RoleTeamLead), nil)
+			},
+			wantErr: apperr.ErrForbidden,
+		},
+		{
+			name: "NOT_FOUND",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.LockPullRequest(gomock.Any(), "pr-1").Return(nil, repository.ErrNotFound)
+			},
+			wantErr: apperr.ErrNotFound,
+		},
+		{
+			name: "repository failure on lock",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.LockPullRequest(gomock.Any(), "pr-1").Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name: "repository failure on merge",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.LockPullRequest(gomock.Any(), "pr-1").Return(openPR("pr-1", "u1"), nil)
+				r.MergePullRequest(gomock.Any(), "pr-1").Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, r := newService(t)
+			tt.setup(r)
+
+			got, err := svc.MergePullRequest(tt.ctx(t), input)
+			if tt.wantErr != nil {
+				requireErr(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReassignReviewer(t *testing.T) {
+	reason := ptr("vacation")
+	input := models.ReassignReviewerInput{PullRequestID: "pr-1", OldReviewerID: "u2", Reason: reason}
+	// PR автора u1 с ревьюверами u2 и u3
+	locked := func(r *mocks.MockRepositoryMockRecorder) {
+		r.LockPullRequest(gomock.Any(), "pr-1").Return(openPR("pr-1", "u1"), nil)
+		r.GetReviewersByPRID(gomock.Any(), "pr-1").Return([]string{"u2", "u3"}, nil)
+		r.GetUserByID(gomock.Any(), "u2").Return(user("u2", 1, service.RoleMember), nil)
+	}
+
+	tests := []struct {
+		name    string
+		ctx     func(t *testing.T) context.Context
+		setup   func(r *mocks.MockRepositoryMockRecorder)
+		want    *models.ReassignReviewerOutput
+		wantErr error
+	}{
+		{
+			name: "admin replaces reviewer from the same team",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				locked(r)
+				r.GetTeamSettings(gomock.Any(), int64(1)).Return(nil, nil)
+				r.GetActiveUsersWithLoad(gomock.Any(), int64(1)).Return(candidates(1, "u1", "u2", "u3", "u4"), nil)
+				r.ReplaceReviewer(gomock.Any(), "pr-1", "u2", "u4", false, reason).Return(nil)
+				r.GetPullRequestWithReviewers(gomock.Any(), "pr-1").Return(&repository.PRWithReviewers{
+					PullRequest: openPR("pr-1", "u1"), Reviewers: []string{"u3", "u4"},
+				}, nil)
+			},
+			want: &models.ReassignReviewerOutput{
+				PR: &models.PullRequest{
+					PullRequestID: "pr-1", PullRequestName: "name-pr-1", AuthorID: "u1", Status: "OPEN",
+					AssignedReviewers: []string{"u3", "u4"},
+				},
+				ReplacedBy: "u4",
+			},
+		},
+		{
+			name: "reviewer replaces self from fallback team",
+			ctx:  func(t *testing.T) context.Context { return userCtx(t, "u2") },
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetUserByID(gomock.Any(), "u2").Return(user("u2", 1, service.RoleMember), nil)
+				locked(r)
+				r.GetTeamSettings(gomock.Any(), int64(1)).Return(nil, nil)
+				r.GetActiveUsersWithLoad(gomock.Any(), int64(1)).Return(candidates(1, "u1", "u2", "u3"), nil)
+				r.GetFallbackTeams(gomock.Any(), int64(1)).Return([]repository.TeamModel{*frontend}, nil)
+				r.GetTeamSettings(gomock.Any(), int64(2)).Return(nil, nil)
+				r.GetActiveUsersWithLoad(gomock.Any(), int64(2)).Return(candidates(2, "f1"), nil)
+				r.ReplaceReviewer(gomock.Any(), "pr-1", "u2", "f1", true, reason).Return(nil)
+				r.GetPullRequestWithReviewers(gomock.Any(), "pr-1").Return(&repository.PRWithReviewers{
+					PullRequest: openPR("pr-1", "u1"), Reviewers: []string{"u3", "f1"}, FallbackReviewers: []string{"f1"},
+				}, nil)
+			},
+			want: &models.ReassignReviewerOutput{
+				PR: &models.PullRequest{
+					PullRequestID: "pr-1", PullRequestName: "name-pr-1", AuthorID: "u1", Status: "OPEN",
+					AssignedReviewers: []string{"u3", "f1"}, FallbackReviewers: []string{"f1"},
+				},
+				ReplacedBy: "f1",
+			},
+		},
+		{
+			name: "PR_MERGED",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.LockPullRequest(gomock.Any(), "pr-1").Return(mergedPR("pr-1", "u1"), nil)
+			},
+			wantErr: apperr.ErrPRMerged,
+		},
+		{
+			name: "NOT_ASSIGNED",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.LockPullRequest(gomock.Any(), "pr-1").Return(openPR("pr-1", "u1"), nil)
+				r.GetReviewersByPRID(gomock.Any(), "pr-1").Return([]string{"u3"}, nil)
+			},
+			wantErr: apperr.ErrNotAssigned,
+		},
+		{
+			name: "NO_CANDIDATE",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				locked(r)
+				r.GetTeamSettings(gomock.Any(), int64(1)).Return(nil, nil)
+				r.GetActiveUsersWithLoad(gomock.Any(), int64(1)).Return(candidates(1, "u1", "u2", "u3"), nil)
+				r.GetFallbackTeams(gomock.Any(), int64(1)).Return(nil, nil)
+			},
+			wantErr: apperr.ErrNoCandidate,
+		},
+		{
+			name: "NOT_FOUND",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.LockPullRequest(gomock.Any(), "pr-1").Return(nil, repository.ErrNotFound)
+			},
+			wantErr: apperr.ErrNotFound,
+		},
+		{
+			name: "repository failure on lock",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.LockPullRequest(gomock.Any(), "pr-1").Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name: "FORBIDDEN for another member",
+			ctx:  func(t *testing.T) context.Context { return userCtx(t, "u5") },
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetUserByID(gomock.Any(), "u5").Return(user("u5", 1, service.RoleMember), nil)
+				locked(r)
+			},
+			wantErr: apperr.ErrForbidden,
+		},
+		{
+			name: "FORBIDDEN for lead of author's team on reviewer from another team",
+			ctx:  func(t *testing.T) context.Context { return userCtx(t, "lead") },
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetUserByID(gomock.Any(), "lead").Return(user("lead", 1, service.RoleTeamLead), nil)
+				r.LockPullRequest(gomock.Any(), "pr-1").Return(openPR("pr-1", "u1"), nil)
+				r.GetReviewersByPRID(gomock.Any(), "pr-1").Return([]string{"u2", "u3"}, nil)
+				r.GetUserByID(gomock.Any(), "u2").Return(user("u2", 2, service.RoleMember), nil)
+			},
+			wantErr: apperr.ErrForbidden,
+		},
+		{
+			name: "lead replaces own member on another team's PR",
+			ctx:  func(t *testing.T) context.Context { return userCtx(t, "lead") },
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetUserByID(gomock.Any(), "lead").Return(user("lead", 1, service.RoleTeamLead), nil)
+				r.LockPullRequest(gomock.Any(), "pr-1").Return(openPR("pr-1", "f1"), nil)
+				r.GetReviewersByPRID(gomock.Any(), "pr-1").Return([]string{"u2", "u3"}, nil)
+				r.GetUserByID(gomock.Any(), "u2").Return(user("u2", 1, service.RoleMember), nil)
+				r.GetTeamSettings(gomock.Any(), int64(1)).Return(nil, nil)
+				r.GetActiveUsersWithLoad(gomock.Any(), int64(1)).Return(candidates(1, "u2", "u3", "u4"), nil)
+				r.ReplaceReviewer(gomock.Any(), "pr-1", "u2", "u4", false, reason).Return(nil)
+				r.GetPullRequestWithReviewers(gomock.Any(), "pr-1").Return(&repository.PRWithReviewers{
+					PullRequest: openPR("pr-1", "f1"), Reviewers: []string{"u3", "u4"},
+				}, nil)
+			},
+			want: &models.ReassignReviewerOutput{
+				PR: &models.PullRequest{
+					PullRequestID: "pr-1", PullRequestName: "name-pr-1", AuthorID: "f1", Status: "OPEN",
+					AssignedReviewers: []string{"u3", "u4"},
+				},
+				ReplacedBy: "u4",
+			},
+		},
+		{
+			name:    "UNAUTHORIZED without principal",
+			ctx:     baseCtx,
+			setup:   func(*mocks.MockRepositoryMockRecorder) {},
+			wantErr: apperr.ErrUnauthorized,
+		},
+		{
+			name: "repository failure on replace",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				locked(r)
+				r.GetTeamSettings(gomock.Any(), int64(1)).Return(nil, nil)
+				r.GetActiveUsersWithLoad(gomock.Any(), int64(1)).Return(candidates(1, "u4"), nil)
+				r.ReplaceReviewer(gomock.Any(), "pr-1", "u2", "u4", false, reason).Return(errDB)
+			},
+			wantErr: errDB,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, r := newService(t)
+			tt.setup(r)
+
+			got, err := svc.ReassignReviewer(tt.ctx(t), input)
+			if tt.wantErr != nil {
+				requireErr(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetPullRequestHistory(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     func(t *testing.T) context.Context
+		setup   func(r *mocks.MockRepositoryMockRecorder)
+		want    *models.PullRequestHistory
+		wantErr error
+	}{
+		{
+			name: "returns assignments in order",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.PullRequestExists(gomock.Any(), "pr-1").Return(true, nil)
+				r.GetAssignmentHistory(gomock.Any(), "pr-1").Return([]repository.ReviewerAssignmentHistoryModel{
+					{PullRequestID: "pr-1", NewReviewerUserID: "u2", ReassignedAt: mergedAt},
+					{PullRequestID: "pr-1", OldReviewerUserID: ptr("u2"), NewReviewerUserID: "u4", ReassignedAt: mergedAt.Add(time.Hour), Reason: ptr("vacation")},
+				}, nil)
+			},
+			want: &models.PullRequestHistory{PullRequestID: "pr-1", History: []models.ReviewerAssignmentEvent{
+				{NewUserID: "u2", AssignedAt: "2025-01-02T03:04:05Z"},
+				{OldUserID: ptr("u2"), NewUserID: "u4", Reason: ptr("vacation"), AssignedAt: "2025-01-02T04:04:05Z"},
+			}},
+		},
+		{
+			name:    "UNAUTHORIZED without principal",
+			ctx:     baseCtx,
+			setup:   func(*mocks.MockRepositoryMockRecorder) {},
+			wantErr: apperr.ErrUnauthorized,
+		},
+		{
+			name: "NOT_FOUND",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.PullRequestExists(gomock.Any(), "pr-1").Return(false, nil)
+			},
+			wantErr: apperr.ErrNotFound,
+		},
+		{
+			name: "repository failure on history",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.PullRequestExists(gomock.Any(), "pr-1").Return(true, nil)
+				r.GetAssignmentHistory(gomock.Any(), "pr-1").Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, r := newService(t)
+			tt.setup(r)
+
+			got, err := svc.GetPullRequestHistory(tt.ctx(t), "pr-1")
+			if tt.wantErr != nil {
+				requireErr(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"avito-test-quest/internal/models"
+	"avito-test-quest/internal/repository"
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"sync"
+)
+
+// ReviewerSelector стратегия выбора ревьюверов из списка кандидатов
+type ReviewerSelector interface {
+	// Select выбирает до n ревьюверов из candidates и возвращает их user_id
+	// Кандидаты уже отфильтрованы: автор и назначенные ревьюверы исключены
+	Select(ctx context.Context, candidates []repository.ReviewerCandidate, n int) ([]string, error)
+}
+
+// NewReviewerSelector создает стратегию выбора ревьюверов по имени
+func NewReviewerSelector(strategy string) (ReviewerSelector, error) {
+	switch strategy {
+	case models.StrategyRandom, "":
+		return NewRandomSelector(), nil
+	case models.StrategyRoundRobin:
+		return NewRoundRobinSelector(), nil
+	case models.StrategyLeastLoaded:
+		return NewLeastLoadedSelector(), nil
+	default:
+		return nil, fmt.Errorf("unknown reviewer selection strategy: %q", strategy)
+	}
+}
+
+// ==================== Random ====================
+
+// RandomSelector выбирает ревьюверов случайно
+type RandomSelector struct{}
+
+// NewRandomSelector создает новый экземпляр RandomSelector
+func NewRandomSelector() *RandomSelector {
+	return &RandomSelector{}
+}
+
+// Select выбирает до n случайных кандидатов
+func (s *RandomSelector) Select(_ context.Context, candidates []repository.ReviewerCandidate, n int) ([]string, error) {
+	ids := userIDs(shuffled(candidates))
+
+	return limit(ids, n), nil
+}
+
+// ==================== Round Robin ====================
+
+// RoundRobinSelector выбирает тех, кого дольше всего не назначали
+type RoundRobinSelector struct {
+	mu         sync.Mutex
+	seq        uint64
+	lastPicked map[string]uint64
+}
+
+// NewRoundRobinSelector создает новый экземпляр RoundRobinSelector
+func NewRoundRobinSelector() *RoundRobinSelector {
+	return &RoundRobinSelector{lastPicked: make(map[string]uint64)}
+}
+
+// Select выбирает до n кандидатов по очереди
+func (s *RoundRobinSelector) Select(_ context.Context, candidates []repository.ReviewerCandidate, n int) ([]string, error) {
+	ids := userIDs(candidates)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// сначала те, кого не назначали дольше всех, при равенстве — по user_id
+	sort.SliceStable(ids, func(i, j int) bool {
+		pi, pj := s.lastPicked[ids[i]], s.lastPicked[ids[j]]
+		if pi != pj {
+			return pi < pj
+		}
+		return ids[i] < ids[j]
+	})
+	chosen := limit(ids, n)
+	for _, id := range chosen {
+		s.seq++
+		s.lastPicked[id] = s.seq
+	}
+
+	return chosen, nil
+}
+
+// ==================== Least Loaded ====================
+
+// LeastLoadedSelector выбирает наименее загруженных ревьюверов
+type LeastLoadedSelector struct{}
+
+// NewLeastLoadedSelector создает новый экземпляр LeastLoadedSelector
+func NewLeastLoadedSelector() *LeastLoadedSelector {
+	return &LeastLoadedSelector{}
+}
+
+// Select выбирает до n кандидатов с наименьшим числом открытых ревью
+// При равной загрузке порядок случайный
+func (s *LeastLoadedSelector) Select(_ context.Context, candidates []repository.ReviewerCandidate, n int) ([]string, error) {
+	// перемешиваем до стабильной сортировки, чтобы ничьи разрешались случайно
+	pool := shuffled(candidates)
+	sort.SliceStable(pool, func(i, j int) bool {
+		return pool[i].OpenReviews < pool[j].OpenReviews
+	})
+
+	return limit(userIDs(pool), n), nil
+}
+
+// ==================== Helpers ====================
+
+// shuffled возвращает перемешанную копию списка кандидатов
+func shuffled(candidates []repository.ReviewerCandidate) []repository.ReviewerCandidate {
+	res := make([]repository.ReviewerCandidate, len(candidates))
+	copy(res, candidates)
+	rand.Shuffle(len(res), func(i, j int) { //nolint:gosec // криптостойкость для выбора ревьювера не нужна
+		res[i], res[j] = res[j], res[i]
+	})
+	return res
+}
+
+// userIDs возвращает список user_id кандидатов
+func userIDs(candidates []repository.ReviewerCandidate) []string {
+	ids := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		ids = append(ids, c.UserID)
+	}
+	return ids
+}
+
+// limit обрезает список до n элементов
+func limit(ids []string, n int) []string {
+	if n < 0 {
+		n = 0
+	}
+	if len(ids) > n {
+		return ids[:n]
+	}
+	return ids
+}
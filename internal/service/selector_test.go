@@ -0,0 +1,247 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"avito-test-quest/internal/models"
+	"avito-test-quest/internal/repository"
+	"avito-test-quest/internal/repository/mocks"
+	"avito-test-quest/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// withLoad кандидаты с заданным числом открытых ревью
+func withLoad(load map[string]int) []repository.ReviewerCandidate {
+	res := make([]repository.ReviewerCandidate, 0, len(load))
+	for id, n := range load {
+		c := repository.ReviewerCandidate{UserModel: *user(id, 1, service.RoleMember), OpenReviews: n}
+		res = append(res, c)
+	}
+	return res
+}
+
+// selectCall аргументы одного вызова Select
+type selectCall struct {
+	candidates []repository.ReviewerCandidate
+	n          int
+}
+
+func TestNewReviewerSelector(t *testing.T) {
+	tests := []struct {
+		strategy string
+		want     service.ReviewerSelector
+		wantErr  bool
+	}{
+		{strategy: "", want: &service.RandomSelector{}},
+		{strategy: models.StrategyRandom, want: &service.RandomSelector{}},
+		{strategy: models.StrategyRoundRobin, want: service.NewRoundRobinSelector()},
+		{strategy: models.StrategyLeastLoaded, want: &service.LeastLoadedSelector{}},
+		{strategy: "fifo", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			got, err := service.NewReviewerSelector(tt.strategy)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.IsType(t, tt.want, got)
+		})
+	}
+}
+
+func TestRandomSelector(t *testing.T) {
+	tests := []struct {
+		name       string
+		candidates []repository.ReviewerCandidate
+		n          int
+		wantLen    int
+	}{
+		{name: "picks n of many", candidates: candidates(1, "u1", "u2", "u3", "u4"), n: 2, wantLen: 2},
+		{name: "returns all when fewer than n", candidates: candidates(1, "u1"), n: 2, wantLen: 1},
+		{name: "no candidates", n: 2, wantLen: 0},
+		{name: "zero requested", candidates: candidates(1, "u1", "u2"), n: 0, wantLen: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowed := map[string]struct{}{}
+			for _, c := range tt.candidates {
+				allowed[c.UserID] = struct{}{}
+			}
+			// выбор случайный, поэтому свойства проверяются на нескольких прогонах
+			for range 50 {
+				got, err := service.NewRandomSelector().Select(context.Background(), tt.candidates, tt.n)
+				require.NoError(t, err)
+				require.Len(t, got, tt.wantLen)
+				seen := map[string]struct{}{}
+				for _, id := range got {
+					require.Contains(t, allowed, id)
+					require.NotContains(t, seen, id, "reviewer picked twice")
+					seen[id] = struct{}{}
+				}
+			}
+		})
+	}
+}
+
+func TestRoundRobinSelector(t *testing.T) {
+	tests := []struct {
+		name  string
+		calls []selectCall
+		want  [][]string
+	}{
+		{
+			name: "rotates one at a time",
+			calls: []selectCall{
+				{candidates(1, "u1", "u2", "u3"), 1},
+				{candidates(1, "u1", "u2", "u3"), 1},
+				{candidates(1, "u1", "u2", "u3"), 1},
+				{candidates(1, "u1", "u2", "u3"), 1},
+			},
+			want: [][]string{{"u1"}, {"u2"}, {"u3"}, {"u1"}},
+		},
+		{
+			name: "rotates pairs across calls",
+			calls: []selectCall{
+				{candidates(1, "u1", "u2", "u3"), 2},
+				{candidates(1, "u1", "u2", "u3"), 2},
+				{candidates(1, "u1", "u2", "u3"), 2},
+			},
+			want: [][]string{{"u1", "u2"}, {"u3", "u1"}, {"u2", "u3"}},
+		},
+		{
+			name: "new candidate goes first",
+			calls: []selectCall{
+				{candidates(1, "u1", "u2"), 1},
+				{candidates(1, "u1", "u2"), 1},
+				{candidates(1, "u1", "u2", "u3"), 1},
+				{candidates(1, "u1", "u2", "u3"), 1},
+			},
+			want: [][]string{{"u1"}, {"u2"}, {"u3"}, {"u1"}},
+		},
+		{
+			name: "excluded candidate keeps its turn",
+			calls: []selectCall{
+				{candidates(1, "u1", "u2", "u3"), 1},
+				{candidates(1, "u1", "u3"), 1},
+				{candidates(1, "u1", "u2", "u3"), 1},
+			},
+			want: [][]string{{"u1"}, {"u3"}, {"u2"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sel := service.NewRoundRobinSelector()
+			for i, call := range tt.calls {
+				got, err := sel.Select(context.Background(), call.candidates, call.n)
+				require.NoError(t, err)
+				assert.Equal(t, tt.want[i], got, "call %d", i)
+			}
+		})
+	}
+}
+
+func TestLeastLoadedSelector(t *testing.T) {
+	tests := []struct {
+		name       string
+		candidates []repository.ReviewerCandidate
+		n          int
+		// want допустимые наборы: при равной загрузке выбор случайный
+		want [][]string
+	}{
+		{
+			name:       "picks least loaded in order",
+			candidates: withLoad(map[string]int{"u1": 3, "u2": 0, "u3": 1, "u4": 5}),
+			n:          2,
+			want:       [][]string{{"u2", "u3"}},
+		},
+		{
+			name:       "breaks ties among equally loaded",
+			candidates: withLoad(map[string]int{"u1": 2, "u2": 0, "u3": 0}),
+			n:          1,
+			want:       [][]string{{"u2"}, {"u3"}},
+		},
+		{
+			name:       "returns all sorted when fewer than n",
+			candidates: withLoad(map[string]int{"u1": 4, "u2": 1}),
+			n:          3,
+			want:       [][]string{{"u2", "u1"}},
+		},
+		{
+			name: "no candidates",
+			n:    2,
+			want: [][]string{{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 20 {
+				got, err := service.NewLeastLoadedSelector().Select(context.Background(), tt.candidates, tt.n)
+				require.NoError(t, err)
+				if got == nil {
+					got = []string{}
+				}
+				assert.Contains(t, tt.want, got)
+			}
+		})
+	}
+}
+
+// TestRandomSelectorExcludesAuthor автор PR не попадает в ревьюверы при случайном выборе
+func TestRandomSelectorExcludesAuthor(t *testing.T) {
+	repo := mocks.NewMockRepository(gomock.NewController(t))
+	repo.EXPECT().WithTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, fn func(repository.Repository) error) error {
+			return fn(repo)
+		}).AnyTimes()
+	r := repo.EXPECT()
+	r.GetUserByID(gomock.Any(), "u1").Return(user("u1", 1, service.RoleMember), nil).AnyTimes()
+	r.CreatePullRequest(gomock.Any(), "pr-1", "name-pr-1", "u1").Return(openPR("pr-1", "u1"), nil).AnyTimes()
+	r.GetTeamSettings(gomock.Any(), int64(1)).Return(nil, nil).AnyTimes()
+	r.GetActiveUsersWithLoad(gomock.Any(), int64(1)).Return(candidates(1, "u1", "u2", "u3", "u4"), nil).AnyTimes()
+	r.AssignReviewer(gomock.Any(), "pr-1", gomock.Any(), false).Return(nil).AnyTimes()
+
+	svc := service.NewPrService(repo, service.NewRandomSelector(), nil)
+	input := models.CreatePullRequestInput{PullRequestID: "pr-1", PullRequestName: "name-pr-1", AuthorID: "u1"}
+	for range 50 {
+		pr, err := svc.CreatePullRequest(adminCtx(t), input)
+		require.NoError(t, err)
+		require.Len(t, pr.AssignedReviewers, 2)
+		require.NotContains(t, pr.AssignedReviewers, "u1")
+	}
+}
+
+// TestRoundRobinSharedWithDefault команды со стратегией по умолчанию и с явным round_robin
+// продвигают одну очередь
+func TestRoundRobinSharedWithDefault(t *testing.T) {
+	repo := mocks.NewMockRepository(gomock.NewController(t))
+	repo.EXPECT().WithTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, fn func(repository.Repository) error) error {
+			return fn(repo)
+		}).AnyTimes()
+	r := repo.EXPECT()
+	r.GetUserByID(gomock.Any(), "u1").Return(user("u1", 1, service.RoleMember), nil).Times(2)
+	r.CreatePullRequest(gomock.Any(), "pr-1", "name-pr-1", "u1").Return(openPR("pr-1", "u1"), nil)
+	r.CreatePullRequest(gomock.Any(), "pr-2", "name-pr-2", "u1").Return(openPR("pr-2", "u1"), nil)
+	// первый PR создается по стратегии по умолчанию, второй — после явной настройки round_robin
+	gomock.InOrder(
+		r.GetTeamSettings(gomock.Any(), int64(1)).Return(&repository.TeamSettingsModel{TeamID: 1, ReviewersCount: 1}, nil),
+		r.GetTeamSettings(gomock.Any(), int64(1)).Return(&repository.TeamSettingsModel{TeamID: 1, ReviewersCount: 1, Strategy: ptr(models.StrategyRoundRobin)}, nil),
+	)
+	r.GetActiveUsersWithLoad(gomock.Any(), int64(1)).Return(candidates(1, "u2", "u3"), nil).Times(2)
+	r.AssignReviewer(gomock.Any(), "pr-1", "u2", false).Return(nil)
+	r.AssignReviewer(gomock.Any(), "pr-2", "u3", false).Return(nil)
+
+	svc := service.NewPrService(repo, service.NewRoundRobinSelector(), nil)
+	first, err := svc.CreatePullRequest(adminCtx(t), models.CreatePullRequestInput{PullRequestID: "pr-1", PullRequestName: "name-pr-1", AuthorID: "u1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u2"}, first.AssignedReviewers)
+	second, err := svc.CreatePullRequest(adminCtx(t), models.CreatePullRequestInput{PullRequestID: "pr-2", PullRequestName: "name-pr-2", AuthorID: "u1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u3"}, second.AssignedReviewers)
+}
@@ -1,24 +1,53 @@
 package service
 
 import (
+	"avito-test-quest/internal/apperr"
 	"avito-test-quest/internal/logger"
+	"avito-test-quest/internal/metrics"
 	"avito-test-quest/internal/models"
 	"avito-test-quest/internal/repository"
 	"context"
 	"errors"
+	"slices"
 	"time"
 
 	"go.uber.org/zap"
 )
 
+// defaultReviewersCount количество ревьюверов на PR, если команда не задала своё
+const defaultReviewersCount = 2
+
 type PrService struct {
-	repo repository.Repository
+	repo      repository.Repository
+	selector  ReviewerSelector
+	selectors map[string]ReviewerSelector
+	metrics   *metrics.Metrics
 }
 
 // NewPrService создает новый экземпляр PrService
-func NewPrService(repo repository.Repository) Service {
+// selector используется для команд, не задавших свою стратегию; m может быть nil
+func NewPrService(repo repository.Repository, selector ReviewerSelector, m *metrics.Metrics) Service {
+	selectors := map[string]ReviewerSelector{
+		models.StrategyRandom:      NewRandomSelector(),
+		models.StrategyRoundRobin:  NewRoundRobinSelector(),
+		models.StrategyLeastLoaded: NewLeastLoadedSelector(),
+	}
+	// стратегия по умолчанию и та же стратегия, заданная командой явно, делят один экземпляр:
+	// иначе у round_robin было бы две независимые очереди
+	switch selector.(type) {
+	case *RandomSelector:
+		selectors[models.StrategyRandom] = selector
+	case *RoundRobinSelector:
+		selectors[models.StrategyRoundRobin] = selector
+	case *LeastLoadedSelector:
+		selectors[models.StrategyLeastLoaded] = selector
+	}
+
 	return &PrService{
-		repo: repo,
+		repo:      repo,
+		selector:  selector,
+		selectors: selectors,
+		metrics:   m,
 	}
 }
 
@@ -26,45 +55,75 @@ func NewPrService(repo repository.Repository) Service {
 
 func (s *PrService) CreateTeam(ctx context.Context, input models.CreateTeamInput) (*models.Team, error) {
 	log := logger.GetOrCreateLoggerFromCtx(ctx)
-	exists, err := s.repo.TeamExists(ctx, input.TeamName)
-	if err != nil {
-		log.Error(ctx, "failed to check team exists", zap.Error(err))
-		return nil, err
-	}
-	if exists {
-		return nil, errors.New("TEAM_EXISTS")
-	}
-	// создаем команду в БД
-	teamModel, err := s.repo.CreateTeam(ctx, input.TeamName)
+	a, err := currentActor(ctx, s.repo)
 	if err != nil {
-		log.Error(ctx, "failed to create team", zap.Error(err))
 		return nil, err
 	}
-	// создаем или обновляем пользователей
-	for _, m := range input.Members {
-		ue, err := s.repo.UserExists(ctx, m.UserID)
+	var teamModel *repository.TeamModel
+	var users []repository.UserModel
+	var toppedUp []reviewerPick
+	// команда, участники и добор ревьюверов создаются в одной транзакции
+	err = s.repo.WithTx(ctx, func(repo repository.Repository) error {
+		exists, err := repo.TeamExists(ctx, input.TeamName)
 		if err != nil {
-			log.Error(ctx, "failed to check user exists", zap.Error(err))
-			return nil, err
+			log.Error(ctx, "failed to check team exists", zap.Error(err))
+			return err
 		}
-		if ue {
-			if _, err := s.repo.UpdateUser(ctx, m.UserID, m.Username, teamModel.ID, m.IsActive); err != nil {
-				log.Error(ctx, "failed to update user", zap.Error(err))
-				return nil, err
+		if exists {
+			return apperr.New(apperr.CodeTeamExists, "team_name already exists")
+		}
+		// создаем команду в БД
+		teamModel, err = repo.CreateTeam(ctx, input.TeamName)
+		if err != nil {
+			// параллельное создание команды с тем же именем
+			if errors.Is(err, repository.ErrAlreadyExists) {
+				return apperr.Wrap(err, apperr.CodeTeamExists, "team_name already exists")
 			}
-		} else {
-			if _, err := s.repo.CreateUser(ctx, m.UserID, m.Username, teamModel.ID, m.IsActive); err != nil {
-				log.Error(ctx, "failed to create user", zap.Error(err))
-				return nil, err
+			log.Error(ctx, "failed to create team", zap.Error(err))
+			return err
+		}
+		// создаем или обновляем пользователей
+		for _, m := range input.Members {
+			ue, err := repo.UserExists(ctx, m.UserID)
+			if err != nil {
+				log.Error(ctx, "failed to check user exists", zap.Error(err))
+				return err
+			}
+			if ue {
+				// перенос существующего пользователя меняет его команду и is_active в обход
+				// проверок setIsActive, поэтому доступен только админу
+				if a.role != RoleAdmin {
+					return apperr.New(apperr.CodeForbidden, "only admin can move existing user "+m.UserID)
+				}
+				if _, err := repo.UpdateUser(ctx, m.UserID, m.Username, teamModel.ID, m.IsActive); err != nil {
+					log.Error(ctx, "failed to update user", zap.Error(err))
+					return err
+				}
+			} else {
+				if _, err := repo.CreateUser(ctx, m.UserID, m.Username, teamModel.ID, m.IsActive); err != nil {
+					log.Error(ctx, "failed to create user", zap.Error(err))
+					return err
+				}
 			}
 		}
-	}
-	// получаем созданных пользователей
-	users, err := s.repo.GetUsersByTeamID(ctx, teamModel.ID)
+		// добираем ревьюверов на PR участников команды, которым их не хватало
+		toppedUp, err = s.topUpReviewers(ctx, repo, teamModel.ID)
+		if err != nil {
+			log.Error(ctx, "failed to top up reviewers", zap.Error(err))
+			return err
+		}
+		// получаем созданных пользователей
+		users, err = repo.GetUsersByTeamID(ctx, teamModel.ID)
+		if err != nil {
+			log.Error(ctx, "failed to fetch team users", zap.Error(err))
+			return err
+		}
+		return nil
+	})
 	if err != nil {
-		log.Error(ctx, "failed to fetch team users", zap.Error(err))
 		return nil, err
 	}
+	s.recordAssignments(metrics.OperationTopUp, toppedUp)
 	// формируем ответ
 	var members []models.TeamMember
 	for _, u := range users {
@@ -76,10 +135,17 @@ func (s *PrService) CreateTeam(ctx context.Context, input models.CreateTeamInput
 
 func (s *PrService) GetTeam(ctx context.Context, teamName string) (*models.Team, error) {
 	log := logger.GetOrCreateLoggerFromCtx(ctx)
+	if _, err := currentActor(ctx, s.repo); err != nil {
+		return nil, err
+	}
 	tm, err := s.repo.GetTeamByName(ctx, teamName)
-	if err != nil {
+	if errors.Is(err, repository.ErrNotFound) {
 		log.Info(ctx, "team not found", zap.String("team", teamName), zap.Error(err))
-		return nil, errors.New("NOT_FOUND")
+		return nil, apperr.Wrap(err, apperr.CodeNotFound, "team not found")
+	}
+	if err != nil {
+		log.Error(ctx, "failed to get team", zap.Error(err))
+		return nil, err
 	}
 	// получаем пользователей команды
 	users, err := s.repo.GetUsersByTeamID(ctx, tm.ID)
@@ -96,22 +162,290 @@ func (s *PrService) GetTeam(ctx context.Context, teamName string) (*models.Team,
 	return &models.Team{TeamName: tm.TeamName, Members: members}, nil
 }
 
+func (s *PrService) GetTeamSettings(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+	log := logger.GetOrCreateLoggerFromCtx(ctx)
+	if _, err := currentActor(ctx, s.repo); err != nil {
+		return nil, err
+	}
+	tm, err := s.repo.GetTeamByName(ctx, teamName)
+	if errors.Is(err, repository.ErrNotFound) {
+		log.Info(ctx, "team not found", zap.String("team", teamName), zap.Error(err))
+		return nil, apperr.Wrap(err, apperr.CodeNotFound, "team not found")
+	}
+	if err != nil {
+		log.Error(ctx, "failed to get team", zap.Error(err))
+		return nil, err
+	}
+	settings, err := s.teamSettings(ctx, s.repo, tm.ID)
+	if err != nil {
+		log.Error(ctx, "failed to get team settings", zap.Error(err))
+		return nil, err
+	}
+	fallbackTeams, err := s.repo.GetFallbackTeams(ctx, tm.ID)
+	if err != nil {
+		log.Error(ctx, "failed to get fallback teams", zap.Error(err))
+		return nil, err
+	}
+
+	return toTeamSettings(tm.TeamName, settings, fallbackTeams), nil
+}
+
+func (s *PrService) UpdateTeamSettings(ctx context.Context, input models.UpdateTeamSettingsInput) (*models.TeamSettings, error) {
+	log := logger.GetOrCreateLoggerFromCtx(ctx)
+	a, err := currentActor(ctx, s.repo)
+	if err != nil {
+		return nil, err
+	}
+	if a.role == RoleMember {
+		return nil, apperr.New(apperr.CodeForbidden, "admin or team lead role required")
+	}
+	var out *models.TeamSettings
+	// настройки и список резервных команд меняются атомарно
+	err = s.repo.WithTx(ctx, func(repo repository.Repository) error {
+		tm, err := repo.GetTeamByName(ctx, input.TeamName)
+		if errors.Is(err, repository.ErrNotFound) {
+			log.Info(ctx, "team not found", zap.String("team", input.TeamName), zap.Error(err))
+			return apperr.Wrap(err, apperr.CodeNotFound, "team not found")
+		}
+		if err != nil {
+			log.Error(ctx, "failed to get team", zap.Error(err))
+			return err
+		}
+		// тимлид меняет настройки только своей команды
+		if !a.canManageTeam(tm.ID) {
+			return apperr.New(apperr.CodeForbidden, "team is not led by the caller")
+		}
+		settings, err := s.teamSettings(ctx, repo, tm.ID)
+		if err != nil {
+			log.Error(ctx, "failed to get team settings", zap.Error(err))
+			return err
+		}
+		// применяем только переданные поля
+		if input.ReviewersCount != nil {
+			settings.ReviewersCount = *input.ReviewersCount
+		}
+		if input.Strategy != nil {
+			if *input.Strategy == "" {
+				settings.Strategy = nil
+			} else {
+				if _, ok := s.selectors[*input.Strategy]; !ok {
+					return apperr.New(apperr.CodeInvalidStrategy, "unknown reviewer selection strategy")
+				}
+				settings.Strategy = input.Strategy
+			}
+		}
+		if input.MaxOpenReviews != nil {
+			settings.MaxOpenReviews = *input.MaxOpenReviews
+		}
+		// проверяем резервные команды: существуют, не совпадают с самой командой и не повторяются
+		var fallbackIDs []int64
+		seen := map[string]struct{}{}
+		for _, name := range input.FallbackTeams {
+			if _, dup := seen[name]; dup || name == tm.TeamName {
+				return apperr.New(apperr.CodeInvalidFallbackTeam, "fallback team is duplicated or equal to the team itself")
+			}
+			seen[name] = struct{}{}
+			ft, err := repo.GetTeamByName(ctx, name)
+			if errors.Is(err, repository.ErrNotFound) {
+				log.Info(ctx, "fallback team not found", zap.String("team", name), zap.Error(err))
+				return apperr.Wrap(err, apperr.CodeInvalidFallbackTeam, "fallback team not found")
+			}
+			if err != nil {
+				log.Error(ctx, "failed to get team", zap.Error(err))
+				return err
+			}
+			fallbackIDs = append(fallbackIDs, ft.ID)
+		}
+		updated, err := repo.UpsertTeamSettings(ctx, *settings)
+		if err != nil {
+			log.Error(ctx, "failed to upsert team settings", zap.Error(err))
+			return err
+		}
+		if input.FallbackTeams != nil {
+			if err := repo.SetFallbackTeams(ctx, tm.ID, fallbackIDs); err != nil {
+				log.Error(ctx, "failed to set fallback teams", zap.Error(err))
+				return err
+			}
+		}
+		fallbackTeams, err := repo.GetFallbackTeams(ctx, tm.ID)
+		if err != nil {
+			log.Error(ctx, "failed to get fallback teams", zap.Error(err))
+			return err
+		}
+		out = toTeamSettings(tm.TeamName, updated, fallbackTeams)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *PrService) DeactivateTeamMembers(ctx context.Context, input models.DeactivateTeamMembersInput) (*models.DeactivateTeamMembersOutput, error) {
+	log := logger.GetOrCreateLoggerFromCtx(ctx)
+	a, err := currentActor(ctx, s.repo)
+	if err != nil {
+		return nil, err
+	}
+	if a.role == RoleMember {
+		return nil, apperr.New(apperr.CodeForbidden, "admin or team lead role required")
+	}
+	out := &models.DeactivateTeamMembersOutput{
+		TeamName:    input.TeamName,
+		Deactivated: []string{},
+		Reassigned:  []models.ReviewReassignment{},
+		NoCandidate: []models.UnreassignedReview{},
+	}
+	fallbackReassigned := 0
+	// деактивация и переназначение выполняются в одной транзакции набором пакетных запросов
+	err = s.repo.WithTx(ctx, func(repo repository.Repository) error {
+		tm, err := repo.GetTeamByName(ctx, input.TeamName)
+		if errors.Is(err, repository.ErrNotFound) {
+			log.Info(ctx, "team not found", zap.String("team", input.TeamName), zap.Error(err))
+			return apperr.Wrap(err, apperr.CodeNotFound, "team not found")
+		}
+		if err != nil {
+			log.Error(ctx, "failed to get team", zap.Error(err))
+			return err
+		}
+		// тимлид, как и в setIsActive, деактивирует только участников своей команды
+		if !a.canManageTeam(tm.ID) {
+			return apperr.New(apperr.CodeForbidden, "team is not led by the caller")
+		}
+		// все переданные пользователи должны быть участниками команды
+		users, err := repo.GetUsersByTeamID(ctx, tm.ID)
+		if err != nil {
+			log.Error(ctx, "failed to get users by team id", zap.Error(err))
+			return err
+		}
+		members := make(map[string]struct{}, len(users))
+		for _, u := range users {
+			members[u.UserID] = struct{}{}
+		}
+		for _, id := range input.UserIDs {
+			if _, ok := members[id]; !ok {
+				log.Info(ctx, "user is not a team member", zap.String("team", input.TeamName), zap.String("user", id))
+				return apperr.New(apperr.CodeNotFound, "user "+id+" is not a team member")
+			}
+		}
+		deactivated, err := repo.DeactivateTeamMembers(ctx, tm.ID, input.UserIDs, input.AllExcept)
+		if err != nil {
+			log.Error(ctx, "failed to deactivate team members", zap.Error(err))
+			return err
+		}
+		if len(deactivated) == 0 {
+			return nil
+		}
+		out.Deactivated = deactivated
+		// блокируем затронутые PR, подбираем замены и применяем их пакетно
+		reviews, err := repo.LockOpenReviews(ctx, deactivated)
+		if err != nil {
+			log.Error(ctx, "failed to lock open reviews", zap.Error(err))
+			return err
+		}
+		plan, err := s.planReplacements(ctx, repo, tm.ID, reviews, deactivated)
+		if err != nil {
+			log.Error(ctx, "failed to plan reviewer replacements", zap.Error(err))
+			return err
+		}
+		if err := repo.ApplyReviewerReplacements(ctx, plan, input.Reason); err != nil {
+			log.Error(ctx, "failed to apply reviewer replacements", zap.Error(err))
+			return err
+		}
+		for _, rp := range plan {
+			if rp.NewReviewerUserID == nil {
+				out.NoCandidate = append(out.NoCandidate, models.UnreassignedReview{PullRequestID: rp.PullRequestID, UserID: rp.OldReviewerUserID})
+				continue
+			}
+			out.Reassigned = append(out.Reassigned, models.ReviewReassignment{PullRequestID: rp.PullRequestID, OldUserID: rp.OldReviewerUserID, NewUserID: *rp.NewReviewerUserID})
+			if rp.IsFallback {
+				fallbackReassigned++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// метрики учитываем только после коммита: откаченная транзакция ничего не назначила
+	s.metrics.AddReassignments(metrics.OperationDeactivate, false, len(out.Reassigned)-fallbackReassigned)
+	s.metrics.AddReassignments(metrics.OperationDeactivate, true, fallbackReassigned)
+	s.metrics.AddNoCandidate(metrics.OperationDeactivate, len(out.NoCandidate))
+
+	return out, nil
+}
+
 // ==================== User Service Methods ====================
 
 func (s *PrService) SetIsActive(ctx context.Context, input models.SetIsActiveInput) (*models.User, error) {
 	log := logger.GetOrCreateLoggerFromCtx(ctx)
-	exists, err := s.repo.UserExists(ctx, input.UserID)
+	a, err := currentActor(ctx, s.repo)
 	if err != nil {
-		log.Error(ctx, "failed to check user exists", zap.Error(err))
 		return nil, err
 	}
-	if !exists {
-		return nil, errors.New("NOT_FOUND")
+	if a.role == RoleMember {
+		return nil, apperr.New(apperr.CodeForbidden, "admin or team lead role required")
+	}
+	target, err := s.repo.GetUserByID(ctx, input.UserID)
+	if errors.Is(err, repository.ErrNotFound) {
+		log.Info(ctx, "user not found", zap.String("user", input.UserID), zap.Error(err))
+		return nil, apperr.Wrap(err, apperr.CodeNotFound, "user not found")
+	}
+	if err != nil {
+		log.Error(ctx, "failed to get user", zap.Error(err))
+		return nil, err
+	}
+	// тимлид меняет активность только участников своей команды
+	if !a.canSetIsActive(target) {
+		return nil, apperr.New(apperr.CodeForbidden, "user is not a member of the caller's team")
+	}
+	// обновляем is_active и при активации добираем ревьюверов на PR команды в одной транзакции
+	var u *repository.UserModel
+	var toppedUp []reviewerPick
+	err = s.repo.WithTx(ctx, func(repo repository.Repository) error {
+		var err error
+		u, err = repo.SetIsActive(ctx, input.UserID, input.IsActive)
+		if err != nil {
+			log.Error(ctx, "failed to set is_active", zap.Error(err))
+			return err
+		}
+		if !u.IsActive {
+			return nil
+		}
+		toppedUp, err = s.topUpReviewers(ctx, repo, u.TeamID)
+		if err != nil {
+			log.Error(ctx, "failed to top up reviewers", zap.Error(err))
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.recordAssignments(metrics.OperationTopUp, toppedUp)
+	// получаем название команды
+	team, err := s.repo.GetTeamByID(ctx, u.TeamID)
+	if err != nil {
+		log.Error(ctx, "failed to get team for user", zap.Error(err))
+		return nil, err
+	}
+
+	return &models.User{UserID: u.UserID, Username: u.Username, TeamName: team.TeamName, IsActive: u.IsActive, Role: u.Role}, nil
+}
+
+func (s *PrService) SetUserRole(ctx context.Context, input models.SetRoleInput) (*models.User, error) {
+	log := logger.GetOrCreateLoggerFromCtx(ctx)
+	if err := requireAdmin(ctx, s.repo); err != nil {
+		return nil, err
+	}
+	u, err := s.repo.SetUserRole(ctx, input.UserID, input.Role)
+	if errors.Is(err, repository.ErrNotFound) {
+		log.Info(ctx, "user not found", zap.String("user", input.UserID), zap.Error(err))
+		return nil, apperr.Wrap(err, apperr.CodeNotFound, "user not found")
 	}
-	// обновляем is_active
-	u, err := s.repo.SetIsActive(ctx, input.UserID, input.IsActive)
 	if err != nil {
-		log.Error(ctx, "failed to set is_active", zap.Error(err))
+		log.Error(ctx, "failed to set user role", zap.Error(err))
 		return nil, err
 	}
 	// получаем название команды
@@ -121,18 +455,21 @@ func (s *PrService) SetIsActive(ctx context.Context, input models.SetIsActiveInp
 		return nil, err
 	}
 
-	return &models.User{UserID: u.UserID, Username: u.Username, TeamName: team.TeamName, IsActive: u.IsActive}, nil
+	return &models.User{UserID: u.UserID, Username: u.Username, TeamName: team.TeamName, IsActive: u.IsActive, Role: u.Role}, nil
 }
 
 func (s *PrService) GetUserReviews(ctx context.Context, userID string) (*models.UserReviewsOutput, error) {
 	log := logger.GetOrCreateLoggerFromCtx(ctx)
+	if _, err := currentActor(ctx, s.repo); err != nil {
+		return nil, err
+	}
 	exists, err := s.repo.UserExists(ctx, userID)
 	if err != nil {
 		log.Error(ctx, "failed to check user exists", zap.Error(err))
 		return nil, err
 	}
 	if !exists {
-		return nil, errors.New("NOT_FOUND")
+		return nil, apperr.New(apperr.CodeNotFound, "user not found")
 	}
 	// получаем PR, где пользователь назначен ревьювером
 	prs, err := s.repo.GetPRsByReviewerID(ctx, userID)
@@ -143,7 +480,7 @@ func (s *PrService) GetUserReviews(ctx context.Context, userID string) (*models.
 	// формируем ответ
 	var out []models.PullRequestShort
 	for _, p := range prs {
-		out = append(out, models.PullRequestShort{PullRequestID: p.PullRequestID, PullRequestName: p.PullRequestName, AuthorID: p.AuthorID, Status: p.Status})
+		out = append(out, models.PullRequestShort{PullRequestID: p.PullRequestID, PullRequestName: p.PullRequestName, AuthorID: p.AuthorID, Status: p.Status, NeedMoreReviewers: p.NeedMoreReviewers})
 	}
 
 	return &models.UserReviewsOutput{UserID: userID, PullRequests: out}, nil
@@ -153,180 +490,241 @@ func (s *PrService) GetUserReviews(ctx context.Context, userID string) (*models.
 
 func (s *PrService) CreatePullRequest(ctx context.Context, input models.CreatePullRequestInput) (*models.PullRequest, error) {
 	log := logger.GetOrCreateLoggerFromCtx(ctx)
-	author, err := s.repo.GetUserByID(ctx, input.AuthorID)
-	if err != nil {
-		log.Info(ctx, "author not found", zap.String("author", input.AuthorID), zap.Error(err))
-		return nil, errors.New("NOT_FOUND")
-	}
-	// проверяем, что PR с таким ID не существует
-	exists, err := s.repo.PullRequestExists(ctx, input.PullRequestID)
-	if err != nil {
-		log.Error(ctx, "failed to check pr exists", zap.Error(err))
-		return nil, err
-	}
-	if exists {
-		return nil, errors.New("PR_EXISTS")
-	}
-	// создаем PR
-	prModel, err := s.repo.CreatePullRequest(ctx, input.PullRequestID, input.PullRequestName, input.AuthorID)
-	if err != nil {
-		log.Error(ctx, "failed to create pr", zap.Error(err))
-		return nil, err
-	}
-	// выбираем до 2 ревьюверов из активных пользователей команды автора
-	candidates, err := s.repo.GetActiveUsersInTeam(ctx, author.TeamID)
-	if err != nil {
-		log.Error(ctx, "failed to get active users in team", zap.Error(err))
+	if err := requireAdmin(ctx, s.repo); err != nil {
 		return nil, err
 	}
-	var assigned []string
-	for _, c := range candidates {
-		if c.UserID == input.AuthorID {
-			continue
+	var out *models.PullRequest
+	var picks []reviewerPick
+	missing := 0
+	// PR, его ревьюверы и флаг нехватки создаются в одной транзакции
+	err := s.repo.WithTx(ctx, func(repo repository.Repository) error {
+		author, err := repo.GetUserByID(ctx, input.AuthorID)
+		if errors.Is(err, repository.ErrNotFound) {
+			log.Info(ctx, "author not found", zap.String("author", input.AuthorID), zap.Error(err))
+			return apperr.Wrap(err, apperr.CodeNotFound, "author not found")
 		}
-		if len(assigned) >= 2 {
-			break
+		if err != nil {
+			log.Error(ctx, "failed to get user", zap.Error(err))
+			return err
 		}
-		if err := s.repo.AssignReviewer(ctx, input.PullRequestID, c.UserID); err != nil {
-			log.Warn(ctx, "failed to assign reviewer", zap.String("user", c.UserID), zap.Error(err))
-			continue
+		// создаем PR: вставка с ON CONFLICT атомарно проверяет уникальность,
+		// а новая строка остается заблокированной до конца транзакции
+		prModel, err := repo.CreatePullRequest(ctx, input.PullRequestID, input.PullRequestName, input.AuthorID)
+		if err != nil {
+			if errors.Is(err, repository.ErrAlreadyExists) {
+				return apperr.Wrap(err, apperr.CodePRExists, "PR id already exists")
+			}
+			log.Error(ctx, "failed to create pr", zap.Error(err))
+			return err
 		}
-		assigned = append(assigned, c.UserID)
-	}
-	// формируем ответ
-	var createdAt *string
-	if !prModel.CreatedAt.IsZero() {
-		t := prModel.CreatedAt.UTC().Format(time.RFC3339)
-		createdAt = &t
-	}
-	var mergedAt *string
-	if prModel.MergedAt != nil {
-		t := prModel.MergedAt.UTC().Format(time.RFC3339)
-		mergedAt = &t
+		// выбираем ревьюверов из команды автора по настройкам команды, недостающих — из резервных команд
+		settings, err := s.teamSettings(ctx, repo, author.TeamID)
+		if err != nil {
+			log.Error(ctx, "failed to get team settings", zap.Error(err))
+			return err
+		}
+		picks, err = s.pickReviewers(ctx, repo, settings, map[string]struct{}{input.AuthorID: {}}, settings.ReviewersCount)
+		if err != nil {
+			log.Error(ctx, "failed to select reviewers", zap.Error(err))
+			return err
+		}
+		var assigned, fallback []string
+		for _, p := range picks {
+			if err := repo.AssignReviewer(ctx, input.PullRequestID, p.UserID, p.Fallback); err != nil {
+				log.Error(ctx, "failed to assign reviewer", zap.String("user", p.UserID), zap.Error(err))
+				return err
+			}
+			assigned = append(assigned, p.UserID)
+			if p.Fallback {
+				fallback = append(fallback, p.UserID)
+			}
+		}
+		// помечаем PR, если кандидатов не хватило: ревьюверы доберутся при появлении участников
+		if len(assigned) < settings.ReviewersCount {
+			if err := repo.SetNeedMoreReviewers(ctx, input.PullRequestID, true); err != nil {
+				log.Error(ctx, "failed to set need_more_reviewers", zap.Error(err))
+				return err
+			}
+			prModel.NeedMoreReviewers = true
+			missing = settings.ReviewersCount - len(assigned)
+		}
+		out = toPullRequest(prModel, assigned, fallback)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	s.recordAssignments(metrics.OperationCreate, picks)
+	s.metrics.AddNoCandidate(metrics.OperationCreate, missing)
 
-	return &models.PullRequest{PullRequestID: prModel.PullRequestID, PullRequestName: prModel.PullRequestName, AuthorID: prModel.AuthorID, Status: prModel.Status, AssignedReviewers: assigned, CreatedAt: createdAt, MergedAt: mergedAt}, nil
+	return out, nil
 }
 
 func (s *PrService) MergePullRequest(ctx context.Context, input models.MergePullRequestInput) (*models.PullRequest, error) {
 	log := logger.GetOrCreateLoggerFromCtx(ctx)
-	pr, err := s.repo.GetPullRequestByID(ctx, input.PullRequestID)
-	if err != nil {
-		log.Info(ctx, "pr not found", zap.String("pr", input.PullRequestID), zap.Error(err))
-		return nil, errors.New("NOT_FOUND")
+	if err := requireAdmin(ctx, s.repo); err != nil {
+		return nil, err
 	}
-	// идемпотентность
-	if pr.Status == "MERGED" {
-		reviewers, _ := s.repo.GetReviewersByPRID(ctx, input.PullRequestID)
-		var createdAt *string
-		if !pr.CreatedAt.IsZero() {
-			t := pr.CreatedAt.UTC().Format(time.RFC3339)
-			createdAt = &t
+	var out *models.PullRequest
+	merged := false
+	err := s.repo.WithTx(ctx, func(repo repository.Repository) error {
+		// блокируем PR, чтобы мерж не пересекся с параллельным переназначением
+		pr, err := repo.LockPullRequest(ctx, input.PullRequestID)
+		if errors.Is(err, repository.ErrNotFound) {
+			log.Info(ctx, "pr not found", zap.String("pr", input.PullRequestID), zap.Error(err))
+			return apperr.Wrap(err, apperr.CodeNotFound, "pr not found")
 		}
-		var mergedAt *string
-		if pr.MergedAt != nil {
-			t := pr.MergedAt.UTC().Format(time.RFC3339)
-			mergedAt = &t
+		if err != nil {
+			log.Error(ctx, "failed to lock pr", zap.Error(err))
+			return err
 		}
-		return &models.PullRequest{PullRequestID: pr.PullRequestID, PullRequestName: pr.PullRequestName, AuthorID: pr.AuthorID, Status: pr.Status, AssignedReviewers: reviewers, CreatedAt: createdAt, MergedAt: mergedAt}, nil
-	}
-	// мержим PR
-	updated, err := s.repo.MergePullRequest(ctx, input.PullRequestID)
+		// идемпотентность: уже замерженный PR возвращаем как есть
+		if pr.Status != "MERGED" {
+			pr, err = repo.MergePullRequest(ctx, input.PullRequestID)
+			if err != nil {
+				log.Error(ctx, "failed to merge pr", zap.Error(err))
+				return err
+			}
+			merged = true
+		}
+		// получаем ревьюверов
+		prWith, err := repo.GetPullRequestWithReviewers(ctx, input.PullRequestID)
+		if err != nil {
+			log.Error(ctx, "failed to get pr reviewers", zap.Error(err))
+			return err
+		}
+		out = toPullRequest(pr, prWith.Reviewers, prWith.FallbackReviewers)
+		return nil
+	})
 	if err != nil {
-		log.Error(ctx, "failed to merge pr", zap.Error(err))
 		return nil, err
 	}
-	// получаем ревьюверов
-	reviewers, _ := s.repo.GetReviewersByPRID(ctx, input.PullRequestID)
-	var createdAt *string
-	if !updated.CreatedAt.IsZero() {
-		t := updated.CreatedAt.UTC().Format(time.RFC3339)
-		createdAt = &t
-	}
-	// формируем ответ
-	var mergedAt *string
-	if updated.MergedAt != nil {
-		t := updated.MergedAt.UTC().Format(time.RFC3339)
-		mergedAt = &t
+	if merged {
+		s.metrics.IncMerges()
 	}
 
-	return &models.PullRequest{PullRequestID: updated.PullRequestID, PullRequestName: updated.PullRequestName, AuthorID: updated.AuthorID, Status: updated.Status, AssignedReviewers: reviewers, CreatedAt: createdAt, MergedAt: mergedAt}, nil
+	return out, nil
 }
 
 func (s *PrService) ReassignReviewer(ctx context.Context, input models.ReassignReviewerInput) (*models.ReassignReviewerOutput, error) {
 	log := logger.GetOrCreateLoggerFromCtx(ctx)
-	// получаем PR с ревьюверами
-	prWith, err := s.repo.GetPullRequestWithReviewers(ctx, input.PullRequestID)
+	a, err := currentActor(ctx, s.repo)
 	if err != nil {
-		log.Info(ctx, "pr not found for reassign", zap.String("pr", input.PullRequestID), zap.Error(err))
-		return nil, errors.New("NOT_FOUND")
+		return nil, err
 	}
-	if prWith.PullRequest.Status == "MERGED" {
-		return nil, errors.New("PR_MERGED")
+	var out *models.ReassignReviewerOutput
+	fallbackPick := false
+	// проверки, замена и чтение результата выполняются в одной транзакции
+	err = s.repo.WithTx(ctx, func(repo repository.Repository) error {
+		// блокируем PR: параллельные переназначения и мерж ждут, а состояние проверяется под блокировкой
+		pr, err := repo.LockPullRequest(ctx, input.PullRequestID)
+		if errors.Is(err, repository.ErrNotFound) {
+			log.Info(ctx, "pr not found for reassign", zap.String("pr", input.PullRequestID), zap.Error(err))
+			return apperr.Wrap(err, apperr.CodeNotFound, "pr not found")
+		}
+		if err != nil {
+			log.Error(ctx, "failed to lock pr", zap.Error(err))
+			return err
+		}
+		if pr.Status == "MERGED" {
+			return apperr.New(apperr.CodePRMerged, "cannot reassign on merged PR")
+		}
+		reviewers, err := repo.GetReviewersByPRID(ctx, input.PullRequestID)
+		if err != nil {
+			log.Error(ctx, "failed to get pr reviewers", zap.Error(err))
+			return err
+		}
+		// проверяем, что старый ревьювер назначен на этот PR
+		if !slices.Contains(reviewers, input.OldReviewerID) {
+			return apperr.New(apperr.CodeNotAssigned, "reviewer is not assigned to this PR")
+		}
+		// получаем пользователя-старого ревьювера
+		oldUser, err := repo.GetUserByID(ctx, input.OldReviewerID)
+		if err != nil {
+			log.Error(ctx, "failed to get old reviewer user", zap.Error(err))
+			return err
+		}
+		// участник снимает с ревью только себя, тимлид — ревьюверов своей команды
+		if !a.canReassign(oldUser) {
+			return apperr.New(apperr.CodeForbidden, "not allowed to reassign this reviewer")
+		}
+		// ищем замену в команде старого ревьювера, затем в её резервных командах
+		settings, err := s.teamSettings(ctx, repo, oldUser.TeamID)
+		if err != nil {
+			log.Error(ctx, "failed to get team settings", zap.Error(err))
+			return err
+		}
+		excluded := map[string]struct{}{}
+		excluded[pr.AuthorID] = struct{}{}
+		for _, r := range reviewers {
+			excluded[r] = struct{}{}
+		}
+		picks, err := s.pickReviewers(ctx, repo, settings, excluded, 1)
+		if err != nil {
+			log.Error(ctx, "failed to select replacement reviewer", zap.Error(err))
+			return err
+		}
+		if len(picks) == 0 {
+			return apperr.New(apperr.CodeNoCandidate, "no active replacement candidate in team")
+		}
+		chosen := picks[0]
+		if err := repo.ReplaceReviewer(ctx, input.PullRequestID, input.OldReviewerID, chosen.UserID, chosen.Fallback, input.Reason); err != nil {
+			log.Error(ctx, "failed to replace reviewer", zap.Error(err))
+			return err
+		}
+		// получаем обновленный PR с ревьюверами
+		updated, err := repo.GetPullRequestWithReviewers(ctx, input.PullRequestID)
+		if err != nil {
+			log.Error(ctx, "failed to fetch updated pr after reassign", zap.Error(err))
+			return err
+		}
+		outPR := toPullRequest(updated.PullRequest, updated.Reviewers, updated.FallbackReviewers)
+		out = &models.ReassignReviewerOutput{PR: outPR, ReplacedBy: chosen.UserID}
+		fallbackPick = chosen.Fallback
+		return nil
+	})
+	if errors.Is(err, apperr.ErrNoCandidate) {
+		s.metrics.AddNoCandidate(metrics.OperationReassign, 1)
 	}
-	// проверяем, что старый ревьювер назначен на этот PR
-	assigned, err := s.repo.IsReviewerAssigned(ctx, input.PullRequestID, input.OldReviewerID)
 	if err != nil {
-		log.Error(ctx, "failed to check reviewer assigned", zap.Error(err))
 		return nil, err
 	}
-	if !assigned {
-		return nil, errors.New("NOT_ASSIGNED")
-	}
-	// получаем пользователя-старого ревьювера
-	oldUser, err := s.repo.GetUserByID(ctx, input.OldReviewerID)
-	if err != nil {
-		log.Error(ctx, "failed to get old reviewer user", zap.Error(err))
+	s.metrics.AddReassignments(metrics.OperationReassign, fallbackPick, 1)
+
+	return out, nil
+}
+
+func (s *PrService) GetPullRequestHistory(ctx context.Context, prID string) (*models.PullRequestHistory, error) {
+	log := logger.GetOrCreateLoggerFromCtx(ctx)
+	if _, err := currentActor(ctx, s.repo); err != nil {
 		return nil, err
 	}
-	// получаем кандидатов на замену из активных пользователей команды
-	candidates, err := s.repo.GetActiveUsersInTeam(ctx, oldUser.TeamID)
+	exists, err := s.repo.PullRequestExists(ctx, prID)
 	if err != nil {
-		log.Error(ctx, "failed to fetch candidates", zap.Error(err))
+		log.Error(ctx, "failed to check pr exists", zap.Error(err))
 		return nil, err
 	}
-	excluded := map[string]struct{}{}
-	excluded[prWith.PullRequest.AuthorID] = struct{}{}
-	for _, r := range prWith.Reviewers {
-		excluded[r] = struct{}{}
-	}
-	// пытаемся найти кандидата
-	var chosen *string
-	for _, c := range candidates {
-		if _, ok := excluded[c.UserID]; ok {
-			continue
-		}
-		chosen = &c.UserID
-		break
-	}
-	if chosen == nil {
-		return nil, errors.New("NO_CANDIDATE")
-	}
-	if err := s.repo.ReplaceReviewer(ctx, input.PullRequestID, input.OldReviewerID, *chosen); err != nil {
-		log.Error(ctx, "failed to replace reviewer", zap.Error(err))
-		return nil, err
+	if !exists {
+		return nil, apperr.New(apperr.CodeNotFound, "pr not found")
 	}
-	// получаем обновленный PR с ревьюверами
-	updated, err := s.repo.GetPullRequestWithReviewers(ctx, input.PullRequestID)
+	// получаем историю назначений
+	rows, err := s.repo.GetAssignmentHistory(ctx, prID)
 	if err != nil {
-		log.Error(ctx, "failed to fetch updated pr after reassign", zap.Error(err))
+		log.Error(ctx, "failed to get assignment history", zap.Error(err))
 		return nil, err
 	}
-
 	// формируем ответ
-	var createdAt *string
-	if !updated.PullRequest.CreatedAt.IsZero() {
-		t := updated.PullRequest.CreatedAt.UTC().Format(time.RFC3339)
-		createdAt = &t
-	}
-	var mergedAt *string
-	if updated.PullRequest.MergedAt != nil {
-		t := updated.PullRequest.MergedAt.UTC().Format(time.RFC3339)
-		mergedAt = &t
+	events := make([]models.ReviewerAssignmentEvent, 0, len(rows))
+	for _, h := range rows {
+		events = append(events, models.ReviewerAssignmentEvent{
+			OldUserID:  h.OldReviewerUserID,
+			NewUserID:  h.NewReviewerUserID,
+			Reason:     h.Reason,
+			AssignedAt: h.ReassignedAt.UTC().Format(time.RFC3339),
+		})
 	}
-	outPR := &models.PullRequest{PullRequestID: updated.PullRequest.PullRequestID, PullRequestName: updated.PullRequest.PullRequestName, AuthorID: updated.PullRequest.AuthorID, Status: updated.PullRequest.Status, AssignedReviewers: updated.Reviewers, CreatedAt: createdAt, MergedAt: mergedAt}
 
-	return &models.ReassignReviewerOutput{PR: outPR, ReplacedBy: *chosen}, nil
+	return &models.PullRequestHistory{PullRequestID: prID, History: events}, nil
 }
 
 // ==================== Stats Service Methods ====================
@@ -376,5 +774,282 @@ func (s *PrService) GetStats(ctx context.Context) (*models.StatsOutput, error) {
 	}, nil
 }
 
+// reviewerPick выбранный ревьювер
+type reviewerPick struct {
+	UserID   string
+	Fallback bool // выбран из резервной команды
+}
+
+// pickReviewers выбирает до n ревьюверов из команды settings.TeamID,
+// а недостающих добирает из её резервных команд в порядке приоритета.
+// Выбранные добавляются в excluded. repo позволяет выбирать внутри транзакции
+func (s *PrService) pickReviewers(ctx context.Context, repo repository.Repository, settings *repository.TeamSettingsModel, excluded map[string]struct{}, n int) ([]reviewerPick, error) {
+	picks, err := s.pickFromTeam(ctx, repo, settings, excluded, n, false)
+	if err != nil || len(picks) >= n {
+		return picks, err
+	}
+	fallbackTeams, err := repo.GetFallbackTeams(ctx, settings.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	for _, ft := range fallbackTeams {
+		if len(picks) >= n {
+			break
+		}
+		// кандидаты резервной команды выбираются по её собственным настройкам
+		ftSettings, err := s.teamSettings(ctx, repo, ft.ID)
+		if err != nil {
+			return nil, err
+		}
+		more, err := s.pickFromTeam(ctx, repo, ftSettings, excluded, n-len(picks), true)
+		if err != nil {
+			return nil, err
+		}
+		picks = append(picks, more...)
+	}
+	return picks, nil
+}
+
+// topUpReviewers добирает ревьюверов на OPEN PR авторов команды teamID,
+// помеченные needMoreReviewers. Флаг снимается, когда ревьюверов стало достаточно.
+// Возвращает всех назначенных ревьюверов
+func (s *PrService) topUpReviewers(ctx context.Context, repo repository.Repository, teamID int64) ([]reviewerPick, error) {
+	prs, err := repo.GetOpenPRsNeedingReviewers(ctx, teamID)
+	if err != nil || len(prs) == 0 {
+		return nil, err
+	}
+	settings, err := s.teamSettings(ctx, repo, teamID)
+	if err != nil {
+		return nil, err
+	}
+	var assigned []reviewerPick
+	for _, pr := range prs {
+		reviewers, err := repo.GetReviewersByPRID(ctx, pr.PullRequestID)
+		if err != nil {
+			return nil, err
+		}
+		excluded := map[string]struct{}{pr.AuthorID: {}}
+		for _, r := range reviewers {
+			excluded[r] = struct{}{}
+		}
+		picks, err := s.pickReviewers(ctx, repo, settings, excluded, settings.ReviewersCount-len(reviewers))
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range picks {
+			if err := repo.AssignReviewer(ctx, pr.PullRequestID, p.UserID, p.Fallback); err != nil {
+				return nil, err
+			}
+		}
+		assigned = append(assigned, picks...)
+		if len(reviewers)+len(picks) >= settings.ReviewersCount {
+			if err := repo.SetNeedMoreReviewers(ctx, pr.PullRequestID, false); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return assigned, nil
+}
+
+// planReplacements подбирает замену каждому ревьюверу из removed на PR reviews тем же фильтром
+// и стратегией, что и назначение на один PR. Кандидаты читаются один раз на команду,
+// а загрузка выбранного учитывается сразу, поэтому лимит открытых ревью соблюдается для всего пакета
+func (s *PrService) planReplacements(ctx context.Context, repo repository.Repository, teamID int64, reviews []repository.OpenReview, removed []string) ([]repository.ReviewerReplacement, error) {
+	cache := newCandidateCache(repo)
+	settings, err := s.teamSettings(ctx, cache, teamID)
+	if err != nil {
+		return nil, err
+	}
+	var plan []repository.ReviewerReplacement
+	for _, rv := range reviews {
+		excluded := map[string]struct{}{rv.AuthorID: {}}
+		for _, r := range rv.Reviewers {
+			excluded[r] = struct{}{}
+		}
+		for _, old := range rv.Reviewers {
+			if !slices.Contains(removed, old) {
+				continue
+			}
+			rp := repository.ReviewerReplacement{PullRequestID: rv.PullRequestID, OldReviewerUserID: old}
+			picks, err := s.pickReviewers(ctx, cache, settings, excluded, 1)
+			if err != nil {
+				return nil, err
+			}
+			if len(picks) > 0 {
+				rp.NewReviewerUserID = &picks[0].UserID
+				rp.IsFallback = picks[0].Fallback
+				cache.addOpenReview(picks[0].UserID)
+			}
+			plan = append(plan, rp)
+		}
+	}
+	return plan, nil
+}
+
+// candidateCache репозиторий, запоминающий настройки, резервные команды и кандидатов каждой команды.
+// Используется при подборе ревьюверов на много PR в одной транзакции
+type candidateCache struct {
+	repository.Repository
+	settings   map[int64]*repository.TeamSettingsModel
+	fallbacks  map[int64][]repository.TeamModel
+	candidates map[int64][]repository.ReviewerCandidate
+}
+
+func newCandidateCache(repo repository.Repository) *candidateCache {
+	return &candidateCache{
+		Repository: repo,
+		settings:   map[int64]*repository.TeamSettingsModel{},
+		fallbacks:  map[int64][]repository.TeamModel{},
+		candidates: map[int64][]repository.ReviewerCandidate{},
+	}
+}
+
+func (c *candidateCache) GetTeamSettings(ctx context.Context, teamID int64) (*repository.TeamSettingsModel, error) {
+	if settings, ok := c.settings[teamID]; ok {
+		return settings, nil
+	}
+	settings, err := c.Repository.GetTeamSettings(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	c.settings[teamID] = settings
+	return settings, nil
+}
+
+func (c *candidateCache) GetFallbackTeams(ctx context.Context, teamID int64) ([]repository.TeamModel, error) {
+	if teams, ok := c.fallbacks[teamID]; ok {
+		return teams, nil
+	}
+	teams, err := c.Repository.GetFallbackTeams(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	c.fallbacks[teamID] = teams
+	return teams, nil
+}
+
+func (c *candidateCache) GetActiveUsersWithLoad(ctx context.Context, teamID int64) ([]repository.ReviewerCandidate, error) {
+	if candidates, ok := c.candidates[teamID]; ok {
+		return candidates, nil
+	}
+	candidates, err := c.Repository.GetActiveUsersWithLoad(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	c.candidates[teamID] = candidates
+	return candidates, nil
+}
+
+// addOpenReview учитывает новое открытое ревью кандидата userID
+func (c *candidateCache) addOpenReview(userID string) {
+	for _, candidates := range c.candidates {
+		for i := range candidates {
+			if candidates[i].UserID == userID {
+				candidates[i].OpenReviews++
+				return
+			}
+		}
+	}
+}
+
+// recordAssignments учитывает в метриках назначенных ревьюверов, отдельно — из резервных команд
+func (s *PrService) recordAssignments(operation string, picks []reviewerPick) {
+	fallback := 0
+	for _, p := range picks {
+		if p.Fallback {
+			fallback++
+		}
+	}
+	s.metrics.AddAssignments(operation, false, len(picks)-fallback)
+	s.metrics.AddAssignments(operation, true, fallback)
+}
+
+// pickFromTeam выбирает до n ревьюверов из активных участников одной команды
+func (s *PrService) pickFromTeam(ctx context.Context, repo repository.Repository, settings *repository.TeamSettingsModel, excluded map[string]struct{}, n int, fallback bool) ([]reviewerPick, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	candidates, err := repo.GetActiveUsersWithLoad(ctx, settings.TeamID)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := s.selectorFor(settings).Select(ctx, filterCandidates(candidates, excluded, settings.MaxOpenReviews), n)
+	if err != nil {
+		return nil, err
+	}
+	picks := make([]reviewerPick, 0, len(ids))
+	for _, id := range ids {
+		excluded[id] = struct{}{}
+		picks = append(picks, reviewerPick{UserID: id, Fallback: fallback})
+	}
+	return picks, nil
+}
+
+// teamSettings возвращает настройки команды или настройки по умолчанию
+func (s *PrService) teamSettings(ctx context.Context, repo repository.Repository, teamID int64) (*repository.TeamSettingsModel, error) {
+	settings, err := repo.GetTeamSettings(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		settings = &repository.TeamSettingsModel{TeamID: teamID, ReviewersCount: defaultReviewersCount}
+	}
+	return settings, nil
+}
+
+// selectorFor возвращает стратегию выбора ревьюверов для настроек команды
+func (s *PrService) selectorFor(settings *repository.TeamSettingsModel) ReviewerSelector {
+	if settings.Strategy != nil {
+		if sel, ok := s.selectors[*settings.Strategy]; ok {
+			return sel
+		}
+	}
+	return s.selector
+}
+
+// filterCandidates возвращает кандидатов, не входящих в excluded
+// и не превысивших лимит открытых ревью (0 — без лимита)
+func filterCandidates(candidates []repository.ReviewerCandidate, excluded map[string]struct{}, maxOpenReviews int) []repository.ReviewerCandidate {
+	res := make([]repository.ReviewerCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		if _, ok := excluded[c.UserID]; ok {
+			continue
+		}
+		if maxOpenReviews > 0 && c.OpenReviews >= maxOpenReviews {
+			continue
+		}
+		res = append(res, c)
+	}
+	return res
+}
+
+// toPullRequest формирует ответ с PR
+func toPullRequest(pr *repository.PullRequestModel, reviewers, fallback []string) *models.PullRequest {
+	var createdAt *string
+	if !pr.CreatedAt.IsZero() {
+		t := pr.CreatedAt.UTC().Format(time.RFC3339)
+		createdAt = &t
+	}
+	var mergedAt *string
+	if pr.MergedAt != nil {
+		t := pr.MergedAt.UTC().Format(time.RFC3339)
+		mergedAt = &t
+	}
+
+	return &models.PullRequest{PullRequestID: pr.PullRequestID, PullRequestName: pr.PullRequestName, AuthorID: pr.AuthorID, Status: pr.Status, AssignedReviewers: reviewers, FallbackReviewers: fallback, NeedMoreReviewers: pr.NeedMoreReviewers, CreatedAt: createdAt, MergedAt: mergedAt}
+}
+
+// toTeamSettings формирует ответ с настройками команды
+func toTeamSettings(teamName string, settings *repository.TeamSettingsModel, fallbackTeams []repository.TeamModel) *models.TeamSettings {
+	out := &models.TeamSettings{TeamName: teamName, ReviewersCount: settings.ReviewersCount, MaxOpenReviews: settings.MaxOpenReviews, FallbackTeams: []string{}}
+	if settings.Strategy != nil {
+		out.Strategy = *settings.Strategy
+	}
+	for _, ft := range fallbackTeams {
+		out.FallbackTeams = append(out.FallbackTeams, ft.TeamName)
+	}
+	return out
+}
+
 // проверка реализации интерфейса Service
 var _ Service = (*PrService)(nil)
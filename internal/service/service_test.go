@@ -0,0 +1,111 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"avito-test-quest/internal/auth"
+	"avito-test-quest/internal/logger"
+	"avito-test-quest/internal/repository"
+	"avito-test-quest/internal/repository/mocks"
+	"avito-test-quest/internal/service"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// errDB ошибка хранилища, которую сервис должен вернуть как есть
+var errDB = errors.New("connection reset")
+
+// mergedAt время мержа в фикстурах
+var mergedAt = time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// firstSelector детерминированная стратегия: первые n кандидатов в порядке репозитория
+type firstSelector struct{}
+
+func (firstSelector) Select(_ context.Context, candidates []repository.ReviewerCandidate, n int) ([]string, error) {
+	var ids []string
+	for _, c := range candidates {
+		if len(ids) == n {
+			break
+		}
+		ids = append(ids, c.UserID)
+	}
+	return ids, nil
+}
+
+// newService создает сервис поверх мока репозитория.
+// WithTx вызывает fn с тем же моком, поэтому ожидания одинаковы внутри и вне транзакции
+func newService(t *testing.T) (service.Service, *mocks.MockRepositoryMockRecorder) {
+	t.Helper()
+	repo := mocks.NewMockRepository(gomock.NewController(t))
+	repo.EXPECT().WithTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, fn func(repository.Repository) error) error {
+			return fn(repo)
+		}).AnyTimes()
+
+	return service.NewPrService(repo, firstSelector{}, nil), repo.EXPECT()
+}
+
+// baseCtx контекст с логгером, пишущим только фатальные ошибки
+func baseCtx(t *testing.T) context.Context {
+	t.Helper()
+	ctx, _, err := logger.New(context.Background(), logger.Config{Level: "fatal"})
+	require.NoError(t, err)
+	return ctx
+}
+
+// adminCtx контекст с админским токеном
+func adminCtx(t *testing.T) context.Context {
+	return auth.WithPrincipal(baseCtx(t), &auth.Principal{Role: auth.RoleAdmin})
+}
+
+// userCtx контекст с пользовательским токеном userID
+func userCtx(t *testing.T, userID string) context.Context {
+	return auth.WithPrincipal(baseCtx(t), &auth.Principal{UserID: userID, Role: auth.RoleUser})
+}
+
+// user фикстура пользователя
+func user(userID string, teamID int64, role string) *repository.UserModel {
+	return &repository.UserModel{UserID: userID, Username: "name-" + userID, TeamID: teamID, IsActive: true, Role: role}
+}
+
+// candidates активные кандидаты без открытых ревью
+func candidates(teamID int64, userIDs ...string) []repository.ReviewerCandidate {
+	res := make([]repository.ReviewerCandidate, 0, len(userIDs))
+	for _, id := range userIDs {
+		res = append(res, repository.ReviewerCandidate{UserModel: *user(id, teamID, service.RoleMember)})
+	}
+	return res
+}
+
+// openPR фикстура OPEN PR
+func openPR(prID, authorID string) *repository.PullRequestModel {
+	return &repository.PullRequestModel{PullRequestID: prID, PullRequestName: "name-" + prID, AuthorID: authorID, Status: "OPEN"}
+}
+
+// mergedPR фикстура MERGED PR
+func mergedPR(prID, authorID string) *repository.PullRequestModel {
+	pr := openPR(prID, authorID)
+	pr.Status = "MERGED"
+	pr.MergedAt = &mergedAt
+	return pr
+}
+
+// ptr возвращает указатель на v
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// requireErr проверяет ожидаемую ошибку. errDB должна дойти до вызывающего как есть:
+// без обертки в доменный код, иначе ErrorHandler отдаст вместо INTERNAL код обертки
+func requireErr(t *testing.T, err, want error) {
+	t.Helper()
+	if want == errDB {
+		require.Equal(t, errDB, err)
+		return
+	}
+	require.ErrorIs(t, err, want)
+}
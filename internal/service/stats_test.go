@@ -0,0 +1,74 @@
+package service_test
+
+import (
+	"testing"
+
+	"avito-test-quest/internal/models"
+	"avito-test-quest/internal/repository"
+	"avito-test-quest/internal/repository/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetStats(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(r *mocks.MockRepositoryMockRecorder)
+		want    *models.StatsOutput
+		wantErr error
+	}{
+		{
+			name: "maps reviewer and PR stats",
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetReviewerStats(gomock.Any()).Return([]repository.ReviewerStatRow{{UserID: "u2", Username: "Bob", AssignedCount: 3}}, nil)
+				r.GetPRStats(gomock.Any()).Return([]repository.PRStatRow{
+					{PullRequestID: "pr-1", PullRequestName: "Add feature", AuthorID: "u1", Status: "OPEN", ReviewerCount: 2},
+				}, nil)
+			},
+			want: &models.StatsOutput{
+				ReviewerStats: []models.ReviewerStat{{UserID: "u2", Username: "Bob", AssignedCount: 3}},
+				PRStats:       []models.PRStat{{PullRequestID: "pr-1", PullRequestName: "Add feature", AuthorID: "u1", Status: "OPEN", ReviewerCount: 2}},
+			},
+		},
+		{
+			name: "empty store",
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetReviewerStats(gomock.Any()).Return(nil, nil)
+				r.GetPRStats(gomock.Any()).Return(nil, nil)
+			},
+			want: &models.StatsOutput{},
+		},
+		{
+			name: "repository failure on reviewer stats",
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetReviewerStats(gomock.Any()).Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name: "repository failure on PR stats",
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetReviewerStats(gomock.Any()).Return(nil, nil)
+				r.GetPRStats(gomock.Any()).Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, r := newService(t)
+			tt.setup(r)
+
+			got, err := svc.GetStats(adminCtx(t))
+			if tt.wantErr != nil {
+				requireErr(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,728 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"avito-test-quest/internal/apperr"
+	"avito-test-quest/internal/models"
+	"avito-test-quest/internal/repository"
+	"avito-test-quest/internal/repository/mocks"
+	"avito-test-quest/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+var (
+	backend  = &repository.TeamModel{ID: 1, TeamName: "backend"}
+	frontend = &repository.TeamModel{ID: 2, TeamName: "frontend"}
+)
+
+func TestCreateTeam(t *testing.T) {
+	input := models.CreateTeamInput{
+		TeamName: "backend",
+		Members: []models.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: true},
+			{UserID: "u2", Username: "Bob", IsActive: false},
+		},
+	}
+	members := []repository.UserModel{
+		{UserID: "u1", Username: "Alice", TeamID: 1, IsActive: true},
+		{UserID: "u2", Username: "Bob", TeamID: 1, IsActive: false},
+	}
+	team := &models.Team{
+		TeamName: "backend",
+		Members: []models.TeamMember{
+			{UserID: "u1", Username: "Alice", IsActive: true},
+			{UserID: "u2", Username: "Bob", IsActive: false},
+		},
+	}
+	// создание команды до добора ревьюверов
+	createMembers := func(r *mocks.MockRepositoryMockRecorder) {
+		r.TeamExists(gomock.Any(), "backend").Return(false, nil)
+		r.CreateTeam(gomock.Any(), "backend").Return(backend, nil)
+		r.UserExists(gomock.Any(), "u1").Return(false, nil)
+		r.CreateUser(gomock.Any(), "u1", "Alice", int64(1), true).Return(&members[0], nil)
+		r.UserExists(gomock.Any(), "u2").Return(true, nil)
+		r.UpdateUser(gomock.Any(), "u2", "Bob", int64(1), false).Return(&members[1], nil)
+	}
+
+	tests := []struct {
+		name    string
+		ctx     func(t *testing.T) context.Context
+		setup   func(r *mocks.MockRepositoryMockRecorder)
+		want    *models.Team
+		wantErr error
+	}{
+		{
+			name: "creates team and upserts members",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				createMembers(r)
+				r.GetOpenPRsNeedingReviewers(gomock.Any(), int64(1)).Return(nil, nil)
+				r.GetUsersByTeamID(gomock.Any(), int64(1)).Return(members, nil)
+			},
+			want: team,
+		},
+		{
+			name: "tops up reviewers on PRs of moved authors",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				createMembers(r)
+				r.GetOpenPRsNeedingReviewers(gomock.Any(), int64(1)).Return([]repository.PullRequestModel{*openPR("pr-1", "u1")}, nil)
+				r.GetTeamSettings(gomock.Any(), int64(1)).Return(nil, nil)
+				r.GetReviewersByPRID(gomock.Any(), "pr-1").Return([]string{"u3"}, nil)
+				r.GetActiveUsersWithLoad(gomock.Any(), int64(1)).Return(candidates(1, "u1", "u2"), nil)
+				r.AssignReviewer(gomock.Any(), "pr-1", "u2", false).Return(nil)
+				r.SetNeedMoreReviewers(gomock.Any(), "pr-1", false).Return(nil)
+				r.GetUsersByTeamID(gomock.Any(), int64(1)).Return(members, nil)
+			},
+			want: team,
+		},
+		{
+			name: "TEAM_EXISTS",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.TeamExists(gomock.Any(), "backend").Return(true, nil)
+			},
+			wantErr: apperr.ErrTeamExists,
+		},
+		{
+			name: "TEAM_EXISTS on concurrent insert",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.TeamExists(gomock.Any(), "backend").Return(false, nil)
+				r.CreateTeam(gomock.Any(), "backend").Return(nil, repository.ErrAlreadyExists)
+			},
+			wantErr: apperr.ErrTeamExists,
+		},
+		{
+			name: "repository failure on team check",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.TeamExists(gomock.Any(), "backend").Return(false, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name: "repository failure on member insert",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.TeamExists(gomock.Any(), "backend").Return(false, nil)
+				r.CreateTeam(gomock.Any(), "backend").Return(backend, nil)
+				r.UserExists(gomock.Any(), "u1").Return(false, nil)
+				r.CreateUser(gomock.Any(), "u1", "Alice", int64(1), true).Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name: "FORBIDDEN for team lead moving existing user",
+			ctx:  func(t *testing.T) context.Context { return userCtx(t, "lead") },
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetUserByID(gomock.Any(), "lead").Return(user("lead", 2, service.RoleTeamLead), nil)
+				r.TeamExists(gomock.Any(), "backend").Return(false, nil)
+				r.CreateTeam(gomock.Any(), "backend").Return(backend, nil)
+				r.UserExists(gomock.Any(), "u1").Return(false, nil)
+				r.CreateUser(gomock.Any(), "u1", "Alice", int64(1), true).Return(&members[0], nil)
+				r.UserExists(gomock.Any(), "u2").Return(true, nil)
+			},
+			wantErr: apperr.ErrForbidden,
+		},
+		{
+			name:    "UNAUTHORIZED without principal",
+			ctx:     baseCtx,
+			setup:   func(*mocks.MockRepositoryMockRecorder) {},
+			wantErr: apperr.ErrUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, r := newService(t)
+			tt.setup(r)
+
+			got, err := svc.CreateTeam(tt.ctx(t), input)
+			if tt.wantErr != nil {
+				requireErr(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetTeam(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     func(t *testing.T) context.Context
+		setup   func(r *mocks.MockRepositoryMockRecorder)
+		want    *models.Team
+		wantErr error
+	}{
+		{
+			name: "returns team with members",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetTeamByName(gomock.Any(), "backend").Return(backend, nil)
+				r.GetUsersByTeamID(gomock.Any(), int64(1)).Return([]repository.UserModel{*user("u1", 1, service.RoleMember)}, nil)
+			},
+			want: &models.Team{TeamName: "backend", Members: []models.TeamMember{{UserID: "u1", Username: "name-u1", IsActive: true}}},
+		},
+		{
+			name:    "UNAUTHORIZED without principal",
+			ctx:     baseCtx,
+			setup:   func(*mocks.MockRepositoryMockRecorder) {},
+			wantErr: apperr.ErrUnauthorized,
+		},
+		{
+			name: "FORBIDDEN for unknown caller",
+			ctx:  func(t *testing.T) context.Context { return userCtx(t, "ghost") },
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetUserByID(gomock.Any(), "ghost").Return(nil, repository.ErrNotFound)
+			},
+			wantErr: apperr.ErrForbidden,
+		},
+		{
+			name: "NOT_FOUND",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetTeamByName(gomock.Any(), "backend").Return(nil, repository.ErrNotFound)
+			},
+			wantErr: apperr.ErrNotFound,
+		},
+		{
+			name: "repository failure on team",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetTeamByName(gomock.Any(), "backend").Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name: "repository failure on caller",
+			ctx:  func(t *testing.T) context.Context { return userCtx(t, "u1") },
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetUserByID(gomock.Any(), "u1").Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name: "repository failure on members",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetTeamByName(gomock.Any(), "backend").Return(backend, nil)
+				r.GetUsersByTeamID(gomock.Any(), int64(1)).Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, r := newService(t)
+			tt.setup(r)
+
+			got, err := svc.GetTeam(tt.ctx(t), "backend")
+			if tt.wantErr != nil {
+				requireErr(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetTeamSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     func(t *testing.T) context.Context
+		setup   func(r *mocks.MockRepositoryMockRecorder)
+		want    *models.TeamSettings
+		wantErr error
+	}{
+		{
+			name: "defaults when settings were never set",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetTeamByName(gomock.Any(), "backend").Return(backend, nil)
+				r.GetTeamSettings(gomock.Any(), int64(1)).Return(nil, nil)
+				r.GetFallbackTeams(gomock.Any(), int64(1)).Return(nil, nil)
+			},
+			want: &models.TeamSettings{TeamName: "backend", ReviewersCount: 2, FallbackTeams: []string{}},
+		},
+		{
+			name: "stored settings with fallback teams",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetTeamByName(gomock.Any(), "backend").Return(backend, nil)
+				r.GetTeamSettings(gomock.Any(), int64(1)).Return(&repository.TeamSettingsModel{
+					TeamID: 1, ReviewersCount: 3, Strategy: ptr(models.StrategyLeastLoaded), MaxOpenReviews: 5,
+				}, nil)
+				r.GetFallbackTeams(gomock.Any(), int64(1)).Return([]repository.TeamModel{*frontend}, nil)
+			},
+			want: &models.TeamSettings{
+				TeamName: "backend", ReviewersCount: 3, Strategy: models.StrategyLeastLoaded, MaxOpenReviews: 5,
+				FallbackTeams: []string{"frontend"},
+			},
+		},
+		{
+			name: "NOT_FOUND",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetTeamByName(gomock.Any(), "backend").Return(nil, repository.ErrNotFound)
+			},
+			wantErr: apperr.ErrNotFound,
+		},
+		{
+			name: "repository failure on team",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetTeamByName(gomock.Any(), "backend").Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name: "repository failure on settings",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetTeamByName(gomock.Any(), "backend").Return(backend, nil)
+				r.GetTeamSettings(gomock.Any(), int64(1)).Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name: "repository failure on fallback teams",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetTeamByName(gomock.Any(), "backend").Return(backend, nil)
+				r.GetTeamSettings(gomock.Any(), int64(1)).Return(nil, nil)
+				r.GetFallbackTeams(gomock.Any(), int64(1)).Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name:    "UNAUTHORIZED without principal",
+			ctx:     baseCtx,
+			setup:   func(*mocks.MockRepositoryMockRecorder) {},
+			wantErr: apperr.ErrUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, r := newService(t)
+			tt.setup(r)
+
+			got, err := svc.GetTeamSettings(tt.ctx(t), "backend")
+			if tt.wantErr != nil {
+				requireErr(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUpdateTeamSettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		ctx     func(t *testing.T) context.Context
+		input   models.UpdateTeamSettingsInput
+		setup   func(r *mocks.MockRepositoryMockRecorder)
+		want    *models.TeamSettings
+		wantErr error
+	}{
+		{
+			name: "applies passed fields and fallback teams",
+			ctx:  adminCtx,
+			input: models.UpdateTeamSettingsInput{
+				TeamName: "backend", ReviewersCount: ptr(1), Strategy: ptr(models.StrategyRoundRobin),
+				FallbackTeams: []string{"frontend"},
+			},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				stored := repository.TeamSettingsModel{TeamID: 1, ReviewersCount: 1, Strategy: ptr(models.StrategyRoundRobin)}
+				r.GetTeamByName(gomock.Any(), "backend").Return(backend, nil)
+				r.GetTeamSettings(gomock.Any(), int64(1)).Return(nil, nil)
+				r.GetTeamByName(gomock.Any(), "frontend").Return(frontend, nil)
+				r.UpsertTeamSettings(gomock.Any(), stored).Return(&stored, nil)
+				r.SetFallbackTeams(gomock.Any(), int64(1), []int64{2}).Return(nil)
+				r.GetFallbackTeams(gomock.Any(), int64(1)).Return([]repository.TeamModel{*frontend}, nil)
+			},
+			want: &models.TeamSettings{
+				TeamName: "backend", ReviewersCount: 1, Strategy: models.StrategyRoundRobin,
+				FallbackTeams: []string{"frontend"},
+			},
+		},
+		{
+			name:  "empty strategy resets to default and keeps fallback teams",
+			ctx:   adminCtx,
+			input: models.UpdateTeamSettingsInput{TeamName: "backend", Strategy: ptr("")},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				stored := repository.TeamSettingsModel{TeamID: 1, ReviewersCount: 2}
+				r.GetTeamByName(gomock.Any(), "backend").Return(backend, nil)
+				r.GetTeamSettings(gomock.Any(), int64(1)).Return(&repository.TeamSettingsModel{
+					TeamID: 1, ReviewersCount: 2, Strategy: ptr(models.StrategyRandom),
+				}, nil)
+				r.UpsertTeamSettings(gomock.Any(), stored).Return(&stored, nil)
+				r.GetFallbackTeams(gomock.Any(), int64(1)).Return(nil, nil)
+			},
+			want: &models.TeamSettings{TeamName: "backend", ReviewersCount: 2, FallbackTeams: []string{}},
+		},
+		{
+			name:  "INVALID_STRATEGY",
+			ctx:   adminCtx,
+			input: models.UpdateTeamSettingsInput{TeamName: "backend", Strategy: ptr("fastest")},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetTeamByName(gomock.Any(), "backend").Return(backend, nil)
+				r.GetTeamSettings(gomock.Any(), int64(1)).Return(nil, nil)
+			},
+			wantErr: apperr.ErrInvalidStrategy,
+		},
+		{
+			name:  "INVALID_FALLBACK_TEAM for the team itself",
+			ctx:   adminCtx,
+			input: models.UpdateTeamSettingsInput{TeamName: "backend", FallbackTeams: []string{"backend"}},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetTeamByName(gomock.Any(), "backend").Return(backend, nil)
+				r.GetTeamSettings(gomock.Any(), int64(1)).Return(nil, nil)
+			},
+			wantErr: apperr.ErrInvalidFallbackTeam,
+		},
+		{
+			name:  "INVALID_FALLBACK_TEAM for duplicates",
+			ctx:   adminCtx,
+			input: models.UpdateTeamSettingsInput{TeamName: "backend", FallbackTeams: []string{"frontend", "frontend"}},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetTeamByName(gomock.Any(), "backend").Return(backend, nil)
+				r.GetTeamSettings(gomock.Any(), int64(1)).Return(nil, nil)
+				r.GetTeamByName(gomock.Any(), "frontend").Return(frontend, nil)
+			},
+			wantErr: apperr.ErrInvalidFallbackTeam,
+		},
+		{
+			name:  "INVALID_FALLBACK_TEAM for unknown team",
+			ctx:   adminCtx,
+			input: models.UpdateTeamSettingsInput{TeamName: "backend", FallbackTeams: []string{"ghost"}},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetTeamByName(gomock.Any(), "backend").Return(backend, nil)
+				r.GetTeamSettings(gomock.Any(), int64(1)).Return(nil, nil)
+				r.GetTeamByName(gomock.Any(), "ghost").Return(nil, repository.ErrNotFound)
+			},
+			wantErr: apperr.ErrInvalidFallbackTeam,
+		},
+		{
+			name:  "repository failure on fallback team",
+			ctx:   adminCtx,
+			input: models.UpdateTeamSettingsInput{TeamName: "backend", FallbackTeams: []string{"frontend"}},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetTeamByName(gomock.Any(), "backend").Return(backend, nil)
+				r.GetTeamSettings(gomock.Any(), int64(1)).Return(nil, nil)
+				r.GetTeamByName(gomock.Any(), "frontend").Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name:  "NOT_FOUND",
+			ctx:   adminCtx,
+			input: models.UpdateTeamSettingsInput{TeamName: "backend", ReviewersCount: ptr(1)},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetTeamByName(gomock.Any(), "backend").Return(nil, repository.ErrNotFound)
+			},
+			wantErr: apperr.ErrNotFound,
+		},
+		{
+			name:  "repository failure on team",
+			ctx:   adminCtx,
+			input: models.UpdateTeamSettingsInput{TeamName: "backend", ReviewersCount: ptr(1)},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetTeamByName(gomock.Any(), "backend").Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name:  "repository failure on upsert",
+			ctx:   adminCtx,
+			input: models.UpdateTeamSettingsInput{TeamName: "backend", ReviewersCount: ptr(1)},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetTeamByName(gomock.Any(), "backend").Return(backend, nil)
+				r.GetTeamSettings(gomock.Any(), int64(1)).Return(nil, nil)
+				r.UpsertTeamSettings(gomock.Any(), gomock.Any()).Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name:  "team lead updates own team",
+			ctx:   func(t *testing.T) context.Context { return userCtx(t, "lead") },
+			input: models.UpdateTeamSettingsInput{TeamName: "backend", ReviewersCount: ptr(1)},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				stored := repository.TeamSettingsModel{TeamID: 1, ReviewersCount: 1}
+				r.GetUserByID(gomock.Any(), "lead").Return(user("lead", 1, service.RoleTeamLead), nil)
+				r.GetTeamByName(gomock.Any(), "backend").Return(backend, nil)
+				r.GetTeamSettings(gomock.Any(), int64(1)).Return(nil, nil)
+				r.UpsertTeamSettings(gomock.Any(), stored).Return(&stored, nil)
+				r.GetFallbackTeams(gomock.Any(), int64(1)).Return(nil, nil)
+			},
+			want: &models.TeamSettings{TeamName: "backend", ReviewersCount: 1, FallbackTeams: []string{}},
+		},
+		{
+			name:  "FORBIDDEN for member",
+			ctx:   func(t *testing.T) context.Context { return userCtx(t, "u1") },
+			input: models.UpdateTeamSettingsInput{TeamName: "backend", ReviewersCount: ptr(1)},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetUserByID(gomock.Any(), "u1").Return(user("u1", 1, service.RoleMember), nil)
+			},
+			wantErr: apperr.ErrForbidden,
+		},
+		{
+			name:  "FORBIDDEN for lead of another team",
+			ctx:   func(t *testing.T) context.Context { return userCtx(t, "lead") },
+			input: models.UpdateTeamSettingsInput{TeamName: "backend", ReviewersCount: ptr(1)},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetUserByID(gomock.Any(), "lead").Return(user("lead", 2, service.RoleTeamLead), nil)
+				r.GetTeamByName(gomock.Any(), "backend").Return(backend, nil)
+			},
+			wantErr: apperr.ErrForbidden,
+		},
+		{
+			name:    "UNAUTHORIZED without principal",
+			ctx:     baseCtx,
+			input:   models.UpdateTeamSettingsInput{TeamName: "backend", ReviewersCount: ptr(1)},
+			setup:   func(*mocks.MockRepositoryMockRecorder) {},
+			wantErr: apperr.ErrUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, r := newService(t)
+			tt.setup(r)
+
+			got, err := svc.UpdateTeamSettings(tt.ctx(t), tt.input)
+			if tt.wantErr != nil {
+				requireErr(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDeactivateTeamMembers(t *testing.T) {
+	reason := ptr("vacation")
+	// pr-1 получает u3 из команды, pr-2 (автор u3) — f1 из резервной команды, pr-3 — никого
+	reviews := []repository.OpenReview{
+		{PullRequestID: "pr-1", AuthorID: "u4", Reviewers: []string{"u1"}},
+		{PullRequestID: "pr-2", AuthorID: "u3", Reviewers: []string{"u2"}},
+		{PullRequestID: "pr-3", AuthorID: "u3", Reviewers: []string{"f1", "u2"}},
+	}
+	plan := []repository.ReviewerReplacement{
+		{PullRequestID: "pr-1", OldReviewerUserID: "u1", NewReviewerUserID: ptr("u3")},
+		{PullRequestID: "pr-2", OldReviewerUserID: "u2", NewReviewerUserID: ptr("f1"), IsFallback: true},
+		{PullRequestID: "pr-3", OldReviewerUserID: "u2"},
+	}
+	team := []repository.UserModel{*user("u1", 1, service.RoleMember), *user("u2", 1, service.RoleMember), *user("u3", 1, service.RoleMember)}
+	// deactivated команда найдена, u1 и u2 деактивированы
+	deactivated := func(r *mocks.MockRepositoryMockRecorder) {
+		r.GetTeamByName(gomock.Any(), "backend").Return(backend, nil)
+		r.GetUsersByTeamID(gomock.Any(), int64(1)).Return(team, nil)
+		r.DeactivateTeamMembers(gomock.Any(), int64(1), []string{"u1", "u2"}, false).Return([]string{"u1", "u2"}, nil)
+	}
+	// planned кандидаты и настройки читаются один раз на команду, а не на каждый PR
+	planned := func(r *mocks.MockRepositoryMockRecorder) {
+		deactivated(r)
+		r.LockOpenReviews(gomock.Any(), []string{"u1", "u2"}).Return(reviews, nil)
+		r.GetTeamSettings(gomock.Any(), int64(1)).Return(nil, nil)
+		r.GetActiveUsersWithLoad(gomock.Any(), int64(1)).Return(candidates(1, "u3"), nil)
+		r.GetFallbackTeams(gomock.Any(), int64(1)).Return([]repository.TeamModel{*frontend}, nil)
+		r.GetTeamSettings(gomock.Any(), int64(2)).Return(nil, nil)
+		r.GetActiveUsersWithLoad(gomock.Any(), int64(2)).Return(candidates(2, "f1"), nil)
+	}
+
+	tests := []struct {
+		name    string
+		ctx     func(t *testing.T) context.Context
+		input   models.DeactivateTeamMembersInput
+		setup   func(r *mocks.MockRepositoryMockRecorder)
+		want    *models.DeactivateTeamMembersOutput
+		wantErr error
+	}{
+		{
+			name:  "deactivates and reassigns, reports NO_CANDIDATE reviews",
+			ctx:   adminCtx,
+			input: models.DeactivateTeamMembersInput{TeamName: "backend", UserIDs: []string{"u1", "u2"}, Reason: reason},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				planned(r)
+				r.ApplyReviewerReplacements(gomock.Any(), plan, reason).Return(nil)
+			},
+			want: &models.DeactivateTeamMembersOutput{
+				TeamName:    "backend",
+				Deactivated: []string{"u1", "u2"},
+				Reassigned: []models.ReviewReassignment{
+					{PullRequestID: "pr-1", OldUserID: "u1", NewUserID: "u3"},
+					{PullRequestID: "pr-2", OldUserID: "u2", NewUserID: "f1"},
+				},
+				NoCandidate: []models.UnreassignedReview{{PullRequestID: "pr-3", UserID: "u2"}},
+			},
+		},
+		{
+			name:  "respects max open reviews across the batch",
+			ctx:   adminCtx,
+			input: models.DeactivateTeamMembersInput{TeamName: "backend", UserIDs: []string{"u1", "u2"}},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				deactivated(r)
+				r.LockOpenReviews(gomock.Any(), []string{"u1", "u2"}).Return([]repository.OpenReview{
+					{PullRequestID: "pr-1", AuthorID: "u4", Reviewers: []string{"u1"}},
+					{PullRequestID: "pr-2", AuthorID: "u4", Reviewers: []string{"u2"}},
+				}, nil)
+				r.GetTeamSettings(gomock.Any(), int64(1)).Return(&repository.TeamSettingsModel{TeamID: 1, ReviewersCount: 2, MaxOpenReviews: 1}, nil)
+				r.GetActiveUsersWithLoad(gomock.Any(), int64(1)).Return(candidates(1, "u3"), nil)
+				r.GetFallbackTeams(gomock.Any(), int64(1)).Return(nil, nil)
+				r.ApplyReviewerReplacements(gomock.Any(), []repository.ReviewerReplacement{
+					{PullRequestID: "pr-1", OldReviewerUserID: "u1", NewReviewerUserID: ptr("u3")},
+					{PullRequestID: "pr-2", OldReviewerUserID: "u2"},
+				}, nil).Return(nil)
+			},
+			want: &models.DeactivateTeamMembersOutput{
+				TeamName:    "backend",
+				Deactivated: []string{"u1", "u2"},
+				Reassigned:  []models.ReviewReassignment{{PullRequestID: "pr-1", OldUserID: "u1", NewUserID: "u3"}},
+				NoCandidate: []models.UnreassignedReview{{PullRequestID: "pr-2", UserID: "u2"}},
+			},
+		},
+		{
+			name:  "nothing to deactivate",
+			ctx:   adminCtx,
+			input: models.DeactivateTeamMembersInput{TeamName: "backend", UserIDs: []string{"u3"}, AllExcept: true},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetTeamByName(gomock.Any(), "backend").Return(backend, nil)
+				r.GetUsersByTeamID(gomock.Any(), int64(1)).Return(team, nil)
+				r.DeactivateTeamMembers(gomock.Any(), int64(1), []string{"u3"}, true).Return(nil, nil)
+			},
+			want: &models.DeactivateTeamMembersOutput{
+				TeamName:    "backend",
+				Deactivated: []string{},
+				Reassigned:  []models.ReviewReassignment{},
+				NoCandidate: []models.UnreassignedReview{},
+			},
+		},
+		{
+			name:  "team lead deactivates own team",
+			ctx:   func(t *testing.T) context.Context { return userCtx(t, "lead") },
+			input: models.DeactivateTeamMembersInput{TeamName: "backend", UserIDs: []string{"u1", "u2"}, Reason: reason},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetUserByID(gomock.Any(), "lead").Return(user("lead", 1, service.RoleTeamLead), nil)
+				planned(r)
+				r.ApplyReviewerReplacements(gomock.Any(), plan, reason).Return(nil)
+			},
+			want: &models.DeactivateTeamMembersOutput{
+				TeamName:    "backend",
+				Deactivated: []string{"u1", "u2"},
+				Reassigned: []models.ReviewReassignment{
+					{PullRequestID: "pr-1", OldUserID: "u1", NewUserID: "u3"},
+					{PullRequestID: "pr-2", OldUserID: "u2", NewUserID: "f1"},
+				},
+				NoCandidate: []models.UnreassignedReview{{PullRequestID: "pr-3", UserID: "u2"}},
+			},
+		},
+		{
+			name:  "FORBIDDEN for lead of another team",
+			ctx:   func(t *testing.T) context.Context { return userCtx(t, "lead") },
+			input: models.DeactivateTeamMembersInput{TeamName: "backend", UserIDs: []string{"u1"}},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetUserByID(gomock.Any(), "lead").Return(user("lead", 2, service.RoleTeamLead), nil)
+				r.GetTeamByName(gomock.Any(), "backend").Return(backend, nil)
+			},
+			wantErr: apperr.ErrForbidden,
+		},
+		{
+			name:  "FORBIDDEN for member",
+			ctx:   func(t *testing.T) context.Context { return userCtx(t, "u3") },
+			input: models.DeactivateTeamMembersInput{TeamName: "backend", UserIDs: []string{"u1"}},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetUserByID(gomock.Any(), "u3").Return(user("u3", 1, service.RoleMember), nil)
+			},
+			wantErr: apperr.ErrForbidden,
+		},
+		{
+			name:  "NOT_FOUND for unknown team",
+			ctx:   adminCtx,
+			input: models.DeactivateTeamMembersInput{TeamName: "backend", UserIDs: []string{"u1"}},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetTeamByName(gomock.Any(), "backend").Return(nil, repository.ErrNotFound)
+			},
+			wantErr: apperr.ErrNotFound,
+		},
+		{
+			name:  "repository failure on team",
+			ctx:   adminCtx,
+			input: models.DeactivateTeamMembersInput{TeamName: "backend", UserIDs: []string{"u1"}},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetTeamByName(gomock.Any(), "backend").Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name:  "NOT_FOUND for non-member",
+			ctx:   adminCtx,
+			input: models.DeactivateTeamMembersInput{TeamName: "backend", UserIDs: []string{"u1", "ghost"}},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetTeamByName(gomock.Any(), "backend").Return(backend, nil)
+				r.GetUsersByTeamID(gomock.Any(), int64(1)).Return(team, nil)
+			},
+			wantErr: apperr.ErrNotFound,
+		},
+		{
+			name:  "repository failure on apply",
+			ctx:   adminCtx,
+			input: models.DeactivateTeamMembersInput{TeamName: "backend", UserIDs: []string{"u1", "u2"}, Reason: reason},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				planned(r)
+				r.ApplyReviewerReplacements(gomock.Any(), plan, reason).Return(errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name:  "repository failure on lock",
+			ctx:   adminCtx,
+			input: models.DeactivateTeamMembersInput{TeamName: "backend", UserIDs: []string{"u1", "u2"}},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				deactivated(r)
+				r.LockOpenReviews(gomock.Any(), []string{"u1", "u2"}).Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name:  "repository failure on candidates",
+			ctx:   adminCtx,
+			input: models.DeactivateTeamMembersInput{TeamName: "backend", UserIDs: []string{"u1", "u2"}},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				deactivated(r)
+				r.LockOpenReviews(gomock.Any(), []string{"u1", "u2"}).Return(reviews, nil)
+				r.GetTeamSettings(gomock.Any(), int64(1)).Return(nil, nil)
+				r.GetActiveUsersWithLoad(gomock.Any(), int64(1)).Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, r := newService(t)
+			tt.setup(r)
+
+			got, err := svc.DeactivateTeamMembers(tt.ctx(t), tt.input)
+			if tt.wantErr != nil {
+				requireErr(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
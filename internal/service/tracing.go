@@ -0,0 +1,116 @@
+package service
+
+import (
+	"avito-test-quest/internal/models"
+	"avito-test-quest/internal/tracing"
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracedService оборачивает каждый метод сервиса в спан OpenTelemetry
+type tracedService struct {
+	next   Service
+	tracer trace.Tracer
+}
+
+// NewTracedService создает обертку над next, открывающую спан на каждый вызов.
+// Без настроенного TracerProvider спаны не записываются
+func NewTracedService(next Service) Service {
+	return &tracedService{next: next, tracer: otel.Tracer("avito-test-quest/internal/service")}
+}
+
+func (s *tracedService) CreateTeam(ctx context.Context, input models.CreateTeamInput) (*models.Team, error) {
+	ctx, span := s.tracer.Start(ctx, "PrService.CreateTeam")
+	res, err := s.next.CreateTeam(ctx, input)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedService) GetTeam(ctx context.Context, teamName string) (*models.Team, error) {
+	ctx, span := s.tracer.Start(ctx, "PrService.GetTeam")
+	res, err := s.next.GetTeam(ctx, teamName)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedService) GetTeamSettings(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+	ctx, span := s.tracer.Start(ctx, "PrService.GetTeamSettings")
+	res, err := s.next.GetTeamSettings(ctx, teamName)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedService) UpdateTeamSettings(ctx context.Context, input models.UpdateTeamSettingsInput) (*models.TeamSettings, error) {
+	ctx, span := s.tracer.Start(ctx, "PrService.UpdateTeamSettings")
+	res, err := s.next.UpdateTeamSettings(ctx, input)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedService) DeactivateTeamMembers(ctx context.Context, input models.DeactivateTeamMembersInput) (*models.DeactivateTeamMembersOutput, error) {
+	ctx, span := s.tracer.Start(ctx, "PrService.DeactivateTeamMembers")
+	res, err := s.next.DeactivateTeamMembers(ctx, input)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedService) SetIsActive(ctx context.Context, input models.SetIsActiveInput) (*models.User, error) {
+	ctx, span := s.tracer.Start(ctx, "PrService.SetIsActive")
+	res, err := s.next.SetIsActive(ctx, input)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedService) SetUserRole(ctx context.Context, input models.SetRoleInput) (*models.User, error) {
+	ctx, span := s.tracer.Start(ctx, "PrService.SetUserRole")
+	res, err := s.next.SetUserRole(ctx, input)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedService) GetUserReviews(ctx context.Context, userID string) (*models.UserReviewsOutput, error) {
+	ctx, span := s.tracer.Start(ctx, "PrService.GetUserReviews")
+	res, err := s.next.GetUserReviews(ctx, userID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedService) CreatePullRequest(ctx context.Context, input models.CreatePullRequestInput) (*models.PullRequest, error) {
+	ctx, span := s.tracer.Start(ctx, "PrService.CreatePullRequest")
+	res, err := s.next.CreatePullRequest(ctx, input)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedService) MergePullRequest(ctx context.Context, input models.MergePullRequestInput) (*models.PullRequest, error) {
+	ctx, span := s.tracer.Start(ctx, "PrService.MergePullRequest")
+	res, err := s.next.MergePullRequest(ctx, input)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedService) ReassignReviewer(ctx context.Context, input models.ReassignReviewerInput) (*models.ReassignReviewerOutput, error) {
+	ctx, span := s.tracer.Start(ctx, "PrService.ReassignReviewer")
+	res, err := s.next.ReassignReviewer(ctx, input)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedService) GetPullRequestHistory(ctx context.Context, prID string) (*models.PullRequestHistory, error) {
+	ctx, span := s.tracer.Start(ctx, "PrService.GetPullRequestHistory")
+	res, err := s.next.GetPullRequestHistory(ctx, prID)
+	tracing.End(span, err)
+	return res, err
+}
+
+func (s *tracedService) GetStats(ctx context.Context) (*models.StatsOutput, error) {
+	ctx, span := s.tracer.Start(ctx, "PrService.GetStats")
+	res, err := s.next.GetStats(ctx)
+	tracing.End(span, err)
+	return res, err
+}
+
+// проверка реализации интерфейса Service
+var _ Service = (*tracedService)(nil)
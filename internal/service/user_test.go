@@ -0,0 +1,274 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"avito-test-quest/internal/apperr"
+	"avito-test-quest/internal/models"
+	"avito-test-quest/internal/repository"
+	"avito-test-quest/internal/repository/mocks"
+	"avito-test-quest/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSetIsActive(t *testing.T) {
+	inactive := user("u1", 1, service.RoleMember)
+	inactive.IsActive = false
+
+	tests := []struct {
+		name    string
+		ctx     func(t *testing.T) context.Context
+		input   models.SetIsActiveInput
+		setup   func(r *mocks.MockRepositoryMockRecorder)
+		want    *models.User
+		wantErr error
+	}{
+		{
+			name:  "admin deactivates user",
+			ctx:   adminCtx,
+			input: models.SetIsActiveInput{UserID: "u1", IsActive: false},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetUserByID(gomock.Any(), "u1").Return(user("u1", 1, service.RoleMember), nil)
+				r.SetIsActive(gomock.Any(), "u1", false).Return(inactive, nil)
+				r.GetTeamByID(gomock.Any(), int64(1)).Return(backend, nil)
+			},
+			want: &models.User{UserID: "u1", Username: "name-u1", TeamName: "backend", IsActive: false, Role: service.RoleMember},
+		},
+		{
+			name:  "team lead activates member and tops up reviewers",
+			ctx:   func(t *testing.T) context.Context { return userCtx(t, "lead") },
+			input: models.SetIsActiveInput{UserID: "u1", IsActive: true},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetUserByID(gomock.Any(), "lead").Return(user("lead", 1, service.RoleTeamLead), nil)
+				r.GetUserByID(gomock.Any(), "u1").Return(inactive, nil)
+				r.SetIsActive(gomock.Any(), "u1", true).Return(user("u1", 1, service.RoleMember), nil)
+				r.GetOpenPRsNeedingReviewers(gomock.Any(), int64(1)).Return([]repository.PullRequestModel{*openPR("pr-1", "u2")}, nil)
+				r.GetTeamSettings(gomock.Any(), int64(1)).Return(nil, nil)
+				r.GetReviewersByPRID(gomock.Any(), "pr-1").Return(nil, nil)
+				r.GetActiveUsersWithLoad(gomock.Any(), int64(1)).Return(candidates(1, "u1", "u2"), nil)
+				r.GetFallbackTeams(gomock.Any(), int64(1)).Return(nil, nil)
+				// второго ревьювера нет: флаг нехватки остается
+				r.AssignReviewer(gomock.Any(), "pr-1", "u1", false).Return(nil)
+				r.GetTeamByID(gomock.Any(), int64(1)).Return(backend, nil)
+			},
+			want: &models.User{UserID: "u1", Username: "name-u1", TeamName: "backend", IsActive: true, Role: service.RoleMember},
+		},
+		{
+			name:  "FORBIDDEN for member",
+			ctx:   func(t *testing.T) context.Context { return userCtx(t, "u2") },
+			input: models.SetIsActiveInput{UserID: "u1", IsActive: false},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetUserByID(gomock.Any(), "u2").Return(user("u2", 1, service.RoleMember), nil)
+			},
+			wantErr: apperr.ErrForbidden,
+		},
+		{
+			name:  "FORBIDDEN for lead of another team",
+			ctx:   func(t *testing.T) context.Context { return userCtx(t, "lead") },
+			input: models.SetIsActiveInput{UserID: "u1", IsActive: false},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetUserByID(gomock.Any(), "lead").Return(user("lead", 2, service.RoleTeamLead), nil)
+				r.GetUserByID(gomock.Any(), "u1").Return(user("u1", 1, service.RoleMember), nil)
+			},
+			wantErr: apperr.ErrForbidden,
+		},
+		{
+			name:    "UNAUTHORIZED without principal",
+			ctx:     baseCtx,
+			input:   models.SetIsActiveInput{UserID: "u1", IsActive: false},
+			setup:   func(*mocks.MockRepositoryMockRecorder) {},
+			wantErr: apperr.ErrUnauthorized,
+		},
+		{
+			name:  "NOT_FOUND",
+			ctx:   adminCtx,
+			input: models.SetIsActiveInput{UserID: "ghost", IsActive: false},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetUserByID(gomock.Any(), "ghost").Return(nil, repository.ErrNotFound)
+			},
+			wantErr: apperr.ErrNotFound,
+		},
+		{
+			name:  "repository failure on user",
+			ctx:   adminCtx,
+			input: models.SetIsActiveInput{UserID: "u1", IsActive: false},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetUserByID(gomock.Any(), "u1").Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name:  "repository failure on caller",
+			ctx:   func(t *testing.T) context.Context { return userCtx(t, "lead") },
+			input: models.SetIsActiveInput{UserID: "u1", IsActive: false},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetUserByID(gomock.Any(), "lead").Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name:  "repository failure on update",
+			ctx:   adminCtx,
+			input: models.SetIsActiveInput{UserID: "u1", IsActive: false},
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetUserByID(gomock.Any(), "u1").Return(user("u1", 1, service.RoleMember), nil)
+				r.SetIsActive(gomock.Any(), "u1", false).Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, r := newService(t)
+			tt.setup(r)
+
+			got, err := svc.SetIsActive(tt.ctx(t), tt.input)
+			if tt.wantErr != nil {
+				requireErr(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSetUserRole(t *testing.T) {
+	input := models.SetRoleInput{UserID: "u1", Role: service.RoleTeamLead}
+
+	tests := []struct {
+		name    string
+		ctx     func(t *testing.T) context.Context
+		setup   func(r *mocks.MockRepositoryMockRecorder)
+		want    *models.User
+		wantErr error
+	}{
+		{
+			name: "admin sets role",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.SetUserRole(gomock.Any(), "u1", service.RoleTeamLead).Return(user("u1", 1, service.RoleTeamLead), nil)
+				r.GetTeamByID(gomock.Any(), int64(1)).Return(backend, nil)
+			},
+			want: &models.User{UserID: "u1", Username: "name-u1", TeamName: "backend", IsActive: true, Role: service.RoleTeamLead},
+		},
+		{
+			name: "FORBIDDEN for team lead",
+			ctx:  func(t *testing.T) context.Context { return userCtx(t, "lead") },
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetUserByID(gomock.Any(), "lead").Return(user("lead", 1, service.RoleTeamLead), nil)
+			},
+			wantErr: apperr.ErrForbidden,
+		},
+		{
+			name: "NOT_FOUND",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.SetUserRole(gomock.Any(), "u1", service.RoleTeamLead).Return(nil, repository.ErrNotFound)
+			},
+			wantErr: apperr.ErrNotFound,
+		},
+		{
+			name: "repository failure on update",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.SetUserRole(gomock.Any(), "u1", service.RoleTeamLead).Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+		{
+			name: "repository failure on team",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.SetUserRole(gomock.Any(), "u1", service.RoleTeamLead).Return(user("u1", 1, service.RoleTeamLead), nil)
+				r.GetTeamByID(gomock.Any(), int64(1)).Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, r := newService(t)
+			tt.setup(r)
+
+			got, err := svc.SetUserRole(tt.ctx(t), input)
+			if tt.wantErr != nil {
+				requireErr(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetUserReviews(t *testing.T) {
+	needMore := openPR("pr-1", "u2")
+	needMore.NeedMoreReviewers = true
+
+	tests := []struct {
+		name    string
+		ctx     func(t *testing.T) context.Context
+		setup   func(r *mocks.MockRepositoryMockRecorder)
+		want    *models.UserReviewsOutput
+		wantErr error
+	}{
+		{
+			name: "returns PRs under review",
+			ctx:  func(t *testing.T) context.Context { return userCtx(t, "u1") },
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.GetUserByID(gomock.Any(), "u1").Return(user("u1", 1, service.RoleMember), nil)
+				r.UserExists(gomock.Any(), "u1").Return(true, nil)
+				r.GetPRsByReviewerID(gomock.Any(), "u1").Return([]repository.PullRequestModel{*needMore, *mergedPR("pr-2", "u3")}, nil)
+			},
+			want: &models.UserReviewsOutput{UserID: "u1", PullRequests: []models.PullRequestShort{
+				{PullRequestID: "pr-1", PullRequestName: "name-pr-1", AuthorID: "u2", Status: "OPEN", NeedMoreReviewers: true},
+				{PullRequestID: "pr-2", PullRequestName: "name-pr-2", AuthorID: "u3", Status: "MERGED"},
+			}},
+		},
+		{
+			name:    "UNAUTHORIZED without principal",
+			ctx:     baseCtx,
+			setup:   func(*mocks.MockRepositoryMockRecorder) {},
+			wantErr: apperr.ErrUnauthorized,
+		},
+		{
+			name: "NOT_FOUND",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.UserExists(gomock.Any(), "u1").Return(false, nil)
+			},
+			wantErr: apperr.ErrNotFound,
+		},
+		{
+			name: "repository failure on reviews",
+			ctx:  adminCtx,
+			setup: func(r *mocks.MockRepositoryMockRecorder) {
+				r.UserExists(gomock.Any(), "u1").Return(true, nil)
+				r.GetPRsByReviewerID(gomock.Any(), "u1").Return(nil, errDB)
+			},
+			wantErr: errDB,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, r := newService(t)
+			tt.setup(r)
+
+			got, err := svc.GetUserReviews(tt.ctx(t), "u1")
+			if tt.wantErr != nil {
+				requireErr(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"avito-test-quest/internal/logger"
+	"avito-test-quest/migrations"
+
+	"github.com/golang-migrate/migrate/v4"
+	sqlitemigrate "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"go.uber.org/zap"
+)
+
+// Migrator применяет встроенные миграции SQLite
+type Migrator struct {
+	m *migrate.Migrate
+}
+
+// NewMigrator открывает файл БД и встроенные миграции SQLite.
+// Повторных попыток нет: файл локальный, и ошибка открытия не исчезнет сама
+func NewMigrator(cfg Config) (*Migrator, error) {
+	db, err := open(cfg)
+	if err != nil {
+		return nil, err
+	}
+	driver, err := sqlitemigrate.WithInstance(db, &sqlitemigrate.Config{})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create migration instance: %w", err)
+	}
+	src, err := iofs.New(migrations.SQLiteFS, "sqlite")
+	if err != nil {
+		_ = driver.Close()
+		return nil, fmt.Errorf("failed to open embedded migrations: %w", err)
+	}
+	m, err := migrate.NewWithInstance("iofs", src, "sqlite", driver)
+	if err != nil {
+		_ = src.Close()
+		_ = driver.Close()
+		return nil, fmt.Errorf("failed to create migration instance: %w", err)
+	}
+
+	return &Migrator{m: m}, nil
+}
+
+// Up применяет все непримененные миграции
+func (mg *Migrator) Up() error {
+	if err := mg.m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+// Down откатывает steps последних миграций
+func (mg *Migrator) Down(steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("steps must be positive, got %d", steps)
+	}
+	if err := mg.m.Steps(-steps); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+// Version возвращает примененную версию схемы; 0 — миграции не применялись
+func (mg *Migrator) Version() (version uint, dirty bool, err error) {
+	version, dirty, err = mg.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
+
+// Force записывает версию схемы без выполнения миграций и снимает флаг dirty
+func (mg *Migrator) Force(version int) error {
+	return mg.m.Force(version)
+}
+
+// Close закрывает файл БД
+func (mg *Migrator) Close() error {
+	srcErr, dbErr := mg.m.Close()
+	return errors.Join(srcErr, dbErr)
+}
+
+// Migrate применяет встроенные миграции при запуске сервиса
+func Migrate(ctx context.Context, cfg Config) error {
+	mg, err := NewMigrator(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = mg.Close() }()
+
+	if err := mg.Up(); err != nil {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+	version, _, err := mg.Version()
+	if err != nil {
+		return fmt.Errorf("failed to read migration version: %w", err)
+	}
+
+	logger.GetOrCreateLoggerFromCtx(ctx).Info(ctx, "migrated successfully", zap.Uint("version", version))
+	return nil
+}
@@ -0,0 +1,60 @@
+// Package sqlite открывает файл SQLite и применяет к нему встроенные миграции.
+// Используется драйвер modernc.org/sqlite без cgo, сервис остается одним бинарником
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	_ "modernc.org/sqlite" // драйвер database/sql "sqlite"
+)
+
+// Config содержит настройки хранилища SQLite
+type Config struct {
+	// Path путь к файлу БД; каталог создается при открытии
+	Path string `yaml:"path" env:"SQLITE_PATH" env-default:"data/pr-reviewer.db"`
+	// SkipMigrations не применять миграции при запуске serve: схему обновляет отдельный "migrate up"
+	SkipMigrations bool `yaml:"skip_migrations" env:"SQLITE_SKIP_MIGRATIONS"`
+}
+
+// busyTimeoutMs сколько ждать блокировку файла, которую держит другой процесс (например, migrate)
+const busyTimeoutMs = 5000
+
+// New открывает БД SQLite
+// SQLite допускает одного писателя, поэтому используется одно соединение: транзакции процесса
+// выполняются по очереди и не получают SQLITE_BUSY, а вложенные savepoint идут в том же соединении
+func New(ctx context.Context, cfg Config) (*sql.DB, error) {
+	db, err := open(cfg)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to open sqlite %s: %w", cfg.Path, err)
+	}
+
+	return db, nil
+}
+
+// open создает каталог БД и открывает её с внешними ключами, WAL и ожиданием блокировок.
+// Транзакции начинаются с BEGIN IMMEDIATE: блокировка записи берется сразу, как FOR UPDATE в Postgres,
+// и чтение внутри транзакции не устаревает к моменту записи
+func open(cfg Config) (*sql.DB, error) {
+	if dir := filepath.Dir(cfg.Path); dir != "." {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return nil, fmt.Errorf("failed to create sqlite directory: %w", err)
+		}
+	}
+	dsn := fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(%d)&_txlock=immediate", cfg.Path, busyTimeoutMs)
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite %s: %w", cfg.Path, err)
+	}
+
+	return db, nil
+}
@@ -0,0 +1,43 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"avito-test-quest/migrations"
+)
+
+// LatestMigrationVersion возвращает версию последней встроенной миграции SQLite
+func LatestMigrationVersion() (uint, error) {
+	return migrations.Latest(migrations.SQLiteFS, "sqlite")
+}
+
+// MigrationVersion возвращает примененную версию схемы из таблицы golang-migrate.
+// Версия 0 без ошибки — миграции не запускались
+func MigrationVersion(ctx context.Context, db *sql.DB) (version uint, dirty bool, err error) {
+	var v int64
+	err = db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&v, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return uint(v), dirty, nil
+}
+
+// DB соединение SQLite для проверки готовности: ping и версия схемы
+type DB struct {
+	*sql.DB
+}
+
+// Ping проверяет, что файл БД доступен
+func (db DB) Ping(ctx context.Context) error {
+	return db.PingContext(ctx)
+}
+
+// MigrationVersion возвращает примененную версию схемы
+func (db DB) MigrationVersion(ctx context.Context) (version uint, dirty bool, err error) {
+	return MigrationVersion(ctx, db.DB)
+}
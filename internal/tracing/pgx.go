@@ -0,0 +1,56 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// QueryTracer pgx.QueryTracer, создающий спан на каждый запрос к Postgres с текстом SQL.
+// Значения параметров в спан не пишутся: в них могут быть персональные данные
+type QueryTracer struct {
+	tracer trace.Tracer
+}
+
+// NewQueryTracer создает трейсер для pgx.ConnConfig.Tracer
+func NewQueryTracer() *QueryTracer {
+	return &QueryTracer{tracer: otel.Tracer("avito-test-quest/internal/postgres")}
+}
+
+// TraceQueryStart открывает спан запроса
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	op := operationName(data.SQL)
+	ctx, _ = t.tracer.Start(ctx, op,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemNamePostgreSQL,
+			semconv.DBOperationName(op),
+			semconv.DBQueryText(data.SQL),
+		),
+	)
+	return ctx
+}
+
+// TraceQueryEnd закрывает спан запроса
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	if data.Err != nil {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
+	}
+	span.End()
+}
+
+// operationName первое слово SQL в верхнем регистре: SELECT, INSERT, WITH, BEGIN
+func operationName(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "QUERY"
+	}
+	return strings.ToUpper(fields[0])
+}
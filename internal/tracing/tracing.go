@@ -0,0 +1,115 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"avito-test-quest/internal/apperr"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// экспортеры спанов
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// Config содержит настройки трассировки
+type Config struct {
+	// Exporter куда отправлять спаны: none (трассировка выключена), otlp или stdout
+	Exporter string `yaml:"exporter" env:"TRACING_EXPORTER" env-default:"none"`
+	// OTLPEndpoint адрес коллектора OTLP/HTTP (host:port); пусто — OTEL_EXPORTER_OTLP_ENDPOINT или localhost:4318
+	OTLPEndpoint string `yaml:"otlp_endpoint" env:"TRACING_OTLP_ENDPOINT"`
+	// OTLPInsecure отправлять спаны по HTTP без TLS
+	OTLPInsecure bool `yaml:"otlp_insecure" env:"TRACING_OTLP_INSECURE"`
+	// File файл для экспортера stdout (JSON по спану на строку); пусто — стандартный вывод
+	File string `yaml:"file" env:"TRACING_FILE"`
+	// ServiceName имя сервиса в спанах
+	ServiceName string `yaml:"service_name" env:"TRACING_SERVICE_NAME" env-default:"pr-reviewer"`
+	// SampleRatio доля трассируемых запросов от 0 до 1; решение родительского спана соблюдается
+	SampleRatio float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO" env-default:"1"`
+}
+
+// New настраивает глобальный TracerProvider и распространение контекста W3C Trace Context.
+// Возвращает функцию, которая выгружает накопленные спаны и закрывает экспортер.
+// При exporter none провайдер не устанавливается, и спаны ничего не стоят
+func New(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var (
+		exporter sdktrace.SpanExporter
+		closer   io.Closer
+		err      error
+	)
+	switch cfg.Exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if cfg.OTLPEndpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.OTLPEndpoint))
+		}
+		if cfg.OTLPInsecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(ctx, opts...)
+	case ExporterStdout:
+		w := io.Writer(os.Stdout)
+		if cfg.File != "" {
+			f, ferr := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+			if ferr != nil {
+				return nil, fmt.Errorf("failed to open trace file: %w", ferr)
+			}
+			w, closer = f, f
+		}
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(w))
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q: expected none, otlp or stdout", cfg.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s trace exporter: %w", cfg.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(cfg.ServiceName)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(tp)
+
+	return func(ctx context.Context) error {
+		err := tp.Shutdown(ctx)
+		if closer != nil {
+			err = errors.Join(err, closer.Close())
+		}
+		return err
+	}, nil
+}
+
+// End завершает спан, отмечая ошибку и её доменный код
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes(attribute.String("app.error_code", string(apperr.CodeOf(err))))
+	}
+	span.End()
+}
@@ -0,0 +1,2 @@
+-- 000005_create_team_settings_table.down.sql
+DROP TABLE IF EXISTS team_settings;
\ No newline at end of file
@@ -0,0 +1,9 @@
+-- 000005_create_team_settings_table.up.sql
+CREATE TABLE IF NOT EXISTS team_settings (
+    team_id INTEGER PRIMARY KEY REFERENCES teams(id) ON DELETE CASCADE,
+    reviewers_count INTEGER NOT NULL DEFAULT 2 CHECK (reviewers_count >= 0),
+    strategy VARCHAR(32) NULL,
+    max_open_reviews INTEGER NOT NULL DEFAULT 0 CHECK (max_open_reviews >= 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    );
@@ -0,0 +1,3 @@
+-- 000006_add_fallback_reviewers.down.sql
+ALTER TABLE pr_reviewers DROP COLUMN IF EXISTS is_fallback;
+DROP TABLE IF EXISTS team_fallbacks;
\ No newline at end of file
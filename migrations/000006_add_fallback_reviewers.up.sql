@@ -0,0 +1,11 @@
+-- 000006_add_fallback_reviewers.up.sql
+CREATE TABLE IF NOT EXISTS team_fallbacks (
+    team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    fallback_team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (team_id, fallback_team_id),
+    UNIQUE (team_id, position),
+    CHECK (team_id <> fallback_team_id)
+    );
+
+ALTER TABLE pr_reviewers ADD COLUMN IF NOT EXISTS is_fallback BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,3 @@
+-- 000007_add_need_more_reviewers.down.sql
+DROP INDEX IF EXISTS idx_pr_need_more_reviewers;
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS need_more_reviewers;
@@ -0,0 +1,4 @@
+-- 000007_add_need_more_reviewers.up.sql
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS need_more_reviewers BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS idx_pr_need_more_reviewers ON pull_requests(need_more_reviewers) WHERE status = 'OPEN';
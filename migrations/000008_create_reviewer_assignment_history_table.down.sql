@@ -0,0 +1,2 @@
+-- 000008_create_reviewer_assignment_history_table.down.sql
+DROP TABLE IF EXISTS reviewer_assignment_history;
@@ -0,0 +1,11 @@
+-- 000008_create_reviewer_assignment_history_table.up.sql
+CREATE TABLE IF NOT EXISTS reviewer_assignment_history (
+    id SERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(255) NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    old_reviewer_user_id VARCHAR(255) NULL,
+    new_reviewer_user_id VARCHAR(255) NOT NULL,
+    reassigned_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    reason TEXT NULL
+    );
+
+CREATE INDEX idx_reviewer_history_pr_id ON reviewer_assignment_history(pull_request_id, reassigned_at);
@@ -0,0 +1,2 @@
+-- 000009_add_user_role.down.sql
+ALTER TABLE users DROP COLUMN IF EXISTS role;
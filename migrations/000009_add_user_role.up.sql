@@ -0,0 +1,3 @@
+-- 000009_add_user_role.up.sql
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'member'
+    CHECK (role IN ('admin', 'team_lead', 'member'));
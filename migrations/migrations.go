@@ -0,0 +1,46 @@
+// Package migrations встраивает SQL миграции в бинарник
+package migrations
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// FS файлы миграций Postgres для golang-migrate: NNNNNN_name.up.sql и NNNNNN_name.down.sql
+//
+//go:embed *.sql
+var FS embed.FS
+
+// SQLiteFS миграции SQLite в каталоге sqlite; схема та же, что у Postgres, с поправкой на диалект
+//
+//go:embed sqlite/*.sql
+var SQLiteFS embed.FS
+
+// Latest возвращает версию последней миграции в каталоге dir встроенного набора
+func Latest(fsys fs.FS, dir string) (uint, error) {
+	src, err := iofs.New(fsys, dir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open embedded migrations: %w", err)
+	}
+	defer src.Close()
+
+	version, err := src.First()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read embedded migrations: %w", err)
+	}
+	for {
+		next, err := src.Next(version)
+		if errors.Is(err, os.ErrNotExist) || errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read embedded migrations: %w", err)
+		}
+		version = next
+	}
+}
@@ -0,0 +1,8 @@
+-- 000001_init.down.sql
+DROP TABLE IF EXISTS reviewer_assignment_history;
+DROP TABLE IF EXISTS team_fallbacks;
+DROP TABLE IF EXISTS team_settings;
+DROP TABLE IF EXISTS pr_reviewers;
+DROP TABLE IF EXISTS pull_requests;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS teams;
@@ -0,0 +1,80 @@
+-- 000001_init.up.sql
+-- Схема SQLite соответствует миграциям Postgres 000001-000009:
+-- SERIAL -> INTEGER PRIMARY KEY AUTOINCREMENT (id не переиспользуются), enum pr_status -> TEXT с CHECK,
+-- CURRENT_TIMESTAMP -> время с миллисекундами в UTC
+CREATE TABLE IF NOT EXISTS teams (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    team_name TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+    );
+
+CREATE TABLE IF NOT EXISTS users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id TEXT NOT NULL UNIQUE,
+    username TEXT NOT NULL,
+    team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    role TEXT NOT NULL DEFAULT 'member' CHECK (role IN ('admin', 'team_lead', 'member')),
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+    );
+
+CREATE INDEX idx_users_team_id ON users(team_id);
+CREATE INDEX idx_users_is_active ON users(is_active);
+
+CREATE TABLE IF NOT EXISTS pull_requests (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    pull_request_id TEXT NOT NULL UNIQUE,
+    pull_request_name TEXT NOT NULL,
+    author_id TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    status TEXT NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'MERGED')),
+    need_more_reviewers BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    merged_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+    );
+
+CREATE INDEX idx_pr_author_id ON pull_requests(author_id);
+CREATE INDEX idx_pr_status ON pull_requests(status);
+CREATE INDEX idx_pr_need_more_reviewers ON pull_requests(need_more_reviewers) WHERE status = 'OPEN';
+
+CREATE TABLE IF NOT EXISTS pr_reviewers (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    reviewer_user_id TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    is_fallback BOOLEAN NOT NULL DEFAULT false,
+    assigned_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    UNIQUE(pull_request_id, reviewer_user_id)
+    );
+
+CREATE INDEX idx_pr_reviewers_user_id ON pr_reviewers(reviewer_user_id);
+
+CREATE TABLE IF NOT EXISTS team_settings (
+    team_id INTEGER PRIMARY KEY REFERENCES teams(id) ON DELETE CASCADE,
+    reviewers_count INTEGER NOT NULL DEFAULT 2 CHECK (reviewers_count >= 0),
+    strategy TEXT NULL,
+    max_open_reviews INTEGER NOT NULL DEFAULT 0 CHECK (max_open_reviews >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+    );
+
+CREATE TABLE IF NOT EXISTS team_fallbacks (
+    team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    fallback_team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (team_id, fallback_team_id),
+    UNIQUE (team_id, position),
+    CHECK (team_id <> fallback_team_id)
+    );
+
+CREATE TABLE IF NOT EXISTS reviewer_assignment_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    old_reviewer_user_id TEXT NULL,
+    new_reviewer_user_id TEXT NOT NULL,
+    reassigned_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    reason TEXT NULL
+    );
+
+CREATE INDEX idx_reviewer_history_pr_id ON reviewer_assignment_history(pull_request_id, reassigned_at);
@@ -0,0 +1,87 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthentication(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	// errorCode возвращает код ошибки из ответа
+	errorCode := func(t *testing.T, resp *http.Response) string {
+		var result map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		errObj := result["error"].(map[string]interface{})
+		return errObj["code"].(string)
+	}
+
+	t.Run("NoToken_Unauthorized", func(t *testing.T) {
+		cleanupTestData(t)
+
+		resp := makeRequest(t, "POST", "/pullRequest/merge", map[string]interface{}{
+			"pull_request_id": "pr-1",
+		}, map[string]string{"Authorization": ""})
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Contains(t, resp.Header.Get("WWW-Authenticate"), "Bearer")
+		assert.Equal(t, "UNAUTHORIZED", errorCode(t, resp))
+	})
+
+	t.Run("UnknownToken_Unauthorized", func(t *testing.T) {
+		cleanupTestData(t)
+
+		resp := makeRequest(t, "GET", "/team/get?team_name=backend", nil,
+			map[string]string{"Authorization": "Bearer wrong-token"})
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Equal(t, "UNAUTHORIZED", errorCode(t, resp))
+	})
+
+	t.Run("UserToken_AdminEndpoint_Forbidden", func(t *testing.T) {
+		cleanupTestData(t)
+
+		createTestTeam(t, "backend", []map[string]interface{}{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+		})
+
+		resp := makeRequest(t, "POST", "/users/setIsActive", map[string]interface{}{
+			"user_id":   "u1",
+			"is_active": false,
+		}, map[string]string{"Authorization": "Bearer " + testUserToken("u1")})
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Equal(t, "FORBIDDEN", errorCode(t, resp))
+	})
+
+	t.Run("UserToken_ReadEndpoint_Allowed", func(t *testing.T) {
+		cleanupTestData(t)
+
+		createTestTeam(t, "backend", []map[string]interface{}{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+		})
+
+		resp := makeRequest(t, "GET", "/users/getReview?user_id=u1", nil,
+			map[string]string{"Authorization": "Bearer " + testUserToken("u1")})
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("PublicEndpoint_NoToken", func(t *testing.T) {
+		cleanupTestData(t)
+
+		resp := makeRequest(t, "GET", "/health", nil, map[string]string{"Authorization": ""})
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
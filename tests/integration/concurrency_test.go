@@ -0,0 +1,262 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parallelResult результат одного запроса из параллельной серии
+type parallelResult struct {
+	status int
+	code   string
+	err    error
+}
+
+// runParallel одновременно выполняет n запросов и возвращает их результаты
+// Запросы выполняются без require, чтобы не вызывать FailNow вне горутины теста
+func runParallel(n int, fn func(i int) parallelResult) []parallelResult {
+	results := make([]parallelResult, n)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			results[i] = fn(i)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	return results
+}
+
+// postJSON отправляет POST запрос и возвращает статус и код ошибки из ответа
+func postJSON(path string, body interface{}) parallelResult {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return parallelResult{err: err}
+	}
+	req, err := http.NewRequest(http.MethodPost, testBaseURL+path, bytes.NewReader(data))
+	if err != nil {
+		return parallelResult{err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return parallelResult{err: err}
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+
+	return parallelResult{status: resp.StatusCode, code: result.Error.Code}
+}
+
+// prReviewers возвращает ревьюверов PR напрямую из БД
+func prReviewers(t *testing.T, prID string) []string {
+	rows, err := testDB.Query(context.Background(), "SELECT reviewer_user_id FROM pr_reviewers WHERE pull_request_id = $1", prID)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var res []string
+	for rows.Next() {
+		var uid string
+		require.NoError(t, rows.Scan(&uid))
+		res = append(res, uid)
+	}
+	require.NoError(t, rows.Err())
+	return res
+}
+
+// historyCount возвращает количество переназначений PR в истории
+func historyCount(t *testing.T, prID string) int {
+	var cnt int
+	err := testDB.QueryRow(context.Background(),
+		"SELECT count(1) FROM reviewer_assignment_history WHERE pull_request_id = $1 AND old_reviewer_user_id IS NOT NULL", prID).Scan(&cnt)
+	require.NoError(t, err)
+	return cnt
+}
+
+// assertReviewerInvariants проверяет инварианты набора ревьюверов PR
+func assertReviewerInvariants(t *testing.T, reviewers []string, authorID string, want int) {
+	assert.Len(t, reviewers, want, "reviewer count must be preserved")
+	seen := map[string]bool{}
+	for _, r := range reviewers {
+		assert.NotEqual(t, authorID, r, "author must not review own PR")
+		assert.False(t, seen[r], "reviewer %s assigned twice", r)
+		seen[r] = true
+	}
+}
+
+func TestConcurrentPullRequestOperations(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	const workers = 20
+
+	// команда из автора, двух ревьюверов и запаса кандидатов на замену
+	createTeamWithCandidates := func(t *testing.T) {
+		members := []map[string]interface{}{
+			{"user_id": "u1", "username": "Author", "is_active": true},
+		}
+		for i := 2; i <= 12; i++ {
+			members = append(members, map[string]interface{}{
+				"user_id": fmt.Sprintf("u%d", i), "username": fmt.Sprintf("User%d", i), "is_active": true,
+			})
+		}
+		createTestTeam(t, "backend", members)
+	}
+
+	t.Run("ParallelReassign_SameReviewer", func(t *testing.T) {
+		cleanupTestData(t)
+		createTeamWithCandidates(t)
+		createTestPR(t, "pr-1", "Test PR", "u1")
+		assignReviewer(t, "pr-1", "u2")
+		assignReviewer(t, "pr-1", "u3")
+
+		// все запросы пытаются заменить одного и того же ревьювера
+		results := runParallel(workers, func(int) parallelResult {
+			return postJSON("/pullRequest/reassign", map[string]interface{}{"pull_request_id": "pr-1", "old_user_id": "u2"})
+		})
+
+		ok := 0
+		for _, r := range results {
+			require.NoError(t, r.err)
+			switch r.status {
+			case http.StatusOK:
+				ok++
+			case http.StatusConflict:
+				assert.Equal(t, "NOT_ASSIGNED", r.code)
+			default:
+				t.Errorf("unexpected status %d (%s)", r.status, r.code)
+			}
+		}
+		assert.Equal(t, 1, ok, "exactly one reassign must succeed")
+
+		reviewers := prReviewers(t, "pr-1")
+		assertReviewerInvariants(t, reviewers, "u1", 2)
+		assert.NotContains(t, reviewers, "u2")
+		assert.Contains(t, reviewers, "u3")
+		assert.Equal(t, 1, historyCount(t, "pr-1"))
+	})
+
+	t.Run("ParallelReassign_BothReviewers", func(t *testing.T) {
+		cleanupTestData(t)
+		createTeamWithCandidates(t)
+		createTestPR(t, "pr-1", "Test PR", "u1")
+		assignReviewer(t, "pr-1", "u2")
+		assignReviewer(t, "pr-1", "u3")
+
+		// половина запросов меняет u2, половина — u3; параллельные замены не должны выбрать одного кандидата
+		results := runParallel(workers, func(i int) parallelResult {
+			old := "u2"
+			if i%2 == 1 {
+				old = "u3"
+			}
+			return postJSON("/pullRequest/reassign", map[string]interface{}{"pull_request_id": "pr-1", "old_user_id": old})
+		})
+
+		ok := 0
+		for _, r := range results {
+			require.NoError(t, r.err)
+			switch r.status {
+			case http.StatusOK:
+				ok++
+			case http.StatusConflict:
+				assert.Equal(t, "NOT_ASSIGNED", r.code)
+			default:
+				t.Errorf("unexpected status %d (%s)", r.status, r.code)
+			}
+		}
+		assert.Equal(t, 2, ok, "each reviewer must be replaced exactly once")
+
+		reviewers := prReviewers(t, "pr-1")
+		assertReviewerInvariants(t, reviewers, "u1", 2)
+		assert.NotContains(t, reviewers, "u2")
+		assert.NotContains(t, reviewers, "u3")
+		assert.Equal(t, ok, historyCount(t, "pr-1"))
+	})
+
+	t.Run("ParallelReassignAndMerge", func(t *testing.T) {
+		cleanupTestData(t)
+		createTeamWithCandidates(t)
+		createTestPR(t, "pr-1", "Test PR", "u1")
+		assignReviewer(t, "pr-1", "u2")
+		assignReviewer(t, "pr-1", "u3")
+
+		// первый запрос мержит PR, остальные пытаются переназначить ревьювера
+		results := runParallel(workers, func(i int) parallelResult {
+			if i == 0 {
+				return postJSON("/pullRequest/merge", map[string]interface{}{"pull_request_id": "pr-1"})
+			}
+			return postJSON("/pullRequest/reassign", map[string]interface{}{"pull_request_id": "pr-1", "old_user_id": "u2"})
+		})
+
+		require.NoError(t, results[0].err)
+		assert.Equal(t, http.StatusOK, results[0].status)
+
+		ok := 0
+		for _, r := range results[1:] {
+			require.NoError(t, r.err)
+			switch r.status {
+			case http.StatusOK:
+				ok++
+			case http.StatusConflict:
+				assert.Contains(t, []string{"NOT_ASSIGNED", "PR_MERGED"}, r.code)
+			default:
+				t.Errorf("unexpected status %d (%s)", r.status, r.code)
+			}
+		}
+		assert.LessOrEqual(t, ok, 1)
+
+		var status string
+		err := testDB.QueryRow(context.Background(), "SELECT status FROM pull_requests WHERE pull_request_id = 'pr-1'").Scan(&status)
+		require.NoError(t, err)
+		assert.Equal(t, "MERGED", status)
+
+		reviewers := prReviewers(t, "pr-1")
+		assertReviewerInvariants(t, reviewers, "u1", 2)
+		assert.Equal(t, ok, historyCount(t, "pr-1"))
+	})
+
+	t.Run("ParallelCreate_SamePR", func(t *testing.T) {
+		cleanupTestData(t)
+		createTeamWithCandidates(t)
+
+		results := runParallel(workers, func(int) parallelResult {
+			return postJSON("/pullRequest/create", map[string]interface{}{"pull_request_id": "pr-1", "pull_request_name": "Add feature", "author_id": "u1"})
+		})
+
+		created := 0
+		for _, r := range results {
+			require.NoError(t, r.err)
+			switch r.status {
+			case http.StatusCreated:
+				created++
+			case http.StatusConflict:
+				assert.Equal(t, "PR_EXISTS", r.code)
+			default:
+				t.Errorf("unexpected status %d (%s)", r.status, r.code)
+			}
+		}
+		assert.Equal(t, 1, created, "exactly one create must succeed")
+
+		assertReviewerInvariants(t, prReviewers(t, "pr-1"), "u1", 2)
+	})
+}
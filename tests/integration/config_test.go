@@ -0,0 +1,144 @@
+package integration
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"avito-test-quest/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unsetEnv снимает переменную на время теста и восстанавливает её значение после
+func unsetEnv(t *testing.T, name string) {
+	t.Setenv(name, "")
+	require.NoError(t, os.Unsetenv(name))
+}
+
+func TestConfigLoading(t *testing.T) {
+	t.Run("Config_EnvOverridesFile", func(t *testing.T) {
+		t.Setenv("POSTGRES_HOST", "db.internal")
+		t.Setenv("PR_PORT", "9090")
+		t.Setenv("AUTH_ADMIN_TOKENS", "t1,t2")
+
+		cfg, err := config.New("")
+		require.NoError(t, err)
+
+		assert.Equal(t, "db.internal", cfg.Postgres.Host)
+		assert.Equal(t, "9090", cfg.PR.Port)
+		assert.Equal(t, []string{"t1", "t2"}, cfg.Auth.AdminTokens)
+	})
+
+	t.Run("Config_SecretFromFile", func(t *testing.T) {
+		secret := filepath.Join(t.TempDir(), "admin-tokens")
+		require.NoError(t, os.WriteFile(secret, []byte("from-file\n"), 0o600))
+		unsetEnv(t, "AUTH_ADMIN_TOKENS")
+		t.Setenv("AUTH_ADMIN_TOKENS_FILE", secret)
+
+		cfg, err := config.New("")
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"from-file"}, cfg.Auth.AdminTokens)
+	})
+
+	t.Run("Config_AdminTokensOnlyFromEnv", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`
+storage:
+  driver: memory
+auth:
+  admin_tokens:
+    - from-yaml
+`), 0o600))
+		unsetEnv(t, "AUTH_ADMIN_TOKENS")
+
+		cfg, err := config.New(path)
+		require.NoError(t, err)
+		assert.Empty(t, cfg.Auth.AdminTokens)
+	})
+
+	t.Run("Config_RejectsPlaceholderAdminToken", func(t *testing.T) {
+		t.Setenv("AUTH_ADMIN_TOKENS", "admin-secret-token")
+
+		_, err := config.New("")
+		assert.ErrorContains(t, err, "AUTH_ADMIN_TOKENS")
+	})
+
+	t.Run("Config_PathFromEnv", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`
+postgres:
+  host: custom-host
+  port: 6543
+  username: u
+  password: p
+  database: d
+`), 0o600))
+		t.Setenv(config.ConfigPathEnv, path)
+
+		cfg, err := config.New("")
+		require.NoError(t, err)
+
+		assert.Equal(t, "custom-host", cfg.Postgres.Host)
+		assert.Equal(t, "6543", cfg.Postgres.Port)
+		// незаданные в файле поля получают значения по умолчанию
+		assert.Equal(t, "8080", cfg.PR.Port)
+	})
+
+	t.Run("Config_MissingExplicitPath", func(t *testing.T) {
+		_, err := config.New(filepath.Join(t.TempDir(), "missing.yaml"))
+		assert.Error(t, err)
+	})
+
+	t.Run("Config_ValidationReportsAllProblems", func(t *testing.T) {
+		t.Setenv("POSTGRES_PASSWORD", "")
+		t.Setenv("PR_PORT", "99999")
+		t.Setenv("MIN_CONNS", "50")
+		t.Setenv("TRACING_EXPORTER", "zipkin")
+		t.Setenv("LOG_LEVEL", "fatal")
+
+		_, err := config.New("")
+		require.Error(t, err)
+
+		var verr *config.ValidationError
+		require.ErrorAs(t, err, &verr)
+		assert.Len(t, verr.Problems, 5)
+		assert.Contains(t, err.Error(), "POSTGRES_PASSWORD")
+		assert.Contains(t, err.Error(), "PR_PORT")
+		assert.Contains(t, err.Error(), "MIN_CONNS")
+		assert.Contains(t, err.Error(), "TRACING_EXPORTER")
+		assert.Contains(t, err.Error(), "LOG_LEVEL")
+	})
+
+	t.Run("Config_MemoryStorageSkipsPostgres", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`
+storage:
+  driver: memory
+`), 0o600))
+		unsetEnv(t, "POSTGRES_HOST")
+		unsetEnv(t, "POSTGRES_PASSWORD")
+
+		cfg, err := config.New(path)
+		require.NoError(t, err)
+		assert.Equal(t, config.StorageDriverMemory, cfg.Storage.Driver)
+
+		t.Setenv("STORAGE_DRIVER", "sqlite3")
+		_, err = config.New(path)
+		assert.ErrorContains(t, err, "STORAGE_DRIVER")
+	})
+
+	t.Run("Config_SQLiteStorage", func(t *testing.T) {
+		unsetEnv(t, "POSTGRES_HOST")
+		unsetEnv(t, "POSTGRES_PASSWORD")
+		unsetEnv(t, "SQLITE_PATH")
+		t.Setenv("STORAGE_DRIVER", "sqlite")
+
+		cfg, err := config.New("")
+		require.NoError(t, err)
+		assert.Equal(t, config.StorageDriverSQLite, cfg.Storage.Driver)
+		assert.Equal(t, "data/pr-reviewer.db", cfg.SQLite.Path)
+	})
+}
@@ -0,0 +1,273 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"avito-test-quest/internal/auth"
+	"avito-test-quest/internal/models"
+	"avito-test-quest/internal/repository"
+	"avito-test-quest/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// deactivateResult ответ POST /team/deactivateMembers
+type deactivateResult struct {
+	TeamName    string   `json:"team_name"`
+	Deactivated []string `json:"deactivated"`
+	Reassigned  []struct {
+		PullRequestID string `json:"pull_request_id"`
+		OldUserID     string `json:"old_user_id"`
+		NewUserID     string `json:"new_user_id"`
+	} `json:"reassigned"`
+	NoCandidate []struct {
+		PullRequestID string `json:"pull_request_id"`
+		UserID        string `json:"user_id"`
+	} `json:"no_candidate"`
+}
+
+func TestDeactivateTeamMembers(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	deactivate := func(t *testing.T, payload map[string]interface{}) deactivateResult {
+		resp := makeRequest(t, "POST", "/team/deactivateMembers", payload, nil)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result deactivateResult
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		return result
+	}
+
+	t.Run("Deactivate_ReassignsOpenReviews", func(t *testing.T) {
+		cleanupTestData(t)
+
+		createTestTeam(t, "backend", []map[string]interface{}{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+			{"user_id": "u2", "username": "Bob", "is_active": true},
+			{"user_id": "u3", "username": "Charlie", "is_active": true},
+			{"user_id": "u4", "username": "David", "is_active": true},
+			{"user_id": "u5", "username": "Eve", "is_active": true},
+		})
+		createTestPR(t, "pr-1", "Open PR", "u1")
+		assignReviewer(t, "pr-1", "u2")
+		assignReviewer(t, "pr-1", "u3")
+		createTestPR(t, "pr-2", "Merged PR", "u1")
+		assignReviewer(t, "pr-2", "u2")
+		_, err := testDB.Exec(context.Background(), "UPDATE pull_requests SET status = 'MERGED' WHERE pull_request_id = 'pr-2'")
+		require.NoError(t, err)
+
+		result := deactivate(t, map[string]interface{}{
+			"team_name": "backend",
+			"user_ids":  []string{"u2", "u3"},
+			"reason":    "re-org",
+		})
+
+		assert.Equal(t, "backend", result.TeamName)
+		assert.ElementsMatch(t, []string{"u2", "u3"}, result.Deactivated)
+		require.Len(t, result.Reassigned, 2)
+		assert.Empty(t, result.NoCandidate)
+
+		// оба ревью OPEN PR переданы разным оставшимся активным участникам
+		reviewers := prReviewers(t, "pr-1")
+		assertReviewerInvariants(t, reviewers, "u1", 2)
+		for _, r := range reviewers {
+			assert.Contains(t, []string{"u4", "u5"}, r)
+		}
+		// замерженный PR не трогаем
+		assert.Equal(t, []string{"u2"}, prReviewers(t, "pr-2"))
+		assert.Equal(t, 2, historyCount(t, "pr-1"))
+
+		var active int
+		err = testDB.QueryRow(context.Background(), "SELECT count(1) FROM users WHERE user_id IN ('u2', 'u3') AND is_active").Scan(&active)
+		require.NoError(t, err)
+		assert.Equal(t, 0, active)
+	})
+
+	t.Run("Deactivate_AllExcept", func(t *testing.T) {
+		cleanupTestData(t)
+
+		createTestTeam(t, "backend", []map[string]interface{}{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+			{"user_id": "u2", "username": "Bob", "is_active": true},
+			{"user_id": "u3", "username": "Charlie", "is_active": true},
+			{"user_id": "u4", "username": "David", "is_active": true},
+		})
+		createTestPR(t, "pr-1", "Open PR", "u1")
+		assignReviewer(t, "pr-1", "u2")
+
+		result := deactivate(t, map[string]interface{}{
+			"team_name":  "backend",
+			"user_ids":   []string{"u1", "u4"},
+			"all_except": true,
+		})
+
+		assert.ElementsMatch(t, []string{"u2", "u3"}, result.Deactivated)
+		require.Len(t, result.Reassigned, 1)
+		assert.Equal(t, "u4", result.Reassigned[0].NewUserID)
+		assert.Equal(t, []string{"u4"}, prReviewers(t, "pr-1"))
+	})
+
+	t.Run("Deactivate_NoCandidateReported", func(t *testing.T) {
+		cleanupTestData(t)
+
+		createTestTeam(t, "backend", []map[string]interface{}{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+			{"user_id": "u2", "username": "Bob", "is_active": true},
+			{"user_id": "u3", "username": "Charlie", "is_active": true},
+		})
+		createTestPR(t, "pr-1", "Open PR", "u1")
+		assignReviewer(t, "pr-1", "u2")
+		assignReviewer(t, "pr-1", "u3")
+
+		// замены нет: единственный активный участник — автор
+		result := deactivate(t, map[string]interface{}{
+			"team_name": "backend",
+			"user_ids":  []string{"u2", "u3"},
+		})
+
+		assert.Empty(t, result.Reassigned)
+		require.Len(t, result.NoCandidate, 2)
+		assert.ElementsMatch(t, []string{"u2", "u3"}, []string{result.NoCandidate[0].UserID, result.NoCandidate[1].UserID})
+		// ревьюверы без замены остаются назначенными
+		assert.ElementsMatch(t, []string{"u2", "u3"}, prReviewers(t, "pr-1"))
+	})
+
+	t.Run("Deactivate_FallbackTeam", func(t *testing.T) {
+		cleanupTestData(t)
+
+		createTestTeam(t, "backend", []map[string]interface{}{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+			{"user_id": "u2", "username": "Bob", "is_active": true},
+		})
+		createTestTeam(t, "platform", []map[string]interface{}{
+			{"user_id": "p1", "username": "Paul", "is_active": true},
+		})
+		resp := makeRequest(t, "PUT", "/team/settings", map[string]interface{}{
+			"team_name":      "backend",
+			"fallback_teams": []string{"platform"},
+		}, nil)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		createTestPR(t, "pr-1", "Open PR", "u1")
+		assignReviewer(t, "pr-1", "u2")
+
+		result := deactivate(t, map[string]interface{}{
+			"team_name": "backend",
+			"user_ids":  []string{"u2"},
+		})
+
+		require.Len(t, result.Reassigned, 1)
+		assert.Equal(t, "p1", result.Reassigned[0].NewUserID)
+
+		var isFallback bool
+		err := testDB.QueryRow(context.Background(),
+			"SELECT is_fallback FROM pr_reviewers WHERE pull_request_id = 'pr-1' AND reviewer_user_id = 'p1'").Scan(&isFallback)
+		require.NoError(t, err)
+		assert.True(t, isFallback)
+	})
+
+	t.Run("Deactivate_UserNotInTeam", func(t *testing.T) {
+		cleanupTestData(t)
+
+		createTestTeam(t, "backend", []map[string]interface{}{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+		})
+		createTestTeam(t, "platform", []map[string]interface{}{
+			{"user_id": "p1", "username": "Paul", "is_active": true},
+		})
+
+		resp := makeRequest(t, "POST", "/team/deactivateMembers", map[string]interface{}{
+			"team_name": "backend",
+			"user_ids":  []string{"u1", "p1"},
+		}, nil)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		// транзакция не применилась
+		var active bool
+		err := testDB.QueryRow(context.Background(), "SELECT is_active FROM users WHERE user_id = 'u1'").Scan(&active)
+		require.NoError(t, err)
+		assert.True(t, active)
+	})
+
+	t.Run("Deactivate_TeamNotFound", func(t *testing.T) {
+		cleanupTestData(t)
+
+		resp := makeRequest(t, "POST", "/team/deactivateMembers", map[string]interface{}{
+			"team_name": "nonexistent",
+			"user_ids":  []string{"u1"},
+		}, nil)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		var result map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&result)
+
+		errObj := result["error"].(map[string]interface{})
+		assert.Equal(t, "NOT_FOUND", errObj["code"])
+	})
+
+	t.Run("Deactivate_200Users", func(t *testing.T) {
+		cleanupTestData(t)
+
+		// команда из 400 участников, половину деактивируем; у каждого PR два ревьювера из деактивируемых
+		const members = 400
+		ctx := context.Background()
+		var teamID int64
+		err := testDB.QueryRow(ctx, "INSERT INTO teams (team_name) VALUES ('big') RETURNING id").Scan(&teamID)
+		require.NoError(t, err)
+		_, err = testDB.Exec(ctx, `INSERT INTO users (user_id, username, team_id, is_active)
+			SELECT 'u' || g, 'User' || g, $1, true FROM generate_series(1, $2::int) g`, teamID, members)
+		require.NoError(t, err)
+		_, err = testDB.Exec(ctx, `INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id)
+			SELECT 'pr-' || g, 'PR ' || g, 'u' || (200 + g) FROM generate_series(1, 200) g`)
+		require.NoError(t, err)
+		_, err = testDB.Exec(ctx, `INSERT INTO pr_reviewers (pull_request_id, reviewer_user_id)
+			SELECT 'pr-' || g, 'u' || g FROM generate_series(1, 200) g
+			UNION ALL
+			SELECT 'pr-' || g, 'u' || (g % 200 + 1) FROM generate_series(1, 200) g`)
+		require.NoError(t, err)
+
+		var userIDs []string
+		for i := 1; i <= 200; i++ {
+			userIDs = append(userIDs, fmt.Sprintf("u%d", i))
+		}
+
+		// время меряется на вызове сервиса поверх Postgres, без HTTP
+		svc := service.NewPrService(repository.NewPrRepository(testDB), service.NewRandomSelector(), nil)
+		adminCtx := auth.WithPrincipal(ctx, &auth.Principal{Role: auth.RoleAdmin})
+		started := time.Now()
+		result, err := svc.DeactivateTeamMembers(adminCtx, models.DeactivateTeamMembersInput{TeamName: "big", UserIDs: userIDs})
+		elapsed := time.Since(started)
+		require.NoError(t, err)
+		t.Logf("deactivated %d users, reassigned %d reviews in %s", len(result.Deactivated), len(result.Reassigned), elapsed)
+
+		assert.Len(t, result.Deactivated, 200)
+		assert.Len(t, result.Reassigned, 400)
+		assert.Empty(t, result.NoCandidate)
+		assert.Less(t, elapsed, 100*time.Millisecond)
+
+		// ни одного ревью у неактивных, у каждого PR по два разных ревьювера, не автор
+		var inactiveReviews, brokenPRs int
+		err = testDB.QueryRow(ctx, `SELECT count(1) FROM pr_reviewers r JOIN users u ON u.user_id = r.reviewer_user_id WHERE NOT u.is_active`).Scan(&inactiveReviews)
+		require.NoError(t, err)
+		assert.Equal(t, 0, inactiveReviews)
+		err = testDB.QueryRow(ctx, `SELECT count(1) FROM (
+			SELECT p.pull_request_id FROM pull_requests p JOIN pr_reviewers r ON r.pull_request_id = p.pull_request_id
+			GROUP BY p.pull_request_id, p.author_id
+			HAVING count(DISTINCT r.reviewer_user_id) <> 2 OR bool_or(r.reviewer_user_id = p.author_id)) bad`).Scan(&brokenPRs)
+		require.NoError(t, err)
+		assert.Equal(t, 0, brokenPRs)
+	})
+}
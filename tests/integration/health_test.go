@@ -1,10 +1,13 @@
 package integration
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"testing"
 
+	"avito-test-quest/internal/postgres"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -35,4 +38,55 @@ func TestHealthEndpoint(t *testing.T) {
 			resp.Body.Close()
 		}
 	})
+
+	t.Run("Liveness_Success", func(t *testing.T) {
+		resp := makeRequest(t, "GET", "/health/live", nil, nil)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("Readiness_Success", func(t *testing.T) {
+		resp := makeRequest(t, "GET", "/health/ready", nil, nil)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Equal(t, "ok", result["status"])
+
+		expected, err := postgres.LatestMigrationVersion()
+		require.NoError(t, err)
+		migrations := result["migrations"].(map[string]interface{})
+		assert.Equal(t, "ok", migrations["status"])
+		assert.Equal(t, float64(expected), migrations["version"])
+		assert.Equal(t, float64(expected), migrations["expected"])
+
+		pool := result["pool"].(map[string]interface{})
+		assert.Greater(t, pool["max"], float64(0))
+	})
+
+	t.Run("Readiness_SchemaBehind", func(t *testing.T) {
+		ctx := context.Background()
+		// откатываем номер версии, не трогая схему, и возвращаем его после проверки
+		_, err := testDB.Exec(ctx, "UPDATE schema_migrations SET version = version - 1")
+		require.NoError(t, err)
+		defer func() {
+			_, err := testDB.Exec(ctx, "UPDATE schema_migrations SET version = version + 1")
+			require.NoError(t, err)
+		}()
+
+		resp := makeRequest(t, "GET", "/health/ready", nil, nil)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+		var result map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Equal(t, "unavailable", result["status"])
+		migrations := result["migrations"].(map[string]interface{})
+		assert.Equal(t, "unavailable", migrations["status"])
+		assert.NotEmpty(t, migrations["error"])
+	})
 }
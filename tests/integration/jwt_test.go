@@ -0,0 +1,203 @@
+package integration
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"avito-test-quest/internal/auth"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testJWTIssuer   = "https://sso.test"
+	testJWTAudience = "pr-reviewer"
+)
+
+// testJWTKeys ключи, которыми тесты подписывают JWT; публичные части пишутся в JWKS файл
+var testJWTKeys struct {
+	once     sync.Once
+	rsa      *rsa.PrivateKey
+	ec       *ecdsa.PrivateKey
+	jwksFile string
+	err      error
+}
+
+// testJWTConfig генерирует пару ключей RSA и EC P-256 и возвращает настройки JWT с JWKS из временного файла
+func testJWTConfig(t *testing.T) auth.JWTConfig {
+	k := &testJWTKeys
+	k.once.Do(func() {
+		if k.rsa, k.err = rsa.GenerateKey(rand.Reader, 2048); k.err != nil {
+			return
+		}
+		if k.ec, k.err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader); k.err != nil {
+			return
+		}
+		b64 := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+		jwks := map[string]interface{}{"keys": []map[string]string{
+			{"kty": "RSA", "kid": "rsa-1", "use": "sig", "alg": "RS256",
+				"n": b64(k.rsa.N.Bytes()), "e": b64(big.NewInt(int64(k.rsa.E)).Bytes())},
+			{"kty": "EC", "kid": "ec-1", "use": "sig", "alg": "ES256", "crv": "P-256",
+				"x": b64(k.ec.X.FillBytes(make([]byte, 32))), "y": b64(k.ec.Y.FillBytes(make([]byte, 32)))},
+		}}
+		data, err := json.Marshal(jwks)
+		if err != nil {
+			k.err = err
+			return
+		}
+		dir, err := os.MkdirTemp("", "jwks")
+		if err != nil {
+			k.err = err
+			return
+		}
+		k.jwksFile = filepath.Join(dir, "jwks.json")
+		k.err = os.WriteFile(k.jwksFile, data, 0o600)
+	})
+	require.NoError(t, k.err, "failed to generate test jwks")
+
+	return auth.JWTConfig{
+		Issuer:   testJWTIssuer,
+		Audience: testJWTAudience,
+		JWKSFile: k.jwksFile,
+	}
+}
+
+// signJWT подписывает токен методом method ключом key с заголовком kid
+func signJWT(t *testing.T, method jwt.SigningMethod, key crypto.Signer, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+// validClaims claims, проходящие все проверки
+func validClaims(sub, role string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":  testJWTIssuer,
+		"aud":  testJWTAudience,
+		"sub":  sub,
+		"role": role,
+		"iat":  time.Now().Unix(),
+		"exp":  time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestJWTAuthentication(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	keys := &testJWTKeys
+
+	// setIsActive требует роль admin
+	setIsActive := func(t *testing.T, token string) *http.Response {
+		return makeRequest(t, "POST", "/users/setIsActive", map[string]interface{}{
+			"user_id":   "u1",
+			"is_active": false,
+		}, map[string]string{"Authorization": "Bearer " + token})
+	}
+
+	createTeam := func(t *testing.T) {
+		createTestTeam(t, "backend", []map[string]interface{}{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+		})
+	}
+
+	t.Run("RS256_Admin", func(t *testing.T) {
+		cleanupTestData(t)
+		createTeam(t)
+
+		token := signJWT(t, jwt.SigningMethodRS256, keys.rsa, "rsa-1", validClaims("admin-1", "admin"))
+		resp := setIsActive(t, token)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("ES256_AdminInRolesArray", func(t *testing.T) {
+		cleanupTestData(t)
+		createTeam(t)
+
+		claims := validClaims("admin-1", "")
+		claims["role"] = []string{"developer", "admin"}
+		token := signJWT(t, jwt.SigningMethodES256, keys.ec, "ec-1", claims)
+		resp := setIsActive(t, token)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("ES256_User", func(t *testing.T) {
+		cleanupTestData(t)
+		createTeam(t)
+
+		token := signJWT(t, jwt.SigningMethodES256, keys.ec, "ec-1", validClaims("u1", "developer"))
+
+		resp := makeRequest(t, "GET", "/users/getReview?user_id=u1", nil, map[string]string{"Authorization": "Bearer " + token})
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = setIsActive(t, token)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		cleanupTestData(t)
+		createTeam(t)
+
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		expired := validClaims("admin-1", "admin")
+		expired["exp"] = time.Now().Add(-time.Hour).Unix()
+		wrongAudience := validClaims("admin-1", "admin")
+		wrongAudience["aud"] = "other-service"
+		wrongIssuer := validClaims("admin-1", "admin")
+		wrongIssuer["iss"] = "https://evil.test"
+		noExpiry := validClaims("admin-1", "admin")
+		delete(noExpiry, "exp")
+		noSubject := validClaims("", "admin")
+		delete(noSubject, "sub")
+
+		cases := map[string]string{
+			"Expired":       signJWT(t, jwt.SigningMethodRS256, keys.rsa, "rsa-1", expired),
+			"WrongAudience": signJWT(t, jwt.SigningMethodRS256, keys.rsa, "rsa-1", wrongAudience),
+			"WrongIssuer":   signJWT(t, jwt.SigningMethodRS256, keys.rsa, "rsa-1", wrongIssuer),
+			"NoExpiry":      signJWT(t, jwt.SigningMethodRS256, keys.rsa, "rsa-1", noExpiry),
+			"NoSubject":     signJWT(t, jwt.SigningMethodRS256, keys.rsa, "rsa-1", noSubject),
+			"UnknownKey":    signJWT(t, jwt.SigningMethodRS256, otherKey, "rsa-1", validClaims("admin-1", "admin")),
+			"UnknownKid":    signJWT(t, jwt.SigningMethodRS256, keys.rsa, "rsa-2", validClaims("admin-1", "admin")),
+			"KeyTypeMix":    signJWT(t, jwt.SigningMethodES256, keys.ec, "rsa-1", validClaims("admin-1", "admin")),
+		}
+		for name, token := range cases {
+			t.Run(name, func(t *testing.T) {
+				resp := setIsActive(t, token)
+				defer resp.Body.Close()
+
+				assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+			})
+		}
+
+		// пользователь остался активным: ни один запрос не прошел
+		var active bool
+		err = testDB.QueryRow(context.Background(), "SELECT is_active FROM users WHERE user_id = 'u1'").Scan(&active)
+		require.NoError(t, err)
+		assert.True(t, active)
+	})
+}
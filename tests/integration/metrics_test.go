@@ -0,0 +1,70 @@
+package integration
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scrapeMetrics возвращает текущие метрики сервиса в текстовом формате Prometheus
+func scrapeMetrics(t *testing.T) string {
+	resp := makeRequest(t, "GET", "/metrics", nil, nil)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	t.Run("Metrics_DomainCounters", func(t *testing.T) {
+		cleanupTestData(t)
+
+		createTestTeam(t, "backend", []map[string]interface{}{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+			{"user_id": "u2", "username": "Bob", "is_active": true},
+			{"user_id": "u3", "username": "Charlie", "is_active": true},
+		})
+
+		// PR получает двух ревьюверов, замены для них нет, затем PR мержится дважды
+		resp := makeRequest(t, "POST", "/pullRequest/create", map[string]interface{}{
+			"pull_request_id": "pr-1", "pull_request_name": "Add metrics", "author_id": "u1",
+		}, nil)
+		resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		resp = makeRequest(t, "POST", "/pullRequest/reassign", map[string]interface{}{
+			"pull_request_id": "pr-1", "old_user_id": "u2",
+		}, nil)
+		resp.Body.Close()
+		require.Equal(t, http.StatusConflict, resp.StatusCode)
+
+		for i := 0; i < 2; i++ {
+			resp = makeRequest(t, "POST", "/pullRequest/merge", map[string]interface{}{"pull_request_id": "pr-1"}, nil)
+			resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+		}
+
+		body := scrapeMetrics(t)
+		assert.Contains(t, body, `pr_reviewer_reviewer_assignments_total{fallback="false",operation="create"} 2`)
+		assert.Contains(t, body, `pr_reviewer_no_candidate_total{operation="reassign"} 1`)
+		assert.Contains(t, body, `pr_reviewer_pull_request_merges_total 1`)
+	})
+
+	t.Run("Metrics_HTTPAndDB", func(t *testing.T) {
+		resp := makeRequest(t, "GET", "/team/get?team_name=nonexistent", nil, nil)
+		resp.Body.Close()
+
+		body := scrapeMetrics(t)
+		assert.Contains(t, body, `pr_reviewer_http_request_duration_seconds_count{method="GET",route="/team/get",status="404"}`)
+		assert.Contains(t, body, `pr_reviewer_db_query_duration_seconds_count{query="select teams",success="true"}`)
+		assert.Contains(t, body, `pr_reviewer_db_pool_max_connections`)
+	})
+}
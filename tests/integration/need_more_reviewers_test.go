@@ -0,0 +1,153 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNeedMoreReviewers(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	// createPR создает PR через API и возвращает его из ответа
+	createPR := func(t *testing.T, prID, authorID string) map[string]interface{} {
+		payload := map[string]interface{}{
+			"pull_request_id":   prID,
+			"pull_request_name": "Add feature",
+			"author_id":         authorID,
+		}
+		resp := makeRequest(t, "POST", "/pullRequest/create", payload, nil)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var result map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		return result["pr"].(map[string]interface{})
+	}
+
+	// userReviews возвращает PR, где пользователь назначен ревьювером
+	userReviews := func(t *testing.T, userID string) []interface{} {
+		resp := makeRequest(t, "GET", "/users/getReview?user_id="+userID, nil, nil)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		prs, _ := result["pull_requests"].([]interface{})
+		return prs
+	}
+
+	t.Run("CreatePR_EnoughReviewers_NotFlagged", func(t *testing.T) {
+		cleanupTestData(t)
+
+		createTestTeam(t, "backend", []map[string]interface{}{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+			{"user_id": "u2", "username": "Bob", "is_active": true},
+			{"user_id": "u3", "username": "Charlie", "is_active": true},
+		})
+
+		pr := createPR(t, "pr-1", "u1")
+		assert.Equal(t, false, pr["needMoreReviewers"])
+		assert.Len(t, pr["assigned_reviewers"], 2)
+	})
+
+	t.Run("CreatePR_NotEnoughReviewers_Flagged", func(t *testing.T) {
+		cleanupTestData(t)
+
+		createTestTeam(t, "backend", []map[string]interface{}{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+			{"user_id": "u2", "username": "Bob", "is_active": true},
+		})
+
+		pr := createPR(t, "pr-1", "u1")
+		assert.Equal(t, true, pr["needMoreReviewers"])
+		assert.Len(t, pr["assigned_reviewers"], 1)
+
+		// флаг виден и в списке ревью пользователя
+		prs := userReviews(t, "u2")
+		require.Len(t, prs, 1)
+		assert.Equal(t, true, prs[0].(map[string]interface{})["needMoreReviewers"])
+	})
+
+	t.Run("SetIsActive_TopsUpFlaggedPRs", func(t *testing.T) {
+		cleanupTestData(t)
+
+		createTestTeam(t, "backend", []map[string]interface{}{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+			{"user_id": "u2", "username": "Bob", "is_active": true},
+			{"user_id": "u3", "username": "Charlie", "is_active": false},
+		})
+
+		pr := createPR(t, "pr-1", "u1")
+		require.Equal(t, true, pr["needMoreReviewers"])
+
+		// активируем u3 — он должен быть назначен на PR, которому не хватало ревьюверов
+		resp := makeRequest(t, "POST", "/users/setIsActive", map[string]interface{}{
+			"user_id":   "u3",
+			"is_active": true,
+		}, nil)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		prs := userReviews(t, "u3")
+		require.Len(t, prs, 1)
+		prObj := prs[0].(map[string]interface{})
+		assert.Equal(t, "pr-1", prObj["pull_request_id"])
+		assert.Equal(t, false, prObj["needMoreReviewers"])
+	})
+
+	t.Run("SetIsActive_SkipsMergedPRs", func(t *testing.T) {
+		cleanupTestData(t)
+
+		createTestTeam(t, "backend", []map[string]interface{}{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+			{"user_id": "u2", "username": "Bob", "is_active": false},
+		})
+
+		pr := createPR(t, "pr-1", "u1")
+		require.Equal(t, true, pr["needMoreReviewers"])
+
+		resp := makeRequest(t, "POST", "/pullRequest/merge", map[string]interface{}{"pull_request_id": "pr-1"}, nil)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = makeRequest(t, "POST", "/users/setIsActive", map[string]interface{}{
+			"user_id":   "u2",
+			"is_active": true,
+		}, nil)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		// замерженный PR не добирает ревьюверов
+		assert.Empty(t, userReviews(t, "u2"))
+	})
+
+	t.Run("CreateTeam_TopsUpFlaggedPRs", func(t *testing.T) {
+		cleanupTestData(t)
+
+		createTestTeam(t, "backend", []map[string]interface{}{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+		})
+
+		pr := createPR(t, "pr-1", "u1")
+		require.Equal(t, true, pr["needMoreReviewers"])
+		require.Empty(t, pr["assigned_reviewers"])
+
+		// автор переходит в новую команду вместе с двумя активными участниками
+		createTestTeam(t, "platform", []map[string]interface{}{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+			{"user_id": "u5", "username": "Eve", "is_active": true},
+			{"user_id": "u6", "username": "Frank", "is_active": true},
+		})
+
+		for _, uid := range []string{"u5", "u6"} {
+			prs := userReviews(t, uid)
+			require.Len(t, prs, 1, "user %s should be assigned", uid)
+			assert.Equal(t, false, prs[0].(map[string]interface{})["needMoreReviewers"])
+		}
+	})
+}
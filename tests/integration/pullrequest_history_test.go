@@ -0,0 +1,142 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullRequestHistory(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	t.Run("History_CreateAndReassign", func(t *testing.T) {
+		cleanupTestData(t)
+
+		// команда: автор + 1 ревьювер при создании, затем активируем замену
+		createTestTeam(t, "backend", []map[string]interface{}{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+			{"user_id": "u2", "username": "Bob", "is_active": true},
+			{"user_id": "u3", "username": "Charlie", "is_active": false},
+		})
+
+		resp := makeRequest(t, "POST", "/pullRequest/create", map[string]interface{}{
+			"pull_request_id":   "pr-1",
+			"pull_request_name": "Add feature",
+			"author_id":         "u1",
+		}, nil)
+		resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		// деактивируем u2 и делаем u3 единственным кандидатом на замену
+		_, err := testDB.Exec(context.Background(), "UPDATE users SET is_active = (user_id = 'u3') WHERE user_id IN ('u2', 'u3')")
+		require.NoError(t, err)
+
+		resp = makeRequest(t, "POST", "/pullRequest/reassign", map[string]interface{}{
+			"pull_request_id": "pr-1",
+			"old_user_id":     "u2",
+			"reason":          "on vacation",
+		}, nil)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		// запрашиваем историю назначений
+		resp = makeRequest(t, "GET", "/pullRequest/history?pull_request_id=pr-1", nil, nil)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result map[string]interface{}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		require.NoError(t, err)
+
+		assert.Equal(t, "pr-1", result["pull_request_id"])
+		history := result["history"].([]interface{})
+		require.Len(t, history, 2)
+
+		// первичное назначение
+		first := history[0].(map[string]interface{})
+		assert.Nil(t, first["old_user_id"])
+		assert.Equal(t, "u2", first["new_user_id"])
+		assert.NotEmpty(t, first["assigned_at"])
+		assert.NotContains(t, first, "reason")
+
+		// переназначение с причиной
+		second := history[1].(map[string]interface{})
+		assert.Equal(t, "u2", second["old_user_id"])
+		assert.Equal(t, "u3", second["new_user_id"])
+		assert.Equal(t, "on vacation", second["reason"])
+	})
+
+	t.Run("History_ReassignWithoutReason", func(t *testing.T) {
+		cleanupTestData(t)
+
+		createTestTeam(t, "backend", []map[string]interface{}{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+			{"user_id": "u2", "username": "Bob", "is_active": true},
+			{"user_id": "u3", "username": "Charlie", "is_active": true},
+		})
+
+		createTestPR(t, "pr-1", "Test PR", "u1")
+		assignReviewer(t, "pr-1", "u2")
+
+		resp := makeRequest(t, "POST", "/pullRequest/reassign", map[string]interface{}{
+			"pull_request_id": "pr-1",
+			"old_user_id":     "u2",
+		}, nil)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp = makeRequest(t, "GET", "/pullRequest/history?pull_request_id=pr-1", nil, nil)
+		defer resp.Body.Close()
+
+		var result map[string]interface{}
+		err := json.NewDecoder(resp.Body).Decode(&result)
+		require.NoError(t, err)
+
+		history := result["history"].([]interface{})
+		require.Len(t, history, 1)
+		entry := history[0].(map[string]interface{})
+		assert.Equal(t, "u2", entry["old_user_id"])
+		assert.Equal(t, "u3", entry["new_user_id"])
+		assert.NotContains(t, entry, "reason")
+	})
+
+	t.Run("History_Empty", func(t *testing.T) {
+		cleanupTestData(t)
+
+		createTestTeam(t, "backend", []map[string]interface{}{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+		})
+		createTestPR(t, "pr-1", "Test PR", "u1")
+
+		resp := makeRequest(t, "GET", "/pullRequest/history?pull_request_id=pr-1", nil, nil)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result map[string]interface{}
+		err := json.NewDecoder(resp.Body).Decode(&result)
+		require.NoError(t, err)
+		assert.Empty(t, result["history"])
+	})
+
+	t.Run("History_NotFound", func(t *testing.T) {
+		cleanupTestData(t)
+
+		resp := makeRequest(t, "GET", "/pullRequest/history?pull_request_id=nonexistent", nil, nil)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		var result map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&result)
+
+		errObj := result["error"].(map[string]interface{})
+		assert.Equal(t, "NOT_FOUND", errObj["code"])
+	})
+}
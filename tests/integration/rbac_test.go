@@ -0,0 +1,239 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// asUser заголовки запроса от имени пользователя userID
+func asUser(userID string) map[string]string {
+	return map[string]string{"Authorization": "Bearer " + testUserToken(userID)}
+}
+
+// setTestRole назначает роль пользователю напрямую в БД
+func setTestRole(t *testing.T, userID, role string) {
+	_, err := testDB.Exec(context.Background(), "UPDATE users SET role = $1 WHERE user_id = $2", role, userID)
+	require.NoError(t, err, "failed to set test role")
+}
+
+func TestRoleBasedAccess(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	// две команды: в backend тимлид u1, в frontend тимлид u5
+	setupTeams := func(t *testing.T) {
+		createTestTeam(t, "backend", []map[string]interface{}{
+			{"user_id": "u1", "username": "Lead", "is_active": true},
+			{"user_id": "u2", "username": "Bob", "is_active": true},
+			{"user_id": "u3", "username": "Charlie", "is_active": true},
+			{"user_id": "u4", "username": "David", "is_active": true},
+		})
+		createTestTeam(t, "frontend", []map[string]interface{}{
+			{"user_id": "u5", "username": "Other Lead", "is_active": true},
+			{"user_id": "u6", "username": "Frank", "is_active": true},
+			{"user_id": "u7", "username": "Grace", "is_active": true},
+		})
+		setTestRole(t, "u1", "team_lead")
+		setTestRole(t, "u5", "team_lead")
+	}
+
+	setIsActive := func(t *testing.T, caller, userID string) int {
+		resp := makeRequest(t, "POST", "/users/setIsActive", map[string]interface{}{
+			"user_id":   userID,
+			"is_active": false,
+		}, asUser(caller))
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	reassign := func(t *testing.T, caller, prID, oldUserID string) int {
+		resp := makeRequest(t, "POST", "/pullRequest/reassign", map[string]interface{}{
+			"pull_request_id": prID,
+			"old_user_id":     oldUserID,
+		}, asUser(caller))
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	t.Run("SetRole_AdminOnly", func(t *testing.T) {
+		cleanupTestData(t)
+		setupTeams(t)
+
+		resp := makeRequest(t, "POST", "/users/setRole", map[string]interface{}{"user_id": "u2", "role": "team_lead"}, nil)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		user := result["user"].(map[string]interface{})
+		assert.Equal(t, "team_lead", user["role"])
+
+		// тимлид не может раздавать роли
+		resp2 := makeRequest(t, "POST", "/users/setRole", map[string]interface{}{"user_id": "u3", "role": "admin"}, asUser("u1"))
+		defer resp2.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp2.StatusCode)
+
+		// неизвестная роль
+		resp3 := makeRequest(t, "POST", "/users/setRole", map[string]interface{}{"user_id": "u3", "role": "owner"}, nil)
+		defer resp3.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp3.StatusCode)
+	})
+
+	t.Run("SetIsActive_TeamLeadScopedToTeam", func(t *testing.T) {
+		cleanupTestData(t)
+		setupTeams(t)
+
+		assert.Equal(t, http.StatusOK, setIsActive(t, "u1", "u2"))
+		assert.Equal(t, http.StatusForbidden, setIsActive(t, "u1", "u6"))
+		// участник не может менять активность, даже свою
+		assert.Equal(t, http.StatusForbidden, setIsActive(t, "u3", "u3"))
+
+		var active bool
+		err := testDB.QueryRow(context.Background(), "SELECT is_active FROM users WHERE user_id = 'u6'").Scan(&active)
+		require.NoError(t, err)
+		assert.True(t, active)
+	})
+
+	t.Run("Reassign_MemberOnlySelf", func(t *testing.T) {
+		cleanupTestData(t)
+		setupTeams(t)
+		createTestPR(t, "pr-1", "Test PR", "u4")
+		assignReviewer(t, "pr-1", "u2")
+		assignReviewer(t, "pr-1", "u3")
+
+		assert.Equal(t, http.StatusForbidden, reassign(t, "u2", "pr-1", "u3"))
+		assert.Equal(t, http.StatusOK, reassign(t, "u2", "pr-1", "u2"))
+		assert.NotContains(t, prReviewers(t, "pr-1"), "u2")
+	})
+
+	t.Run("Reassign_TeamLeadScopedToTeam", func(t *testing.T) {
+		cleanupTestData(t)
+		setupTeams(t)
+		createTestPR(t, "pr-1", "Backend PR", "u4")
+		assignReviewer(t, "pr-1", "u2")
+		createTestPR(t, "pr-2", "Frontend PR", "u7")
+		assignReviewer(t, "pr-2", "u6")
+
+		assert.Equal(t, http.StatusOK, reassign(t, "u1", "pr-1", "u2"))
+		assert.Equal(t, http.StatusForbidden, reassign(t, "u1", "pr-2", "u6"))
+		assert.Equal(t, []string{"u6"}, prReviewers(t, "pr-2"))
+
+		// PR своей команды не дает права снимать ревьювера из другой команды
+		createTestPR(t, "pr-3", "Backend PR reviewed by frontend", "u4")
+		assignReviewer(t, "pr-3", "u6")
+		assert.Equal(t, http.StatusForbidden, reassign(t, "u1", "pr-3", "u6"))
+		assert.Equal(t, []string{"u6"}, prReviewers(t, "pr-3"))
+	})
+
+	t.Run("AdminOnlyOperations", func(t *testing.T) {
+		cleanupTestData(t)
+		setupTeams(t)
+
+		resp := makeRequest(t, "POST", "/pullRequest/create", map[string]interface{}{
+			"pull_request_id":   "pr-1",
+			"pull_request_name": "Add feature",
+			"author_id":         "u1",
+		}, asUser("u1"))
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	})
+
+	t.Run("DeactivateMembers_TeamLeadScopedToTeam", func(t *testing.T) {
+		cleanupTestData(t)
+		setupTeams(t)
+
+		deactivate := func(caller, teamName, userID string) int {
+			resp := makeRequest(t, "POST", "/team/deactivateMembers", map[string]interface{}{
+				"team_name": teamName,
+				"user_ids":  []string{userID},
+			}, asUser(caller))
+			defer resp.Body.Close()
+			return resp.StatusCode
+		}
+
+		// тимлид деактивирует участников только своей команды, участник — никого
+		assert.Equal(t, http.StatusForbidden, deactivate("u5", "backend", "u2"))
+		assert.Equal(t, http.StatusForbidden, deactivate("u3", "backend", "u2"))
+		assert.Equal(t, http.StatusOK, deactivate("u1", "backend", "u2"))
+
+		var active bool
+		err := testDB.QueryRow(context.Background(), "SELECT is_active FROM users WHERE user_id = 'u2'").Scan(&active)
+		require.NoError(t, err)
+		assert.False(t, active)
+	})
+
+	t.Run("TeamSettings_TeamLeadScopedToTeam", func(t *testing.T) {
+		cleanupTestData(t)
+		setupTeams(t)
+
+		updateSettings := func(caller string) int {
+			resp := makeRequest(t, "PUT", "/team/settings", map[string]interface{}{
+				"team_name":       "backend",
+				"reviewers_count": 1,
+			}, asUser(caller))
+			defer resp.Body.Close()
+			return resp.StatusCode
+		}
+
+		// участник и тимлид другой команды не меняют настройки backend
+		assert.Equal(t, http.StatusForbidden, updateSettings("u2"))
+		assert.Equal(t, http.StatusForbidden, updateSettings("u5"))
+		assert.Equal(t, http.StatusOK, updateSettings("u1"))
+
+		var count int
+		err := testDB.QueryRow(context.Background(),
+			"SELECT reviewers_count FROM team_settings s JOIN teams t ON t.id = s.team_id WHERE t.team_name = 'backend'").Scan(&count)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		// чтение настроек доступно участнику, но не без токена
+		resp := makeRequest(t, "GET", "/team/settings?team_name=backend", nil, asUser("u2"))
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		resp2 := makeRequest(t, "GET", "/team/settings?team_name=backend", nil, map[string]string{"Authorization": ""})
+		defer resp2.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp2.StatusCode)
+	})
+
+	t.Run("CreateTeam_MoveExistingUserAdminOnly", func(t *testing.T) {
+		cleanupTestData(t)
+		setupTeams(t)
+
+		createTeam := func(teamName, userID string) int {
+			resp := makeRequest(t, "POST", "/team/add", map[string]interface{}{
+				"team_name": teamName,
+				"members": []map[string]interface{}{
+					{"user_id": userID, "username": "Moved", "is_active": true},
+				},
+			}, asUser("u1"))
+			defer resp.Body.Close()
+			return resp.StatusCode
+		}
+
+		// тимлид не может перетащить чужого участника в новую команду
+		assert.Equal(t, http.StatusForbidden, createTeam("platform", "u6"))
+		var teamName string
+		err := testDB.QueryRow(context.Background(),
+			"SELECT t.team_name FROM users u JOIN teams t ON t.id = u.team_id WHERE u.user_id = 'u6'").Scan(&teamName)
+		require.NoError(t, err)
+		assert.Equal(t, "frontend", teamName)
+
+		// команда с новыми пользователями создается любым аутентифицированным пользователем
+		assert.Equal(t, http.StatusCreated, createTeam("platform", "u12"))
+	})
+
+	t.Run("StoredAdminRole", func(t *testing.T) {
+		cleanupTestData(t)
+		setupTeams(t)
+		setTestRole(t, "u3", "admin")
+
+		// роль admin в БД дает полный доступ и с пользовательским токеном
+		assert.Equal(t, http.StatusOK, setIsActive(t, "u3", "u6"))
+	})
+}
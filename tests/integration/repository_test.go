@@ -0,0 +1,92 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"avito-test-quest/internal/repository"
+	"avito-test-quest/internal/repository/repotest"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPostgresRepositoryConformance прогоняет общий набор тестов хранилища на Postgres:
+// тот же набор проходит хранилище в памяти, поэтому их поведение не расходится
+func TestPostgresRepositoryConformance(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	repotest.Run(t, func(t *testing.T) repository.Repository {
+		cleanupTestData(t)
+		return repository.NewPrRepository(testDB)
+	})
+}
+
+// countingDB считает запросы, отправленные репозиторием в БД
+type countingDB struct {
+	repository.DB
+	queries int
+}
+
+func (db *countingDB) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	db.queries++
+	return db.DB.Exec(ctx, sql, args...)
+}
+
+func (db *countingDB) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	db.queries++
+	return db.DB.Query(ctx, sql, args...)
+}
+
+func (db *countingDB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	db.queries++
+	return db.DB.QueryRow(ctx, sql, args...)
+}
+
+// TestActiveUsersWithLoadSingleQuery загрузка кандидатов считается одним запросом,
+// а не отдельным запросом на каждого участника команды
+func TestActiveUsersWithLoadSingleQuery(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	cleanupTestData(t)
+
+	ctx := context.Background()
+	repo := repository.NewPrRepository(testDB)
+	team, err := repo.CreateTeam(ctx, "backend")
+	require.NoError(t, err)
+	for _, id := range []string{"u1", "u2", "u3", "u4", "u5"} {
+		_, err := repo.CreateUser(ctx, id, "name-"+id, team.ID, id != "u5")
+		require.NoError(t, err)
+	}
+	// u2: два открытых ревью, u3: одно открытое и одно на замерженном PR
+	for _, pr := range []struct {
+		id        string
+		reviewers []string
+	}{
+		{"pr-1", []string{"u2", "u3"}},
+		{"pr-2", []string{"u2"}},
+		{"pr-3", []string{"u3", "u5"}},
+	} {
+		_, err := repo.CreatePullRequest(ctx, pr.id, "PR "+pr.id, "u1")
+		require.NoError(t, err)
+		for _, r := range pr.reviewers {
+			require.NoError(t, repo.AssignReviewer(ctx, pr.id, r, false))
+		}
+	}
+	_, err = repo.MergePullRequest(ctx, "pr-3")
+	require.NoError(t, err)
+
+	db := &countingDB{DB: testDB}
+	candidates, err := repository.NewPrRepository(db).GetActiveUsersWithLoad(ctx, team.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, db.queries)
+
+	load := map[string]int{}
+	for _, c := range candidates {
+		load[c.UserID] = c.OpenReviews
+	}
+	assert.Equal(t, map[string]int{"u1": 0, "u2": 2, "u3": 1, "u4": 0}, load)
+}
@@ -0,0 +1,52 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestID(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	t.Run("RequestID_EchoedFromClient", func(t *testing.T) {
+		resp := makeRequest(t, "GET", "/health", nil, map[string]string{"X-Request-ID": "client-req-42"})
+		defer resp.Body.Close()
+
+		assert.Equal(t, "client-req-42", resp.Header.Get("X-Request-ID"))
+	})
+
+	t.Run("RequestID_GeneratedWhenMissing", func(t *testing.T) {
+		resp := makeRequest(t, "GET", "/health", nil, nil)
+		defer resp.Body.Close()
+
+		assert.Len(t, resp.Header.Get("X-Request-ID"), 32)
+	})
+
+	t.Run("RequestID_InvalidReplaced", func(t *testing.T) {
+		resp := makeRequest(t, "GET", "/health", nil, map[string]string{"X-Request-ID": "bad id\twith spaces"})
+		defer resp.Body.Close()
+
+		id := resp.Header.Get("X-Request-ID")
+		assert.NotEqual(t, "bad id\twith spaces", id)
+		assert.Len(t, id, 32)
+	})
+
+	t.Run("RequestID_InErrorEnvelope", func(t *testing.T) {
+		cleanupTestData(t)
+
+		resp := makeRequest(t, "GET", "/team/get?team_name=nonexistent", nil, map[string]string{"X-Request-ID": "trace-me"})
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		var result map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		errObj := result["error"].(map[string]interface{})
+		assert.Equal(t, "trace-me", errObj["request_id"])
+	})
+}
@@ -13,6 +13,14 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// testAdminToken токен, с которым тесты обращаются к API
+const testAdminToken = "test-admin-token"
+
+// testUserToken токен пользователя userID; токены настроены для пользователей u1..u12
+func testUserToken(userID string) string {
+	return "test-user-token-" + userID
+}
+
 var (
 	testBaseURL string
 	testDB      *pgxpool.Pool
@@ -23,7 +31,7 @@ func setupTestEnvironment(t *testing.T) (*app.App, func()) {
 	ctx := context.Background()
 
 	// загружаем конфигурацию
-	cfg, err := config.New()
+	cfg, err := config.New("")
 	require.NoError(t, err, "failed to load config")
 
 	// переопределяем хост для подключения к БД
@@ -34,6 +42,17 @@ func setupTestEnvironment(t *testing.T) (*app.App, func()) {
 	// переопределяем порт для тестового сервера
 	cfg.PR.Port = "8081"
 	testBaseURL = "http://localhost:8081"
+	// тесты не ждут вывода из балансировщика при остановке
+	cfg.PR.DrainDelay = 0
+
+	// фиксированные токены, не зависящие от configs/config.yaml
+	cfg.Auth.AdminTokens = []string{testAdminToken}
+	cfg.Auth.UserTokens = map[string]string{}
+	for i := 1; i <= 12; i++ {
+		userID := fmt.Sprintf("u%d", i)
+		cfg.Auth.UserTokens[userID] = testUserToken(userID)
+	}
+	cfg.Auth.JWT = testJWTConfig(t)
 
 	application, err := app.New(ctx, cfg)
 	require.NoError(t, err, "failed to create app")
@@ -92,6 +111,9 @@ func cleanupTestData(t *testing.T) {
 
 	// удаляем данные из всех таблиц
 	queries := []string{
+		"TRUNCATE TABLE team_settings CASCADE",
+		"TRUNCATE TABLE team_fallbacks CASCADE",
+		"TRUNCATE TABLE reviewer_assignment_history CASCADE",
 		"TRUNCATE TABLE pr_reviewers CASCADE",
 		"TRUNCATE TABLE pull_requests CASCADE",
 		"TRUNCATE TABLE users CASCADE",
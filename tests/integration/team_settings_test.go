@@ -0,0 +1,345 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTeamSettingsEndpoints(t *testing.T) {
+	_, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	t.Run("GetTeamSettings_Defaults", func(t *testing.T) {
+		cleanupTestData(t)
+
+		createTestTeam(t, "backend", []map[string]interface{}{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+		})
+
+		// отправляем запрос на получение настроек команды, которые не задавались
+		resp := makeRequest(t, "GET", "/team/settings?team_name=backend", nil, nil)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result map[string]interface{}
+		err := json.NewDecoder(resp.Body).Decode(&result)
+		require.NoError(t, err)
+
+		settings := result["settings"].(map[string]interface{})
+		assert.Equal(t, "backend", settings["team_name"])
+		assert.Equal(t, float64(2), settings["reviewers_count"])
+		assert.Equal(t, "", settings["strategy"])
+		assert.Equal(t, float64(0), settings["max_open_reviews"])
+		assert.Empty(t, settings["fallback_teams"])
+	})
+
+	t.Run("GetTeamSettings_NotFound", func(t *testing.T) {
+		cleanupTestData(t)
+
+		// отправляем запрос на получение настроек несуществующей команды
+		resp := makeRequest(t, "GET", "/team/settings?team_name=nonexistent", nil, nil)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		var result map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&result)
+
+		errObj := result["error"].(map[string]interface{})
+		assert.Equal(t, "NOT_FOUND", errObj["code"])
+	})
+
+	t.Run("UpdateTeamSettings_Success", func(t *testing.T) {
+		cleanupTestData(t)
+
+		createTestTeam(t, "backend", []map[string]interface{}{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+		})
+
+		payload := map[string]interface{}{
+			"team_name":        "backend",
+			"reviewers_count":  3,
+			"strategy":         "least_loaded",
+			"max_open_reviews": 5,
+		}
+
+		// отправляем запрос на изменение настроек команды
+		resp := makeRequest(t, "PUT", "/team/settings", payload, nil)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result map[string]interface{}
+		err := json.NewDecoder(resp.Body).Decode(&result)
+		require.NoError(t, err)
+
+		settings := result["settings"].(map[string]interface{})
+		assert.Equal(t, float64(3), settings["reviewers_count"])
+		assert.Equal(t, "least_loaded", settings["strategy"])
+		assert.Equal(t, float64(5), settings["max_open_reviews"])
+
+		// частичное обновление сохраняет остальные поля
+		resp2 := makeRequest(t, "PUT", "/team/settings", map[string]interface{}{
+			"team_name":       "backend",
+			"reviewers_count": 1,
+		}, nil)
+		defer resp2.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp2.StatusCode)
+
+		var result2 map[string]interface{}
+		json.NewDecoder(resp2.Body).Decode(&result2)
+
+		settings2 := result2["settings"].(map[string]interface{})
+		assert.Equal(t, float64(1), settings2["reviewers_count"])
+		assert.Equal(t, "least_loaded", settings2["strategy"])
+	})
+
+	t.Run("UpdateTeamSettings_InvalidStrategy", func(t *testing.T) {
+		cleanupTestData(t)
+
+		createTestTeam(t, "backend", []map[string]interface{}{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+		})
+
+		payload := map[string]interface{}{
+			"team_name": "backend",
+			"strategy":  "by_seniority",
+		}
+
+		// отправляем запрос с неизвестной стратегией
+		resp := makeRequest(t, "PUT", "/team/settings", payload, nil)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		var result map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&result)
+
+		errObj := result["error"].(map[string]interface{})
+		assert.Equal(t, "INVALID_STRATEGY", errObj["code"])
+	})
+
+	t.Run("CreatePR_HonoursReviewersCount", func(t *testing.T) {
+		cleanupTestData(t)
+
+		createTestTeam(t, "platform", []map[string]interface{}{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+			{"user_id": "u2", "username": "Bob", "is_active": true},
+			{"user_id": "u3", "username": "Charlie", "is_active": true},
+			{"user_id": "u4", "username": "David", "is_active": true},
+			{"user_id": "u5", "username": "Eve", "is_active": true},
+		})
+
+		resp := makeRequest(t, "PUT", "/team/settings", map[string]interface{}{
+			"team_name":       "platform",
+			"reviewers_count": 3,
+		}, nil)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		payload := map[string]interface{}{
+			"pull_request_id":   "pr-1",
+			"pull_request_name": "Add feature",
+			"author_id":         "u1",
+		}
+
+		// отправляем запрос на создание PR в команде, требующей 3 ревьюверов
+		resp = makeRequest(t, "POST", "/pullRequest/create", payload, nil)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var result map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&result)
+
+		pr := result["pr"].(map[string]interface{})
+		reviewers := pr["assigned_reviewers"].([]interface{})
+		assert.Len(t, reviewers, 3, "should assign 3 reviewers")
+		for _, r := range reviewers {
+			assert.NotEqual(t, "u1", r)
+		}
+	})
+
+	t.Run("CreatePR_MaxOpenReviewsSkipsOverloaded", func(t *testing.T) {
+		cleanupTestData(t)
+
+		createTestTeam(t, "backend", []map[string]interface{}{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+			{"user_id": "u2", "username": "Bob", "is_active": true},
+			{"user_id": "u3", "username": "Charlie", "is_active": true},
+		})
+
+		// u2 уже занят одним открытым ревью
+		createTestPR(t, "pr-0", "Existing PR", "u3")
+		assignReviewer(t, "pr-0", "u2")
+
+		resp := makeRequest(t, "PUT", "/team/settings", map[string]interface{}{
+			"team_name":        "backend",
+			"max_open_reviews": 1,
+		}, nil)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		payload := map[string]interface{}{
+			"pull_request_id":   "pr-1",
+			"pull_request_name": "Add feature",
+			"author_id":         "u1",
+		}
+
+		// отправляем запрос на создание PR: u2 превысил лимит и не назначается
+		resp = makeRequest(t, "POST", "/pullRequest/create", payload, nil)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var result map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&result)
+
+		pr := result["pr"].(map[string]interface{})
+		reviewers := pr["assigned_reviewers"].([]interface{})
+		assert.Equal(t, []interface{}{"u3"}, reviewers)
+	})
+	t.Run("UpdateTeamSettings_FallbackTeams", func(t *testing.T) {
+		cleanupTestData(t)
+
+		createTestTeam(t, "small", []map[string]interface{}{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+		})
+		createTestTeam(t, "platform", []map[string]interface{}{
+			{"user_id": "u2", "username": "Bob", "is_active": true},
+		})
+		createTestTeam(t, "infra", []map[string]interface{}{
+			{"user_id": "u3", "username": "Charlie", "is_active": true},
+		})
+
+		payload := map[string]interface{}{
+			"team_name":      "small",
+			"fallback_teams": []string{"infra", "platform"},
+		}
+
+		// отправляем запрос на задание резервных команд
+		resp := makeRequest(t, "PUT", "/team/settings", payload, nil)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&result)
+
+		settings := result["settings"].(map[string]interface{})
+		assert.Equal(t, []interface{}{"infra", "platform"}, settings["fallback_teams"])
+	})
+
+	t.Run("UpdateTeamSettings_InvalidFallbackTeam", func(t *testing.T) {
+		cleanupTestData(t)
+
+		createTestTeam(t, "small", []map[string]interface{}{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+		})
+
+		for _, fallback := range [][]string{{"nonexistent"}, {"small"}} {
+			payload := map[string]interface{}{
+				"team_name":      "small",
+				"fallback_teams": fallback,
+			}
+
+			// отправляем запрос с несуществующей командой и с самой командой в резерве
+			resp := makeRequest(t, "PUT", "/team/settings", payload, nil)
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+			var result map[string]interface{}
+			json.NewDecoder(resp.Body).Decode(&result)
+			resp.Body.Close()
+
+			errObj := result["error"].(map[string]interface{})
+			assert.Equal(t, "INVALID_FALLBACK_TEAM", errObj["code"])
+		}
+	})
+
+	t.Run("CreatePR_TopsUpFromFallbackTeam", func(t *testing.T) {
+		cleanupTestData(t)
+
+		createTestTeam(t, "small", []map[string]interface{}{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+			{"user_id": "u2", "username": "Bob", "is_active": true},
+		})
+		createTestTeam(t, "platform", []map[string]interface{}{
+			{"user_id": "u3", "username": "Charlie", "is_active": true},
+			{"user_id": "u4", "username": "David", "is_active": false},
+		})
+
+		resp := makeRequest(t, "PUT", "/team/settings", map[string]interface{}{
+			"team_name":      "small",
+			"fallback_teams": []string{"platform"},
+		}, nil)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		payload := map[string]interface{}{
+			"pull_request_id":   "pr-1",
+			"pull_request_name": "Add feature",
+			"author_id":         "u1",
+		}
+
+		// отправляем запрос на создание PR: в команде только один кандидат
+		resp = makeRequest(t, "POST", "/pullRequest/create", payload, nil)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		var result map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&result)
+
+		pr := result["pr"].(map[string]interface{})
+		assert.ElementsMatch(t, []interface{}{"u2", "u3"}, pr["assigned_reviewers"])
+		assert.Equal(t, []interface{}{"u3"}, pr["fallback_reviewers"])
+	})
+
+	t.Run("ReassignReviewer_FallsBackInsteadOfNoCandidate", func(t *testing.T) {
+		cleanupTestData(t)
+
+		createTestTeam(t, "small", []map[string]interface{}{
+			{"user_id": "u1", "username": "Alice", "is_active": true},
+			{"user_id": "u2", "username": "Bob", "is_active": true},
+		})
+		createTestTeam(t, "platform", []map[string]interface{}{
+			{"user_id": "u3", "username": "Charlie", "is_active": true},
+		})
+
+		createTestPR(t, "pr-1", "Test PR", "u1")
+		assignReviewer(t, "pr-1", "u2")
+
+		resp := makeRequest(t, "PUT", "/team/settings", map[string]interface{}{
+			"team_name":      "small",
+			"fallback_teams": []string{"platform"},
+		}, nil)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		payload := map[string]interface{}{
+			"pull_request_id": "pr-1",
+			"old_user_id":     "u2",
+		}
+
+		// отправляем запрос на переназначение: в команде u2 замены нет, берем из резервной
+		resp = makeRequest(t, "POST", "/pullRequest/reassign", payload, nil)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&result)
+
+		assert.Equal(t, "u3", result["replaced_by"])
+		pr := result["pr"].(map[string]interface{})
+		assert.Equal(t, []interface{}{"u3"}, pr["assigned_reviewers"])
+		assert.Equal(t, []interface{}{"u3"}, pr["fallback_reviewers"])
+	})
+}
@@ -110,6 +110,40 @@ func TestTeamEndpoints(t *testing.T) {
 		assert.Equal(t, "NOT_FOUND", errObj["code"])
 	})
 
+	t.Run("GetTeam_MissingParam", func(t *testing.T) {
+		cleanupTestData(t)
+
+		// отправляем запрос без team_name
+		resp := makeRequest(t, "GET", "/team/get", nil, nil)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		var result map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&result)
+
+		errObj := result["error"].(map[string]interface{})
+		assert.Equal(t, "INVALID_REQUEST", errObj["code"])
+		assert.Equal(t, "team_name required", errObj["message"])
+	})
+
+	t.Run("CreateTeam_InvalidBody", func(t *testing.T) {
+		cleanupTestData(t)
+
+		// отправляем запрос без обязательного поля members
+		resp := makeRequest(t, "POST", "/team/add", map[string]interface{}{"team_name": "backend"}, nil)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		var result map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&result)
+
+		errObj := result["error"].(map[string]interface{})
+		assert.Equal(t, "INVALID_REQUEST", errObj["code"])
+		assert.NotEmpty(t, errObj["message"])
+	})
+
 	t.Run("CreateTeam_UpdateExistingUser", func(t *testing.T) {
 		cleanupTestData(t)
 
@@ -166,6 +200,8 @@ func makeRequest(t *testing.T, method, path string, body interface{}, headers ma
 	require.NoError(t, err)
 
 	req.Header.Set("Content-Type", "application/json")
+	// по умолчанию запросы выполняются от имени админа; тесты авторизации передают свой заголовок
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
 	for k, v := range headers {
 		req.Header.Set(k, v)
 	}